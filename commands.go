@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sefazor/comfyn/internal/affiliate/link"
+	"github.com/sefazor/comfyn/internal/auth"
+	"github.com/sefazor/comfyn/internal/notification"
+	"github.com/sefazor/comfyn/internal/post"
+	"github.com/sefazor/comfyn/internal/search"
+	"github.com/sefazor/comfyn/internal/seed"
+	"github.com/sefazor/comfyn/internal/timeline"
+	usersvc "github.com/sefazor/comfyn/internal/user"
+	"github.com/sefazor/comfyn/pkg/database"
+	"github.com/sefazor/comfyn/pkg/jobs"
+	"gorm.io/gorm"
+)
+
+const usage = `Usage: comfyn [command] [arguments] [-config file] [-addr address]
+
+Commands:
+  (none)                               start the HTTP server
+  migrate [up | down [n] | status]     apply, revert or list schema migrations
+  seed [demo]                          add default categories, and demo users and posts with "demo"
+  create-admin <username> <email> <full name>
+                                       create an admin account; the password is read from stdin
+  recount                              recompute denormalized counters from source tables
+  reindex                              rebuild post search vectors
+  rebuild-timelines                    rebuild every home timeline from follow relations
+  worker                               process background jobs until SIGINT/SIGTERM
+  jobs [stats | dead | retry <id|all>] show queue counts, list dead jobs or requeue them
+  openapi [check]                      print the OpenAPI document, or fail if a route is undocumented`
+
+// Geçerli komutlar; bilinmeyen komut veritabanına bağlanmadan reddedilir
+var commands = map[string]bool{
+	"migrate":           true,
+	"seed":              true,
+	"create-admin":      true,
+	"recount":           true,
+	"reindex":           true,
+	"rebuild-timelines": true,
+	"worker":            true,
+	"jobs":              true,
+	"openapi":           true,
+}
+
+// Sunucu dışındaki komutları çalıştırır (migrate hariç)
+func runCommand(command string, args []string, db *gorm.DB, timelines *timeline.Service,
+	notifications *notification.Service, queue *jobs.Queue, worker *jobs.Worker) {
+	switch command {
+	case "seed":
+		seedData(db, args, timelines, notifications, queue)
+	case "create-admin":
+		createAdmin(db, args)
+	case "recount":
+		recount(db)
+	case "reindex":
+		reindex(db)
+	case "rebuild-timelines":
+		rebuildTimelines(timelines)
+	case "worker":
+		runWorker(worker)
+	case "jobs":
+		manageJobs(queue, args)
+	}
+}
+
+// Kategorileri ve istenirse demo verisini ekler
+func seedData(db *gorm.DB, args []string, timelines *timeline.Service, notifications *notification.Service, queue *jobs.Queue) {
+	demo := len(args) > 0 && args[0] == "demo"
+	if len(args) > 0 && !demo {
+		log.Fatalf("Unknown seed target %q (use demo or nothing)", args[0])
+	}
+
+	added, err := seed.Categories(db)
+	if err != nil {
+		log.Fatalf("Failed to seed categories: %v", err)
+	}
+	log.Printf("Categories: %d added", added)
+
+	if !demo {
+		return
+	}
+
+	posts := post.NewService(post.NewPostgresStore(db), notifications, timelines, queue)
+	created, err := seed.Demo(db, usersvc.NewPostgresStore(db), posts)
+	if err != nil {
+		log.Fatalf("Failed to seed demo data: %v", err)
+	}
+	if !created {
+		log.Printf("Demo data already exists")
+		return
+	}
+	log.Printf("Demo data created; demo accounts use the password %q", seed.DemoPassword)
+}
+
+// create-admin <username> <email> <full name>; şifre stdin'den okunur
+func createAdmin(db *gorm.DB, args []string) {
+	if len(args) < 3 {
+		log.Fatal("Usage: comfyn create-admin <username> <email> <full name>")
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		log.Fatalf("Failed to read password: %v", err)
+	}
+
+	admin, err := auth.NewService(usersvc.NewPostgresStore(db)).CreateAdmin(auth.RegisterInput{
+		Username: args[0],
+		Email:    args[1],
+		FullName: strings.Join(args[2:], " "),
+		Password: strings.TrimRight(password, "\r\n"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create admin: %v", err)
+	}
+	log.Printf("Created admin %s (id %d)", admin.Username, admin.ID)
+}
+
+// Post, kullanıcı ve link sayaçlarını kaynak tablolardan yeniden hesaplar
+func recount(db *gorm.DB) {
+	fixed, err := post.ReconcileCounters(db)
+	if err != nil {
+		log.Fatalf("Failed to recount post counters: %v", err)
+	}
+	for _, counter := range post.Counters {
+		log.Printf("posts.%s: %d rows updated", counter, fixed[counter])
+	}
+
+	userFixed, err := usersvc.ReconcileCounters(db)
+	if err != nil {
+		log.Fatalf("Failed to recount user counters: %v", err)
+	}
+	for _, counter := range usersvc.Counters {
+		log.Printf("users.%s: %d rows updated", counter, userFixed[counter])
+	}
+
+	clicksFixed, err := link.ReconcileClickCounts(db)
+	if err != nil {
+		log.Fatalf("Failed to recount link clicks: %v", err)
+	}
+	log.Printf("affiliate_links.click_count: %d rows updated", clicksFixed)
+}
+
+// Tüm postların arama vektörlerini yeniden oluşturur
+func reindex(db *gorm.DB) {
+	count, err := search.ReindexPosts(db)
+	if err != nil {
+		log.Fatalf("Failed to reindex posts after %d posts: %v", count, err)
+	}
+	log.Printf("Reindexed %d posts", count)
+}
+
+// Tüm ana sayfa akışlarını takip ilişkilerinden yeniden oluşturur
+func rebuildTimelines(timelines *timeline.Service) {
+	count, err := timelines.RebuildAll(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to rebuild timelines after %d users: %v", count, err)
+	}
+	log.Printf("Rebuilt timelines for %d users", count)
+}
+
+// migrate up | down [adım] | status
+func migrate(db *gorm.DB, args []string) {
+	action := "up"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "up":
+		applied, err := database.MigrateUp(db)
+		for _, m := range applied {
+			log.Printf("Applied %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			log.Printf("Database schema is up to date")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil {
+				log.Fatalf("Invalid step count %q", args[1])
+			}
+			steps = n
+		}
+		reverted, err := database.MigrateDown(db, steps)
+		for _, m := range reverted {
+			log.Printf("Reverted %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "status":
+		statuses, err := database.MigrationStatuses(db)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%-30s %s\n", s.Version, s.Name, state)
+		}
+	default:
+		log.Fatalf("Unknown migrate action %q (use up, down or status)", action)
+	}
+}
+
+// SIGINT/SIGTERM gelene kadar kuyruktaki işleri işler
+func runWorker(worker *jobs.Worker) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := worker.Run(ctx); err != nil {
+		log.Fatalf("Job worker failed: %v", err)
+	}
+}
+
+// jobs stats | dead | retry <id|all>
+func manageJobs(queue *jobs.Queue, args []string) {
+	ctx := context.Background()
+	action := "stats"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "stats":
+		stats, err := queue.Stats(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, status := range []jobs.Status{jobs.StatusPending, jobs.StatusRunning, jobs.StatusDead} {
+			fmt.Printf("%-8s %d\n", status, stats[status])
+		}
+	case "dead":
+		dead, err := queue.DeadJobs(ctx, 50)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, job := range dead {
+			fmt.Printf("%d\t%s\t%d attempts\t%s\t%s\n", job.ID, job.Kind, job.Attempts,
+				job.UpdatedAt.Format(time.RFC3339), job.LastError)
+		}
+	case "retry":
+		if len(args) < 2 {
+			log.Fatal("Usage: comfyn jobs retry <id|all>")
+		}
+		var id int64
+		if args[1] != "all" {
+			n, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				log.Fatalf("Invalid job id %q", args[1])
+			}
+			id = n
+		}
+		count, err := queue.RetryDead(ctx, id)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Requeued %d dead jobs", count)
+	default:
+		log.Fatalf("Unknown jobs action %q (use stats, dead or retry)", action)
+	}
+}
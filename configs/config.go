@@ -1,14 +1,410 @@
 package configs
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
-func LoadEnv() {
-	err := godotenv.Load()
+const defaultConfigFile = ".env"
+
+// Uygulamanın tüm ayarları. Öncelik sırası: varsayılanlar < config dosyası
+// (.env formatında) < ortam değişkenleri < komut satırı flag'leri.
+type Config struct {
+	HTTP       HTTPConfig
+	Database   DatabaseConfig
+	JWT        JWTConfig
+	App        AppConfig
+	Mail       MailConfig
+	Push       PushConfig
+	Digest     DigestConfig
+	Account    AccountConfig
+	Moderation ModerationConfig
+	Export     ExportConfig
+	Timeline   TimelineConfig
+	Jobs       JobsConfig
+	Log        LogConfig
+	Tracing    TracingConfig
+}
+
+type HTTPConfig struct {
+	Addr         string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// Kapanışta süren isteklerin ve arka plan işlerinin bekleneceği en uzun süre
+	ShutdownTimeout time.Duration
+}
+
+type DatabaseConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string
+	SSLMode  string
+	// Bu süreden uzun sorgular uyarı olarak loglanır
+	SlowQueryThreshold time.Duration
+}
+
+// PostgreSQL bağlantı cümlesi
+func (c DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		c.Host, c.User, c.Password, c.Name, c.Port, c.SSLMode)
+}
+
+type JWTConfig struct {
+	Secret string
+}
+
+type AppConfig struct {
+	// E-posta ve indirme linklerinde kullanılan genel adres
+	BaseURL string
+}
+
+type MailConfig struct {
+	SMTPHost     string // boşsa e-postalar sadece loglanır
+	SMTPPort     string
+	SMTPUser     string
+	SMTPPassword string
+	From         string
+}
+
+type PushConfig struct {
+	FCMCredentialsFile string
+	APNSKeyFile        string
+	APNSKeyID          string
+	APNSTeamID         string
+	APNSTopic          string
+	APNSSandbox        bool
+}
+
+type DigestConfig struct {
+	Hour int // özetlerin gönderildiği saat (0-23)
+}
+
+type AccountConfig struct {
+	// Silme talebinden sonra hesabın geri alınabileceği gün sayısı
+	DeletionGraceDays int
+}
+
+type ModerationConfig struct {
+	// Bu sayıda bekleyen rapora ulaşan içerik otomatik gizlenir
+	AutoHideThreshold int
+}
+
+type ExportConfig struct {
+	Dir     string
+	LinkTTL time.Duration
+}
+
+type TimelineConfig struct {
+	// Bu sayıdan fazla takipçisi olan hesapların postları akışlara yazılmaz
+	FanOutMaxFollowers int
+}
+
+type JobsConfig struct {
+	// Aynı anda işlenen en fazla iş sayısı
+	Concurrency  int
+	PollInterval time.Duration
+	// Tek bir işin en uzun süresi; bunun iki katı süredir bitmeyen iş
+	// worker'ı çökmüş sayılarak tekrar denenir
+	JobTimeout time.Duration
+	// false ise işler sadece "comfyn worker" süreçlerinde işlenir
+	RunInServer bool
+}
+
+type LogConfig struct {
+	Level  string // debug, info, warn, error; debug'da tüm SQL sorguları loglanır
+	Format string // json veya text
+}
+
+type TracingConfig struct {
+	// Kapalıyken span'ler oluşturulmaz ve dışarı gönderilmez
+	Enabled bool
+	// OTLP/HTTP toplayıcı adresi (örn. otel-collector:4318)
+	Endpoint string
+	// TLS'siz bağlantı; yerel toplayıcılar için
+	Insecure    bool
+	ServiceName string
+	// Örneklenen trace oranı (0-1); gelen isteğin örnekleme kararına uyulur
+	SampleRatio float64
+}
+
+// Varsayılan değerlerle dolu config
+func Default() *Config {
+	return &Config{
+		HTTP: HTTPConfig{
+			Addr:            ":8080",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    60 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Database:   DatabaseConfig{Port: "5432", SSLMode: "require", SlowQueryThreshold: 200 * time.Millisecond},
+		App:        AppConfig{BaseURL: "https://comfyn.com"},
+		Mail:       MailConfig{SMTPPort: "587"},
+		Digest:     DigestConfig{Hour: 9},
+		Account:    AccountConfig{DeletionGraceDays: 30},
+		Moderation: ModerationConfig{AutoHideThreshold: 5},
+		Export:     ExportConfig{Dir: "exports", LinkTTL: 72 * time.Hour},
+		Timeline:   TimelineConfig{FanOutMaxFollowers: 10000},
+		Jobs: JobsConfig{
+			Concurrency:  4,
+			PollInterval: time.Second,
+			JobTimeout:   10 * time.Minute,
+			RunInServer:  true,
+		},
+		Log:     LogConfig{Level: "info", Format: "json"},
+		Tracing: TracingConfig{Endpoint: "localhost:4318", ServiceName: "comfyn", SampleRatio: 1},
+	}
+}
+
+// Config'i komut satırı argümanları, config dosyası ve ortam değişkenlerinden
+// yükler ve doğrular. -config verilmezse .env dosyası varsa okunur.
+func Load(args []string) (*Config, error) {
+	flags := flag.NewFlagSet("comfyn", flag.ContinueOnError)
+	configFile := flags.String("config", "", "path to a .env style config file")
+	addr := flags.String("addr", "", "HTTP listen address (overrides HTTP_ADDR)")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	values, err := readConfigFile(*configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	if err := cfg.apply(&source{values: values}); err != nil {
+		return nil, err
+	}
+
+	if *addr != "" {
+		cfg.HTTP.Addr = *addr
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Açıkça verilen dosya bulunamazsa hata döner, varsayılan .env ise isteğe bağlıdır
+func readConfigFile(path string) (map[string]string, error) {
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigFile
+	}
+
+	values, err := godotenv.Read(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("read config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func (c *Config) apply(s *source) error {
+	s.string("HTTP_ADDR", &c.HTTP.Addr)
+	s.duration("HTTP_READ_TIMEOUT", &c.HTTP.ReadTimeout)
+	s.duration("HTTP_WRITE_TIMEOUT", &c.HTTP.WriteTimeout)
+	s.duration("HTTP_IDLE_TIMEOUT", &c.HTTP.IdleTimeout)
+	s.duration("HTTP_SHUTDOWN_TIMEOUT", &c.HTTP.ShutdownTimeout)
+
+	s.string("DB_HOST", &c.Database.Host)
+	s.string("DB_PORT", &c.Database.Port)
+	s.string("DB_USER", &c.Database.User)
+	s.string("DB_PASSWORD", &c.Database.Password)
+	s.string("DB_NAME", &c.Database.Name)
+	s.string("DB_SSLMODE", &c.Database.SSLMode)
+	s.duration("DB_SLOW_QUERY_THRESHOLD", &c.Database.SlowQueryThreshold)
+
+	s.string("JWT_SECRET", &c.JWT.Secret)
+	s.string("APP_BASE_URL", &c.App.BaseURL)
+
+	s.string("SMTP_HOST", &c.Mail.SMTPHost)
+	s.string("SMTP_PORT", &c.Mail.SMTPPort)
+	s.string("SMTP_USER", &c.Mail.SMTPUser)
+	s.string("SMTP_PASSWORD", &c.Mail.SMTPPassword)
+	s.string("MAIL_FROM", &c.Mail.From)
+
+	s.string("FCM_CREDENTIALS_FILE", &c.Push.FCMCredentialsFile)
+	s.string("APNS_KEY_FILE", &c.Push.APNSKeyFile)
+	s.string("APNS_KEY_ID", &c.Push.APNSKeyID)
+	s.string("APNS_TEAM_ID", &c.Push.APNSTeamID)
+	s.string("APNS_TOPIC", &c.Push.APNSTopic)
+	s.bool("APNS_SANDBOX", &c.Push.APNSSandbox)
+
+	s.int("DIGEST_HOUR", &c.Digest.Hour)
+	s.int("ACCOUNT_DELETION_GRACE_DAYS", &c.Account.DeletionGraceDays)
+	s.int("REPORT_AUTO_HIDE_THRESHOLD", &c.Moderation.AutoHideThreshold)
+	s.string("EXPORT_DIR", &c.Export.Dir)
+	s.hours("EXPORT_LINK_TTL_HOURS", &c.Export.LinkTTL)
+	s.int("TIMELINE_FANOUT_MAX_FOLLOWERS", &c.Timeline.FanOutMaxFollowers)
+	s.int("JOBS_CONCURRENCY", &c.Jobs.Concurrency)
+	s.duration("JOBS_POLL_INTERVAL", &c.Jobs.PollInterval)
+	s.duration("JOBS_TIMEOUT", &c.Jobs.JobTimeout)
+	s.bool("JOBS_RUN_IN_SERVER", &c.Jobs.RunInServer)
+	s.string("LOG_LEVEL", &c.Log.Level)
+	s.string("LOG_FORMAT", &c.Log.Format)
+	s.bool("TRACING_ENABLED", &c.Tracing.Enabled)
+	s.string("TRACING_ENDPOINT", &c.Tracing.Endpoint)
+	s.bool("TRACING_INSECURE", &c.Tracing.Insecure)
+	s.string("TRACING_SERVICE_NAME", &c.Tracing.ServiceName)
+	s.float("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio)
+
+	return errors.Join(s.errs...)
+}
+
+// Ayarların tutarlılığını kontrol eder
+func (c *Config) Validate() error {
+	var errs []error
+	for _, field := range []struct{ key, value string }{
+		{"DB_HOST", c.Database.Host},
+		{"DB_USER", c.Database.User},
+		{"DB_NAME", c.Database.Name},
+		{"JWT_SECRET", c.JWT.Secret},
+	} {
+		if field.value == "" {
+			errs = append(errs, fmt.Errorf("%s is required", field.key))
+		}
+	}
+
+	if c.HTTP.Addr == "" {
+		errs = append(errs, errors.New("HTTP_ADDR must not be empty"))
+	}
+	for _, field := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"HTTP_READ_TIMEOUT", c.HTTP.ReadTimeout},
+		{"HTTP_WRITE_TIMEOUT", c.HTTP.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", c.HTTP.IdleTimeout},
+		{"HTTP_SHUTDOWN_TIMEOUT", c.HTTP.ShutdownTimeout},
+		{"JOBS_POLL_INTERVAL", c.Jobs.PollInterval},
+		{"JOBS_TIMEOUT", c.Jobs.JobTimeout},
+		{"DB_SLOW_QUERY_THRESHOLD", c.Database.SlowQueryThreshold},
+	} {
+		if field.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive", field.key))
+		}
+	}
+	if !strings.HasPrefix(c.App.BaseURL, "http://") && !strings.HasPrefix(c.App.BaseURL, "https://") {
+		errs = append(errs, errors.New("APP_BASE_URL must be an http(s) URL"))
+	}
+	if c.Digest.Hour < 0 || c.Digest.Hour > 23 {
+		errs = append(errs, errors.New("DIGEST_HOUR must be between 0 and 23"))
+	}
+	if c.Account.DeletionGraceDays < 0 {
+		errs = append(errs, errors.New("ACCOUNT_DELETION_GRACE_DAYS must not be negative"))
+	}
+	if c.Moderation.AutoHideThreshold <= 0 {
+		errs = append(errs, errors.New("REPORT_AUTO_HIDE_THRESHOLD must be positive"))
+	}
+	if c.Export.Dir == "" {
+		errs = append(errs, errors.New("EXPORT_DIR must not be empty"))
+	}
+	if c.Export.LinkTTL <= 0 {
+		errs = append(errs, errors.New("EXPORT_LINK_TTL_HOURS must be positive"))
+	}
+	if c.Jobs.Concurrency <= 0 {
+		errs = append(errs, errors.New("JOBS_CONCURRENCY must be positive"))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, errors.New("TRACING_SAMPLE_RATIO must be between 0 and 1"))
+	}
+	if c.Timeline.FanOutMaxFollowers < 0 {
+		errs = append(errs, errors.New("TIMELINE_FANOUT_MAX_FOLLOWERS must not be negative"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Ortam değişkeni, yoksa config dosyasındaki değer okunur
+type source struct {
+	values map[string]string
+	errs   []error
+}
+
+func (s *source) lookup(key string) (string, bool) {
+	if v, ok := os.LookupEnv(key); ok {
+		return v, true
+	}
+	v, ok := s.values[key]
+	return v, ok
+}
+
+func (s *source) string(key string, target *string) {
+	if v, ok := s.lookup(key); ok && v != "" {
+		*target = v
+	}
+}
+
+func (s *source) int(key string, target *int) {
+	v, ok := s.lookup(key)
+	if !ok || v == "" {
+		return
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("%s: %q is not an integer", key, v))
+		return
+	}
+	*target = n
+}
+
+func (s *source) float(key string, target *float64) {
+	v, ok := s.lookup(key)
+	if !ok || v == "" {
+		return
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("%s: %q is not a number", key, v))
+		return
+	}
+	*target = f
+}
+
+func (s *source) bool(key string, target *bool) {
+	v, ok := s.lookup(key)
+	if !ok || v == "" {
+		return
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("%s: %q is not a boolean", key, v))
+		return
+	}
+	*target = b
+}
+
+func (s *source) hours(key string, target *time.Duration) {
+	hours := int(*target / time.Hour)
+	s.int(key, &hours)
+	*target = time.Duration(hours) * time.Hour
+}
+
+// "30s", "2m" gibi Go süre biçiminde okunur
+func (s *source) duration(key string, target *time.Duration) {
+	v, ok := s.lookup(key)
+	if !ok || v == "" {
+		return
+	}
+	d, err := time.ParseDuration(v)
 	if err != nil {
-		log.Fatal("Error loading .env file")
+		s.errs = append(s.errs, fmt.Errorf("%s: %q is not a duration (e.g. 30s)", key, v))
+		return
 	}
+	*target = d
 }
@@ -0,0 +1,138 @@
+// internal/account/handler.go
+package account
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/apierror"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type ConfirmPasswordInput struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type SuspendUserInput struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+	Days   int    `json:"days" binding:"min=0"` // 0 ise süresiz
+}
+
+type Handler struct {
+	accounts *Service
+}
+
+func NewHandler(accounts *Service) *Handler {
+	return &Handler{accounts: accounts}
+}
+
+// İsteğin context'ine bağlı servis
+func (h *Handler) accountsFor(c *gin.Context) *Service {
+	return h.accounts.WithContext(c.Request.Context())
+}
+
+// Hesabı geçici olarak devre dışı bırakma
+func (h *Handler) DeactivateAccountHandler(c *gin.Context) {
+	currentUser, ok := confirmPassword(c)
+	if !ok {
+		return
+	}
+
+	if err := h.accountsFor(c).Deactivate(currentUser.ID); err != nil {
+		apierror.Internal(c, "Failed to deactivate account")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account deactivated. Log in again to reactivate it."})
+}
+
+// Hesap silme talebi (bekleme süresi içinde giriş yapılırsa iptal olur)
+func (h *Handler) DeleteAccountHandler(c *gin.Context) {
+	currentUser, ok := confirmPassword(c)
+	if !ok {
+		return
+	}
+
+	deleteAt, err := h.accountsFor(c).ScheduleDeletion(currentUser.ID)
+	if err != nil {
+		apierror.Internal(c, "Failed to schedule account deletion")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Account scheduled for deletion. Log in before the deletion date to cancel.",
+		"deleteAt": deleteAt,
+	})
+}
+
+func confirmPassword(c *gin.Context) (models.User, bool) {
+	var input ConfirmPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierror.Validation(c, err)
+		return models.User{}, false
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	if err := bcrypt.CompareHashAndPassword([]byte(currentUser.Password), []byte(input.Password)); err != nil {
+		apierror.Unauthorized(c, "Password is incorrect")
+		return models.User{}, false
+	}
+
+	return currentUser, true
+}
+
+// Moderatörün kullanıcıyı askıya alması
+func (h *Handler) SuspendUserHandler(c *gin.Context) {
+	targetUserID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	var input SuspendUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierror.Validation(c, err)
+		return
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	if err := h.accountsFor(c).Suspend(currentUser.ID, uint(targetUserID), input.Reason, input.Days); err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound):
+			apierror.NotFound(c, "User not found")
+		case errors.Is(err, ErrCannotSuspend):
+			apierror.Forbidden(c, "This user cannot be suspended")
+		default:
+			apierror.Internal(c, "Failed to suspend user")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User suspended successfully"})
+}
+
+// Askıyı kaldırma
+func (h *Handler) UnsuspendUserHandler(c *gin.Context) {
+	targetUserID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	if err := h.accountsFor(c).Unsuspend(currentUser.ID, uint(targetUserID)); err != nil {
+		apierror.Internal(c, "Failed to unsuspend user")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unsuspended successfully"})
+}
@@ -0,0 +1,21 @@
+// internal/account/scheduler.go
+package account
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/sefazor/comfyn/pkg/jobs"
+)
+
+// Süresi dolan hesap silme taleplerini saatte bir işleyen işi worker'a ekler
+func (s *Service) RegisterJobs(w *jobs.Worker) {
+	w.Every("account.deletion", time.Hour, func(ctx context.Context, now time.Time) error {
+		count, err := s.WithContext(ctx).RunDeletionJob(now)
+		if count > 0 {
+			log.Printf("Anonymized %d deleted accounts", count)
+		}
+		return err
+	})
+}
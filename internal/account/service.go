@@ -0,0 +1,184 @@
+// internal/account/service.go
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/sefazor/comfyn/configs"
+	"github.com/sefazor/comfyn/internal/models"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var ErrCannotSuspend = errors.New("this user cannot be suspended")
+
+// Silinen kullanıcının akışlarını ve postlarını temizler
+type Timeline interface {
+	PurgeUser(ctx context.Context, userID uint) error
+}
+
+// Hesap kapatma, silme ve askıya alma işlemleri
+type Service struct {
+	store    Store
+	timeline Timeline
+	// Silme talebinden sonra hesabın geri alınabileceği süre
+	deletionGracePeriod time.Duration
+	// Akış temizliği bu ctx ile yapılır
+	ctx context.Context
+}
+
+func NewService(store Store, cfg configs.AccountConfig, timeline Timeline) *Service {
+	return &Service{
+		store:               store,
+		timeline:            timeline,
+		deletionGracePeriod: time.Duration(cfg.DeletionGraceDays) * 24 * time.Hour,
+		ctx:                 context.Background(),
+	}
+}
+
+// Store sorgularını ctx'e bağlayan kopya
+func (s *Service) WithContext(ctx context.Context) *Service {
+	copy := *s
+	copy.store = s.store.WithContext(ctx)
+	copy.ctx = ctx
+	return &copy
+}
+
+// Kullanıcıyı askıya alır. days sıfırsa süresizdir. Moderatörün kendisi ve
+// yöneticiler askıya alınamaz. Moderasyon işlemleri aynı transaction'da
+// kullanabilsin diye db alır.
+func Suspend(db *gorm.DB, moderatorID, userID uint, reason string, days int) error {
+	var target models.User
+	if err := db.Select("id, role").First(&target, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if target.ID == moderatorID || target.HasRole(models.RoleAdmin) {
+		return ErrCannotSuspend
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"suspended_at":      now,
+		"suspended_until":   nil,
+		"suspension_reason": reason,
+	}
+	if days > 0 {
+		updates["suspended_until"] = now.AddDate(0, 0, days)
+	}
+	return db.Model(&models.User{}).Where("id = ?", userID).Updates(updates).Error
+}
+
+// Moderatörün kullanıcıyı askıya alması; işlem denetim kaydına yazılır
+func (s *Service) Suspend(moderatorID, userID uint, reason string, days int) error {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.Suspend(moderatorID, userID, reason, days); err != nil {
+		return err
+	}
+	if err := tx.RecordAction(&models.ModerationAction{
+		ModeratorID: &moderatorID,
+		TargetType:  models.ReportTargetUser,
+		TargetID:    userID,
+		Action:      models.ModerationSuspend,
+		Note:        reason,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Service) Unsuspend(moderatorID, userID uint) error {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.Unsuspend(userID); err != nil {
+		return err
+	}
+	if err := tx.RecordAction(&models.ModerationAction{
+		ModeratorID: &moderatorID,
+		TargetType:  models.ReportTargetUser,
+		TargetID:    userID,
+		Action:      models.ModerationUnsuspend,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Hesabı gizler; kullanıcı tekrar giriş yapınca hesap aktifleşir
+func (s *Service) Deactivate(userID uint) error {
+	return s.store.Deactivate(userID, time.Now())
+}
+
+// Hesabı devre dışı bırakır ve bekleme süresi sonunda kalıcı silinmek üzere işaretler
+func (s *Service) ScheduleDeletion(userID uint) (time.Time, error) {
+	now := time.Now()
+	err := s.store.ScheduleDeletion(userID, now)
+	return now.Add(s.deletionGracePeriod), err
+}
+
+// Bekleme süresi dolmuş silme taleplerini işler
+func (s *Service) RunDeletionJob(now time.Time) (int, error) {
+	ids, err := s.store.DueForDeletion(now.Add(-s.deletionGracePeriod))
+	if err != nil {
+		return 0, err
+	}
+
+	for i, id := range ids {
+		if err := s.Anonymize(id); err != nil {
+			return i, fmt.Errorf("anonymize user %d: %w", id, err)
+		}
+	}
+	return len(ids), nil
+}
+
+// Kullanıcının kişisel verilerini siler veya anonimleştirir
+func (s *Service) Anonymize(userID uint) error {
+	password, err := unusablePassword()
+	if err != nil {
+		return err
+	}
+
+	exportFiles, err := s.store.Anonymize(userID, password, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := s.timeline.PurgeUser(s.ctx, userID); err != nil {
+		log.Printf("Failed to purge timelines of user %d: %v", userID, err)
+	}
+
+	for _, path := range exportFiles {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to remove export file %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// Hiçbir şifreyle eşleşmeyecek rastgele bir hash üretir
+func unusablePassword() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(buf)), bcrypt.DefaultCost)
+	return string(hash), err
+}
@@ -0,0 +1,215 @@
+// internal/account/store.go
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"gorm.io/gorm"
+)
+
+var ErrNotFound = errors.New("user not found")
+
+// Hesap durumlarının (askı, kapatma, silme) saklandığı yer
+type Store interface {
+	// Transaction başlatır; Tx üzerindeki işlemler Commit'e kadar kalıcı olmaz
+	Begin() (Tx, error)
+	// Sorguları ctx'e bağlı çalıştıran kopya
+	WithContext(ctx context.Context) Store
+
+	// Hedef askıya alınamıyorsa ErrCannotSuspend döner
+	Suspend(moderatorID, userID uint, reason string, days int) error
+	Unsuspend(userID uint) error
+	RecordAction(action *models.ModerationAction) error
+
+	Deactivate(userID uint, at time.Time) error
+	ScheduleDeletion(userID uint, at time.Time) error
+	// Silme talebi before'dan önce yapılmış ve henüz anonimleştirilmemiş hesaplar
+	DueForDeletion(before time.Time) ([]uint, error)
+	// Kişisel verileri siler veya anonimleştirir; diskten kaldırılması
+	// gereken dışa aktarım arşivlerinin yollarını döner
+	Anonymize(userID uint, password string, at time.Time) ([]string, error)
+}
+
+type Tx interface {
+	Store
+	Commit() error
+	Rollback() error
+}
+
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) WithContext(ctx context.Context) Store {
+	return &PostgresStore{db: s.db.WithContext(ctx)}
+}
+
+type postgresTx struct {
+	PostgresStore
+}
+
+func (s *PostgresStore) Begin() (Tx, error) {
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return &postgresTx{PostgresStore{db: tx}}, nil
+}
+
+func (t *postgresTx) Commit() error {
+	return t.db.Commit().Error
+}
+
+func (t *postgresTx) Rollback() error {
+	return t.db.Rollback().Error
+}
+
+func (s *PostgresStore) Suspend(moderatorID, userID uint, reason string, days int) error {
+	return Suspend(s.db, moderatorID, userID, reason, days)
+}
+
+func (s *PostgresStore) Unsuspend(userID uint) error {
+	return s.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"suspended_at":      nil,
+		"suspended_until":   nil,
+		"suspension_reason": "",
+	}).Error
+}
+
+func (s *PostgresStore) RecordAction(action *models.ModerationAction) error {
+	return s.db.Create(action).Error
+}
+
+func (s *PostgresStore) Deactivate(userID uint, at time.Time) error {
+	return s.db.Model(&models.User{}).Where("id = ?", userID).Update("deactivated_at", at).Error
+}
+
+func (s *PostgresStore) ScheduleDeletion(userID uint, at time.Time) error {
+	return s.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"deactivated_at":        at,
+		"deletion_scheduled_at": at,
+	}).Error
+}
+
+func (s *PostgresStore) DueForDeletion(before time.Time) ([]uint, error) {
+	var ids []uint
+	err := s.db.Model(&models.User{}).
+		Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", before).
+		Where("anonymized_at IS NULL").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Affiliate link, işlem ve kazanç kayıtları finansal raporlar için anonim
+// kullanıcıya bağlı kalır
+func (s *PostgresStore) Anonymize(userID uint, password string, now time.Time) ([]string, error) {
+	var exportFiles []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Takip ilişkileri: karşı tarafın sayaçlarını düzelt ve kenarları sil
+		if err := tx.Exec(`UPDATE users SET following_count = GREATEST(following_count - 1, 0)
+			WHERE id IN (SELECT follower_id FROM user_followers WHERE following_id = ?)`, userID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`UPDATE users SET follower_count = GREATEST(follower_count - 1, 0)
+			WHERE id IN (SELECT following_id FROM user_followers WHERE follower_id = ?)`, userID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM user_followers WHERE follower_id = ? OR following_id = ?", userID, userID).Error; err != nil {
+			return err
+		}
+
+		// Başkalarının postlarındaki etkileşimleri sayaçlardan düş
+		for _, counter := range []struct {
+			column string
+			query  string
+		}{
+			{"like_count", "SELECT post_id, COUNT(*) AS n FROM likes WHERE user_id = ? GROUP BY post_id"},
+			{"comment_count", `SELECT post_id, COUNT(*) AS n FROM comments
+				WHERE user_id = ? AND deleted_at IS NULL AND hidden_at IS NULL GROUP BY post_id`},
+			{"save_count", "SELECT post_id, COUNT(*) AS n FROM saved_posts WHERE user_id = ? GROUP BY post_id"},
+		} {
+			if err := tx.Exec(`UPDATE posts SET `+counter.column+` = GREATEST(`+counter.column+` - e.n, 0)
+				FROM (`+counter.query+`) e WHERE posts.id = e.post_id`, userID).Error; err != nil {
+				return err
+			}
+		}
+
+		deletes := []struct {
+			model interface{}
+			query string
+		}{
+			{&models.FollowRequest{}, "requester_id = ? OR target_id = ?"},
+			{&models.UserBlock{}, "blocker_id = ? OR blocked_id = ?"},
+			{&models.UserMute{}, "muter_id = ? OR muted_id = ?"},
+			{&models.Notification{}, "user_id = ? OR actor_id = ?"},
+		}
+		for _, d := range deletes {
+			if err := tx.Unscoped().Where(d.query, userID, userID).Delete(d.model).Error; err != nil {
+				return err
+			}
+		}
+
+		// Dışa aktarım arşivleri kayıtlar silindikten sonra diskten kaldırılır
+		if err := tx.Model(&models.DataExport{}).Where("user_id = ? AND file_path <> ''", userID).
+			Pluck("file_path", &exportFiles).Error; err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{
+			&models.DataExport{},
+			&models.Like{},
+			&models.Comment{},
+			&models.SavedPost{},
+			&models.PostView{},
+			&models.Device{},
+			&models.NotificationPreference{},
+		} {
+			if err := tx.Unscoped().Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		// Postlar affiliate linklerinde referans olduğu için içerikleri temizlenip silinmiş işaretlenir
+		if err := tx.Unscoped().Model(&models.Post{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+			"description": "",
+			"image_url":   "",
+			"deleted_at":  now,
+		}).Error; err != nil {
+			return err
+		}
+
+		// Kullanıcının kendi tıklamalarındaki kimlik bilgilerini anonimleştir
+		if err := tx.Model(&models.ClickLog{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+			"user_id":     nil,
+			"ip":          "",
+			"user_agent":  "",
+			"referer_url": "",
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"full_name":          "Deleted User",
+			"email":              fmt.Sprintf("deleted-%d@users.comfyn.invalid", userID),
+			"username":           fmt.Sprintf("deleted_%d", userID),
+			"password":           password,
+			"profile_image":      "",
+			"biography":          "",
+			"instagram_username": "",
+			"follower_count":     0,
+			"following_count":    0,
+			"total_views":        0,
+			"anonymized_at":      now,
+			"deleted_at":         now,
+		}).Error
+	})
+	return exportFiles, err
+}
@@ -0,0 +1,14 @@
+// internal/affiliate/link/counters.go
+package link
+
+import (
+	"gorm.io/gorm"
+)
+
+// Linklerin tıklanma sayılarını click_logs tablosundan yeniden hesaplar ve
+// düzeltilen link sayısını döner
+func ReconcileClickCounts(db *gorm.DB) (int64, error) {
+	const source = "SELECT COUNT(*) FROM click_logs WHERE click_logs.affiliate_link_id = affiliate_links.id"
+	result := db.Exec("UPDATE affiliate_links SET click_count = (" + source + ") WHERE click_count IS DISTINCT FROM (" + source + ")")
+	return result.RowsAffected, result.Error
+}
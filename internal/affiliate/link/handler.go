@@ -2,20 +2,69 @@
 package link
 
 import (
+	"errors"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sefazor/comfyn/internal/models"
-	"github.com/sefazor/comfyn/pkg/database"
+	"github.com/sefazor/comfyn/pkg/apierror"
+	"github.com/sefazor/comfyn/pkg/metrics"
 )
 
+type LinkResponse struct {
+	ID          uint                   `json:"id"`
+	OriginalURL string                 `json:"originalURL"`
+	TrackingURL string                 `json:"trackingURL"`
+	ClickCount  int                    `json:"clickCount"`
+	Post        models.PostResponse    `json:"post"`
+	Product     models.ProductResponse `json:"product"`
+	CreatedAt   time.Time              `json:"createdAt"`
+}
+
+type ClickStatsResponse struct {
+	TrackingURL     string          `json:"trackingUrl"`
+	OriginalURL     string          `json:"originalUrl"`
+	ProductName     string          `json:"productName"`
+	PostDescription string          `json:"postDescription"`
+	ClickCount      int             `json:"clickCount"`
+	RecentClicks    []ClickResponse `json:"recentClicks"`
+	CreatedAt       time.Time       `json:"createdAt"`
+}
+
+// Link sahibine gösterilen tıklama; tıklayanın IP'si ve hesabı paylaşılmaz
+type ClickResponse struct {
+	ID         uint      `json:"id"`
+	UserAgent  string    `json:"userAgent"`
+	RefererURL string    `json:"refererUrl"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type Handler struct {
+	links *Service
+}
+
+func NewHandler(links *Service) *Handler {
+	return &Handler{links: links}
+}
+
+// İsteğin context'ine bağlı servis
+func (h *Handler) linksFor(c *gin.Context) *Service {
+	return h.links.WithContext(c.Request.Context())
+}
+
 // Link yönlendirme handler'ı
-func RedirectHandler(c *gin.Context) {
+func (h *Handler) RedirectHandler(c *gin.Context) {
 	trackingID := c.Param("tracking_id")
 
-	var link models.AffiliateLink
-	if err := database.DB.Where("tracking_url LIKE ?", "%"+trackingID).First(&link).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+	link, err := h.linksFor(c).FindByTrackingID(trackingID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			apierror.NotFound(c, "Link not found")
+			return
+		}
+		apierror.Internal(c, "Failed to fetch link")
 		return
 	}
 
@@ -26,45 +75,78 @@ func RedirectHandler(c *gin.Context) {
 		userID = &currentUser.ID
 	}
 
-	// Tıklamayı logla
-	go LogClick(
-		link.ID,
-		userID,
-		c.ClientIP(),
-		c.Request.UserAgent(),
-		c.Request.Referer(),
-	)
+	// Tıklamayı logla; kaydedilemese de kullanıcı yönlendirilir
+	if err := h.linksFor(c).LogClick(c.Request.Context(), link.ID, userID, c.ClientIP(), c.Request.UserAgent(), c.Request.Referer()); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to log click", "link_id", link.ID, "error", err)
+	}
+	metrics.LinkRedirects.Inc()
 
 	// Orijinal URL'e yönlendir
 	c.Redirect(http.StatusTemporaryRedirect, link.OriginalURL)
 }
 
 // Analytics handler'ı
-func GetLinkAnalyticsHandler(c *gin.Context) {
+func (h *Handler) GetLinkAnalyticsHandler(c *gin.Context) {
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	var links []models.AffiliateLink
-	if err := database.DB.Where("user_id = ?", currentUser.ID).
-		Preload("Post").
-		Preload("Product").
-		Find(&links).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch links"})
+	links, err := h.linksFor(c).Links(currentUser.ID)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch links")
 		return
 	}
 
-	response := make([]map[string]interface{}, len(links))
+	response := make([]LinkResponse, len(links))
 	for i, link := range links {
-		response[i] = map[string]interface{}{
-			"id":          link.ID,
-			"originalURL": link.OriginalURL,
-			"trackingURL": link.TrackingURL,
-			"clickCount":  link.ClickCount,
-			"post":        link.Post.Response(),
-			"product":     link.Product,
-			"createdAt":   link.CreatedAt,
+		response[i] = LinkResponse{
+			ID:          link.ID,
+			OriginalURL: link.OriginalURL,
+			TrackingURL: link.TrackingURL,
+			ClickCount:  link.ClickCount,
+			Post:        link.Post.Response(),
+			Product:     link.Product.Response(),
+			CreatedAt:   link.CreatedAt,
 		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{"links": response})
 }
+
+// Linklerin tıklanma istatistikleri ve son tıklamalar
+func (h *Handler) GetClickStatsHandler(c *gin.Context) {
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	links, err := h.linksFor(c).ClickStats(currentUser.ID)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch click stats")
+		return
+	}
+
+	stats := make([]ClickStatsResponse, len(links))
+	for i, link := range links {
+		clicks := make([]ClickResponse, len(link.ClickLogs))
+		for j, click := range link.ClickLogs {
+			clicks[j] = ClickResponse{
+				ID:         click.ID,
+				UserAgent:  click.UserAgent,
+				RefererURL: click.RefererURL,
+				CreatedAt:  click.CreatedAt,
+			}
+		}
+		stats[i] = ClickStatsResponse{
+			TrackingURL:     link.TrackingURL,
+			OriginalURL:     link.OriginalURL,
+			ProductName:     link.Product.Name,
+			PostDescription: link.Post.Description,
+			ClickCount:      link.ClickCount,
+			RecentClicks:    clicks,
+			CreatedAt:       link.CreatedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stats":      stats,
+		"totalLinks": len(links),
+	})
+}
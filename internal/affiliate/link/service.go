@@ -2,14 +2,60 @@
 package link
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/sefazor/comfyn/internal/models"
-	"github.com/sefazor/comfyn/pkg/database"
-	"gorm.io/gorm"
+	"github.com/sefazor/comfyn/pkg/jobs"
 )
 
-func GenerateTrackingURL(userID uint, postID uint, productID uint, originalURL string) (string, error) {
+// Link yanıtlarında gösterilen son tıklama sayısı
+const recentClickCount = 10
+
+// Tıklamalar yönlendirmeyi bekletmemek için kuyruk üzerinden yazılır
+var clickJob = jobs.NewKind[clickPayload]("link.click")
+
+type clickPayload struct {
+	LinkID    uint      `json:"linkId"`
+	UserID    *uint     `json:"userId,omitempty"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"userAgent"`
+	Referer   string    `json:"referer"`
+	ClickedAt time.Time `json:"clickedAt"`
+}
+
+type Service struct {
+	store Store
+	queue *jobs.Queue
+}
+
+func NewService(store Store, queue *jobs.Queue) *Service {
+	return &Service{store: store, queue: queue}
+}
+
+// Store sorgularını ctx'e bağlayan kopya
+func (s *Service) WithContext(ctx context.Context) *Service {
+	copy := *s
+	copy.store = s.store.WithContext(ctx)
+	return &copy
+}
+
+// Kuyruktaki tıklamaları kaydeden handler'ı worker'a ekler
+func (s *Service) RegisterJobs(w *jobs.Worker) {
+	jobs.Handle(w, clickJob, func(ctx context.Context, click clickPayload) error {
+		return s.store.RecordClick(&models.ClickLog{
+			AffiliateLinkID: click.LinkID,
+			UserID:          click.UserID,
+			IP:              click.IP,
+			UserAgent:       click.UserAgent,
+			RefererURL:      click.Referer,
+			CreatedAt:       click.ClickedAt,
+		})
+	})
+}
+
+func (s *Service) GenerateTrackingURL(userID uint, postID uint, productID uint, originalURL string) (string, error) {
 	// Benzersiz bir tracking ID oluştur
 	trackingID := fmt.Sprintf("cmf_%d_%d_%d", userID, postID, productID)
 
@@ -25,38 +71,33 @@ func GenerateTrackingURL(userID uint, postID uint, productID uint, originalURL s
 		TrackingURL: trackingURL,
 	}
 
-	if err := database.DB.Create(&link).Error; err != nil {
+	if err := s.store.Create(&link); err != nil {
 		return "", err
 	}
 
 	return trackingURL, nil
 }
 
-func LogClick(linkID uint, userID *uint, ip string, userAgent string, referer string) error {
-	// Tıklama logunu kaydet
-	clickLog := models.ClickLog{
-		AffiliateLinkID: linkID,
-		UserID:          userID,
-		IP:              ip,
-		UserAgent:       userAgent,
-		RefererURL:      referer,
-	}
-
-	tx := database.DB.Begin()
+// Tıklamayı kaydedilmek üzere kuyruğa ekler
+func (s *Service) LogClick(ctx context.Context, linkID uint, userID *uint, ip string, userAgent string, referer string) error {
+	return clickJob.Enqueue(ctx, s.queue, clickPayload{
+		LinkID:    linkID,
+		UserID:    userID,
+		IP:        ip,
+		UserAgent: userAgent,
+		Referer:   referer,
+		ClickedAt: time.Now(),
+	})
+}
 
-	if err := tx.Create(&clickLog).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
+func (s *Service) FindByTrackingID(trackingID string) (*models.AffiliateLink, error) {
+	return s.store.FindByTrackingID(trackingID)
+}
 
-	// Tıklanma sayısını artır
-	if err := tx.Model(&models.AffiliateLink{}).
-		Where("id = ?", linkID).
-		Update("click_count", gorm.Expr("click_count + ?", 1)).
-		Error; err != nil {
-		tx.Rollback()
-		return err
-	}
+func (s *Service) Links(userID uint) ([]models.AffiliateLink, error) {
+	return s.store.ListByUser(userID)
+}
 
-	return tx.Commit().Error
+func (s *Service) ClickStats(userID uint) ([]models.AffiliateLink, error) {
+	return s.store.ListWithRecentClicks(userID, recentClickCount)
 }
@@ -0,0 +1,89 @@
+// internal/affiliate/link/store.go
+package link
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"gorm.io/gorm"
+)
+
+var ErrNotFound = errors.New("link not found")
+
+// Affiliate linklerin ve tıklama loglarının saklandığı yer
+type Store interface {
+	// Sorguları ctx'e bağlı çalıştıran kopya
+	WithContext(ctx context.Context) Store
+
+	FindByTrackingID(trackingID string) (*models.AffiliateLink, error)
+	Create(link *models.AffiliateLink) error
+	// Tıklamayı loglar ve linkin sayacını artırır
+	RecordClick(click *models.ClickLog) error
+	// Kullanıcının linkleri, post ve ürünleriyle
+	ListByUser(userID uint) ([]models.AffiliateLink, error)
+	// Kullanıcının linkleri, her link için en son recentClicks tıklamayla
+	ListWithRecentClicks(userID uint, recentClicks int) ([]models.AffiliateLink, error)
+}
+
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) WithContext(ctx context.Context) Store {
+	return &PostgresStore{db: s.db.WithContext(ctx)}
+}
+
+func (s *PostgresStore) FindByTrackingID(trackingID string) (*models.AffiliateLink, error) {
+	var link models.AffiliateLink
+	if err := s.db.Where("tracking_url LIKE ?", "%"+trackingID).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (s *PostgresStore) Create(link *models.AffiliateLink) error {
+	return s.db.Create(link).Error
+}
+
+func (s *PostgresStore) RecordClick(click *models.ClickLog) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(click).Error; err != nil {
+			return err
+		}
+
+		// Tıklanma sayısını artır
+		return tx.Model(&models.AffiliateLink{}).
+			Where("id = ?", click.AffiliateLinkID).
+			Update("click_count", gorm.Expr("click_count + ?", 1)).
+			Error
+	})
+}
+
+func (s *PostgresStore) ListByUser(userID uint) ([]models.AffiliateLink, error) {
+	var links []models.AffiliateLink
+	err := s.db.Where("user_id = ?", userID).
+		Preload("Post").
+		Preload("Product").
+		Find(&links).Error
+	return links, err
+}
+
+func (s *PostgresStore) ListWithRecentClicks(userID uint, recentClicks int) ([]models.AffiliateLink, error) {
+	var links []models.AffiliateLink
+	err := s.db.Where("user_id = ?", userID).
+		Preload("Product").
+		Preload("Post").
+		Preload("ClickLogs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at DESC").Limit(recentClicks)
+		}).
+		Find(&links).Error
+	return links, err
+}
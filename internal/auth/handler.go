@@ -1,37 +1,62 @@
 package auth
 
 import (
+	"errors"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sefazor/comfyn/pkg/apierror"
 )
 
-func RegisterHandler(c *gin.Context) {
+type Handler struct {
+	auth *Service
+}
+
+func NewHandler(auth *Service) *Handler {
+	return &Handler{auth: auth}
+}
+
+func (h *Handler) RegisterHandler(c *gin.Context) {
 	var input RegisterInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Validation(c, err)
 		return
 	}
 
-	response, err := Register(input)
+	response, err := h.auth.Register(input)
+	if errors.Is(err, ErrAccountExists) {
+		apierror.Abort(c, apierror.New(http.StatusConflict, apierror.CodeAccountExists, "Email or username already exists"))
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		slog.ErrorContext(c.Request.Context(), "Failed to register user", "error", err)
+		apierror.Internal(c, "Failed to create account")
 		return
 	}
 
 	c.JSON(http.StatusCreated, response)
 }
 
-func LoginHandler(c *gin.Context) {
+func (h *Handler) LoginHandler(c *gin.Context) {
 	var input LoginInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Validation(c, err)
 		return
 	}
 
-	response, err := Login(input)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	response, err := h.auth.Login(input)
+	var suspended *SuspendedError
+	switch {
+	case errors.As(err, &suspended):
+		apierror.AccountSuspended(c, suspended.Reason, suspended.Until)
+		return
+	case errors.Is(err, ErrInvalidCredentials):
+		apierror.Abort(c, apierror.New(http.StatusUnauthorized, apierror.CodeInvalidCredentials, "Invalid username or password"))
+		return
+	case err != nil:
+		slog.ErrorContext(c.Request.Context(), "Failed to log in", "error", err)
+		apierror.Internal(c, "Failed to log in")
 		return
 	}
 
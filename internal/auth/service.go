@@ -2,18 +2,76 @@ package auth
 
 import (
 	"errors"
+	"fmt"
+	"net/mail"
+	"time"
 
 	"github.com/sefazor/comfyn/internal/models"
-	"github.com/sefazor/comfyn/pkg/database"
 	"github.com/sefazor/comfyn/pkg/jwt"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func Register(input RegisterInput) (*AuthResponse, error) {
+// Kimlik doğrulamanın ihtiyaç duyduğu kullanıcı işlemleri (user.Store karşılar)
+type UserStore interface {
+	FindByLogin(login string) (*models.User, error)
+	UsernameTaken(username string) (bool, error)
+	EmailTaken(email string) (bool, error)
+	Create(user *models.User) error
+	Reactivate(userID uint) error
+}
+
+type Service struct {
+	users UserStore
+}
+
+func NewService(users UserStore) *Service {
+	return &Service{users: users}
+}
+
+func (s *Service) Register(input RegisterInput) (*AuthResponse, error) {
+	user, err := s.createUser(input, models.RoleUser)
+	if err != nil {
+		return nil, err
+	}
+
+	// JWT token oluştur
+	token, err := jwt.GenerateToken(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthResponse{
+		Token: token,
+		User:  user.SafeResponse(),
+	}, nil
+}
+
+// Yönetici hesabı oluşturur; komut satırından kullanılır
+func (s *Service) CreateAdmin(input RegisterInput) (*models.User, error) {
+	if input.FullName == "" || input.Username == "" || input.Email == "" {
+		return nil, errors.New("full name, username and email are required")
+	}
+	if _, err := mail.ParseAddress(input.Email); err != nil {
+		return nil, fmt.Errorf("invalid email %q", input.Email)
+	}
+	if len(input.Password) < minPasswordLength {
+		return nil, fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	return s.createUser(input, models.RoleAdmin)
+}
+
+func (s *Service) createUser(input RegisterInput, role models.UserRole) (*models.User, error) {
 	// Email ve username kontrolü
-	var existingUser models.User
-	if err := database.DB.Where("email = ? OR username = ?", input.Email, input.Username).First(&existingUser).Error; err == nil {
-		return nil, errors.New("email or username already exists")
+	emailTaken, err := s.users.EmailTaken(input.Email)
+	if err != nil {
+		return nil, err
+	}
+	usernameTaken, err := s.users.UsernameTaken(input.Username)
+	if err != nil {
+		return nil, err
+	}
+	if emailTaken || usernameTaken {
+		return nil, ErrAccountExists
 	}
 
 	// Şifreyi hashle
@@ -29,35 +87,41 @@ func Register(input RegisterInput) (*AuthResponse, error) {
 		Username:     input.Username,
 		Password:     string(hashedPassword),
 		ProfileImage: "https://example.com/default-profile.jpg",
+		Role:         role,
 	}
 
-	if err := database.DB.Create(&user).Error; err != nil {
-		return nil, err
-	}
-
-	// JWT token oluştur
-	token, err := jwt.GenerateToken(user.ID)
-	if err != nil {
+	if err := s.users.Create(&user); err != nil {
 		return nil, err
 	}
-
-	return &AuthResponse{
-		Token: token,
-		User:  user.SafeResponse(),
-	}, nil
+	return &user, nil
 }
 
-func Login(input LoginInput) (*AuthResponse, error) {
-	var user models.User
-
+func (s *Service) Login(input LoginInput) (*AuthResponse, error) {
 	// Kullanıcıyı bul (username veya email ile)
-	if err := database.DB.Where("username = ? OR email = ?", input.Username, input.Username).First(&user).Error; err != nil {
-		return nil, errors.New("invalid credentials")
+	user, err := s.users.FindByLogin(input.Username)
+	if err != nil {
+		return nil, ErrInvalidCredentials
 	}
 
 	// Şifreyi kontrol et
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, ErrInvalidCredentials
+	}
+
+	// Askıdaki hesaplar giriş yapamaz
+	if user.IsSuspended(time.Now()) {
+		return nil, &SuspendedError{Reason: user.SuspensionReason, Until: user.SuspendedUntil}
+	}
+
+	// Devre dışı hesabı ve bekleyen silme talebini geri al
+	reactivated := false
+	if user.DeactivatedAt != nil {
+		if err := s.users.Reactivate(user.ID); err != nil {
+			return nil, err
+		}
+		user.DeactivatedAt = nil
+		user.DeletionScheduledAt = nil
+		reactivated = true
 	}
 
 	// JWT token oluştur
@@ -67,7 +131,8 @@ func Login(input LoginInput) (*AuthResponse, error) {
 	}
 
 	return &AuthResponse{
-		Token: token,
-		User:  user.SafeResponse(),
+		Token:       token,
+		User:        user.SafeResponse(),
+		Reactivated: reactivated,
 	}, nil
 }
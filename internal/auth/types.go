@@ -1,5 +1,15 @@
 package auth
 
+import (
+	"errors"
+	"time"
+
+	"github.com/sefazor/comfyn/internal/models"
+)
+
+// RegisterInput'taki min kuralıyla aynı olmalı
+const minPasswordLength = 6
+
 type RegisterInput struct {
 	FullName string `json:"fullName" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
@@ -13,6 +23,22 @@ type LoginInput struct {
 }
 
 type AuthResponse struct {
-	Token string                 `json:"token"`
-	User  map[string]interface{} `json:"user"`
+	Token       string              `json:"token"`
+	User        models.UserResponse `json:"user"`
+	Reactivated bool                `json:"reactivated,omitempty"`
+}
+
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrAccountExists      = errors.New("email or username already exists")
+)
+
+// Askıya alınmış hesapla giriş denemesinde döner
+type SuspendedError struct {
+	Reason string
+	Until  *time.Time
+}
+
+func (e *SuspendedError) Error() string {
+	return "account suspended"
 }
@@ -0,0 +1,50 @@
+// internal/digest/handler.go
+package digest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/apierror"
+	"github.com/sefazor/comfyn/pkg/jwt"
+)
+
+type Handler struct {
+	store Store
+}
+
+func NewHandler(store Store) *Handler {
+	return &Handler{store: store}
+}
+
+// E-posta linkinden abonelik iptali (giriş gerektirmez).
+// GET tarayıcıdan, POST e-posta istemcisinin tek tıkla iptalinden gelir.
+func (h *Handler) UnsubscribeHandler(c *gin.Context) {
+	kind := c.Query("type")
+	token := c.Query("token")
+
+	var updates map[string]interface{}
+	switch kind {
+	case UnsubscribeDigest:
+		updates = map[string]interface{}{"email_digest": models.DigestOff}
+	case UnsubscribeReport:
+		updates = map[string]interface{}{"weekly_report": false}
+	default:
+		apierror.BadRequest(c, "Invalid unsubscribe type")
+		return
+	}
+
+	userID, err := jwt.ValidateScopedToken(token, "unsubscribe:"+kind)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid unsubscribe link")
+		return
+	}
+
+	if err := h.store.WithContext(c.Request.Context()).UpdatePreferences(userID, updates); err != nil {
+		apierror.Internal(c, "Failed to unsubscribe")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "You have been unsubscribed"})
+}
@@ -0,0 +1,44 @@
+// internal/digest/scheduler.go
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/jobs"
+)
+
+// Her saat başı kontrol eder; ayarlanan saatte (UTC) günlük özetleri,
+// pazartesi günleri ayrıca haftalık özet ve raporları gönderir. Hata
+// dönerse iş tekrar denenir; gönderilmiş alıcılar markSent sayesinde atlanır.
+func (s *Service) RegisterJobs(w *jobs.Worker) {
+	w.Every("digest.run", time.Hour, func(ctx context.Context, now time.Time) error {
+		now = now.UTC()
+		if now.Hour() != s.hour {
+			return nil
+		}
+		return s.WithContext(ctx).run(now)
+	})
+}
+
+// Bir gönderimin hatası diğerlerini engellemez; hatalar birlikte döner
+func (s *Service) run(now time.Time) error {
+	var errs []error
+	if err := s.RunDigests(models.DigestDaily, now); err != nil {
+		errs = append(errs, fmt.Errorf("daily digests: %w", err))
+	}
+
+	if now.Weekday() == time.Monday {
+		if err := s.RunDigests(models.DigestWeekly, now); err != nil {
+			errs = append(errs, fmt.Errorf("weekly digests: %w", err))
+		}
+		if err := s.RunWeeklyReports(now); err != nil {
+			errs = append(errs, fmt.Errorf("weekly reports: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,284 @@
+// internal/digest/service.go
+package digest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/sefazor/comfyn/configs"
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/internal/push"
+	"github.com/sefazor/comfyn/pkg/jwt"
+	"github.com/sefazor/comfyn/pkg/mail"
+)
+
+const (
+	batchSize       = 100
+	maxDigestItems  = 20
+	maxTopPosts     = 3
+	reportPeriod    = 7 * 24 * time.Hour
+	sendTimeout     = 30 * time.Second
+	postTitleLength = 40
+
+	UnsubscribeDigest = "digest"
+	UnsubscribeReport = "report"
+)
+
+type Service struct {
+	store Store
+	// Gönderim saati (UTC)
+	hour int
+	// E-postalardaki linklerin kök adresi
+	appURL string
+	// E-posta gönderimleri bu ctx'e bağlıdır
+	ctx context.Context
+}
+
+func NewService(store Store, cfg configs.DigestConfig, appURL string) *Service {
+	return &Service{store: store, hour: cfg.Hour, appURL: appURL, ctx: context.Background()}
+}
+
+// Store sorgularını ctx'e bağlayan kopya
+func (s *Service) WithContext(ctx context.Context) *Service {
+	copy := *s
+	copy.store = s.store.WithContext(ctx)
+	copy.ctx = ctx
+	return &copy
+}
+
+// Özet gönderilecek kullanıcı
+type recipient struct {
+	ID               uint
+	Email            string
+	Username         string
+	FullName         string
+	LastDigestSentAt *time.Time
+	LastReportSentAt *time.Time
+}
+
+func (r recipient) name() string {
+	if r.FullName != "" {
+		return r.FullName
+	}
+	return r.Username
+}
+
+// Sıklığa göre özet periyodu
+func digestPeriod(frequency models.DigestFrequency) time.Duration {
+	if frequency == models.DigestDaily {
+		return 24 * time.Hour
+	}
+	return 7 * 24 * time.Hour
+}
+
+// Giriş yapmadan çalışan abonelik iptali linki
+func (s *Service) unsubscribeURL(userID uint, kind string) (string, error) {
+	token, err := jwt.GenerateScopedToken(userID, "unsubscribe:"+kind, 0)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/api/email/unsubscribe?type=%s&token=%s", s.appURL, kind, url.QueryEscape(token)), nil
+}
+
+// Alıcıları id sırasına göre sayfa sayfa işler. Bir alıcının hatası
+// diğerlerini durdurmaz; gönderilemeyenlerin hataları birlikte döner.
+func forEachRecipient(filter func(lastID uint) ([]recipient, error), fn func(recipient) error) error {
+	var errs []error
+	var lastID uint
+	for {
+		recipients, err := filter(lastID)
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+
+		for _, r := range recipients {
+			if err := fn(r); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		if len(recipients) < batchSize {
+			return errors.Join(errs...)
+		}
+		lastID = recipients[len(recipients)-1].ID
+	}
+}
+
+// Okunmamış bildirim özetlerini gönderir
+func (s *Service) RunDigests(frequency models.DigestFrequency, now time.Time) error {
+	// Aynı periyotta ikinci kez göndermemek için biraz tolerans bırak
+	minInterval := digestPeriod(frequency) - time.Hour
+
+	return forEachRecipient(func(lastID uint) ([]recipient, error) {
+		return s.store.DigestRecipients(frequency, now.Add(-minInterval), lastID)
+	}, func(r recipient) error {
+		if err := s.sendDigest(r, frequency, now); err != nil {
+			return fmt.Errorf("send digest to user %d: %w", r.ID, err)
+		}
+		return nil
+	})
+}
+
+func (s *Service) sendDigest(r recipient, frequency models.DigestFrequency, now time.Time) error {
+	since := now.Add(-digestPeriod(frequency))
+	if r.LastDigestSentAt != nil && r.LastDigestSentAt.After(since) {
+		since = *r.LastDigestSentAt
+	}
+
+	notifications, total, err := s.store.UnreadNotifications(r.ID, since, maxDigestItems)
+	if err != nil {
+		return err
+	}
+
+	if total > 0 {
+		items := make([]string, 0, len(notifications))
+		for i := range notifications {
+			if msg, ok := push.FormatMessage(&notifications[i], "en"); ok {
+				items = append(items, msg.Body)
+			}
+		}
+
+		unsubscribe, err := s.unsubscribeURL(r.ID, UnsubscribeDigest)
+		if err != nil {
+			return err
+		}
+
+		data := map[string]interface{}{
+			"Name":           r.name(),
+			"Total":          int(total),
+			"Items":          items,
+			"More":           int(total) - len(items),
+			"AppURL":         s.appURL,
+			"UnsubscribeURL": unsubscribe,
+		}
+
+		email, err := render(r.Email, fmt.Sprintf("You have %d unread notifications on Comfyn", total), digestText, digestHTML, data, unsubscribe)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(s.ctx, sendTimeout)
+		defer cancel()
+		if err := mail.Send(ctx, email); err != nil {
+			return err
+		}
+	}
+
+	return s.store.MarkSent(r.ID, "last_digest_sent_at", now)
+}
+
+// Haftalık içerik üreticisi performans raporlarını gönderir
+func (s *Service) RunWeeklyReports(now time.Time) error {
+	minInterval := reportPeriod - time.Hour
+
+	return forEachRecipient(func(lastID uint) ([]recipient, error) {
+		return s.store.ReportRecipients(now.Add(-minInterval), lastID)
+	}, func(r recipient) error {
+		if err := s.sendReport(r, now); err != nil {
+			return fmt.Errorf("send weekly report to user %d: %w", r.ID, err)
+		}
+		return nil
+	})
+}
+
+type postStats struct {
+	PostID      uint
+	Description string
+	Views       int64
+	Likes       int64
+	Clicks      int64
+}
+
+func (s postStats) Title() string {
+	if s.Description == "" {
+		return fmt.Sprintf("Post #%d", s.PostID)
+	}
+	if runes := []rune(s.Description); len(runes) > postTitleLength {
+		return string(runes[:postTitleLength]) + "…"
+	}
+	return s.Description
+}
+
+func (s *Service) sendReport(r recipient, now time.Time) error {
+	since := now.Add(-reportPeriod)
+
+	stats, err := s.store.PostStats(r.ID, since)
+	if err != nil {
+		return err
+	}
+
+	earnings, err := s.store.Earnings(r.ID, since)
+	if err != nil {
+		return err
+	}
+
+	var views, likes, clicks int64
+	for _, stat := range stats {
+		views += stat.Views
+		likes += stat.Likes
+		clicks += stat.Clicks
+	}
+
+	topPosts := stats
+	if len(topPosts) > maxTopPosts {
+		topPosts = topPosts[:maxTopPosts]
+	}
+
+	unsubscribe, err := s.unsubscribeURL(r.ID, UnsubscribeReport)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"Name":           r.name(),
+		"Views":          views,
+		"Likes":          likes,
+		"Clicks":         clicks,
+		"Earnings":       earnings,
+		"TopPosts":       topPosts,
+		"UnsubscribeURL": unsubscribe,
+	}
+
+	email, err := render(r.Email, "Your weekly Comfyn performance report", reportText, reportHTML, data, unsubscribe)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, sendTimeout)
+	defer cancel()
+	if err := mail.Send(ctx, email); err != nil {
+		return err
+	}
+
+	return s.store.MarkSent(r.ID, "last_report_sent_at", now)
+}
+
+type renderer interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+func render(to, subject string, text, html renderer, data interface{}, unsubscribe string) (mail.Email, error) {
+	var textBody, htmlBody bytes.Buffer
+	if err := text.Execute(&textBody, data); err != nil {
+		return mail.Email{}, err
+	}
+	if err := html.Execute(&htmlBody, data); err != nil {
+		return mail.Email{}, err
+	}
+
+	return mail.Email{
+		To:       to,
+		Subject:  subject,
+		TextBody: textBody.String(),
+		HTMLBody: htmlBody.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribe + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
@@ -0,0 +1,132 @@
+// internal/digest/store.go
+package digest
+
+import (
+	"context"
+	"time"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"gorm.io/gorm"
+)
+
+// Özet ve rapor alıcılarının, içeriklerinin ve gönderim zamanlarının okunduğu yer
+type Store interface {
+	// Sorguları ctx'e bağlı çalıştıran kopya
+	WithContext(ctx context.Context) Store
+
+	// Alıcılar id sırasıyla, afterID'den sonraki batchSize kişilik sayfa olarak döner
+	DigestRecipients(frequency models.DigestFrequency, sentBefore time.Time, afterID uint) ([]recipient, error)
+	ReportRecipients(sentBefore time.Time, afterID uint) ([]recipient, error)
+
+	// since'ten beri okunmamış bildirimlerin en yeni limit tanesi ve toplam sayısı
+	UnreadNotifications(userID uint, since time.Time, limit int) ([]models.Notification, int64, error)
+	PostStats(userID uint, since time.Time) ([]postStats, error)
+	Earnings(userID uint, since time.Time) (float64, error)
+
+	// column, notification_preferences üzerindeki gönderim zamanı kolonudur
+	MarkSent(userID uint, column string, at time.Time) error
+	UpdatePreferences(userID uint, updates map[string]interface{}) error
+}
+
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) WithContext(ctx context.Context) Store {
+	return &PostgresStore{db: s.db.WithContext(ctx)}
+}
+
+func (s *PostgresStore) recipientsQuery(afterID uint) *gorm.DB {
+	return s.db.Table("users").
+		Select("users.id, users.email, users.username, users.full_name, np.last_digest_sent_at, np.last_report_sent_at").
+		Joins("LEFT JOIN notification_preferences np ON np.user_id = users.id").
+		Where("users.deleted_at IS NULL").
+		// Kapatılmış ve askıdaki hesaplara e-posta gönderilmez
+		Where("users.deactivated_at IS NULL").
+		Where("(users.suspended_at IS NULL OR users.suspended_until <= NOW())").
+		Where("users.id > ?", afterID).
+		Order("users.id").
+		Limit(batchSize)
+}
+
+func (s *PostgresStore) DigestRecipients(frequency models.DigestFrequency, sentBefore time.Time, afterID uint) ([]recipient, error) {
+	var recipients []recipient
+	err := s.recipientsQuery(afterID).
+		Where("COALESCE(np.email_digest, ?) = ?", models.DigestWeekly, frequency).
+		Where("(np.last_digest_sent_at IS NULL OR np.last_digest_sent_at < ?)", sentBefore).
+		Scan(&recipients).Error
+	return recipients, err
+}
+
+func (s *PostgresStore) ReportRecipients(sentBefore time.Time, afterID uint) ([]recipient, error) {
+	var recipients []recipient
+	err := s.recipientsQuery(afterID).
+		Where("COALESCE(np.weekly_report, ?) = ?", true, true).
+		Where("(np.last_report_sent_at IS NULL OR np.last_report_sent_at < ?)", sentBefore).
+		// Sadece gönderisi olan kullanıcılar
+		Where("EXISTS (SELECT 1 FROM posts p WHERE p.user_id = users.id AND p.deleted_at IS NULL)").
+		Scan(&recipients).Error
+	return recipients, err
+}
+
+func (s *PostgresStore) UnreadNotifications(userID uint, since time.Time, limit int) ([]models.Notification, int64, error) {
+	query := s.db.Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ? AND created_at >= ?", userID, false, since)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	var notifications []models.Notification
+	err := query.Preload("Actor").
+		Preload("Comment").
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, total, err
+}
+
+func (s *PostgresStore) PostStats(userID uint, since time.Time) ([]postStats, error) {
+	var stats []postStats
+	err := s.db.Raw(`
+        SELECT p.id AS post_id, p.description,
+            (SELECT COUNT(*) FROM post_views pv WHERE pv.post_id = p.id AND pv.created_at >= ?) AS views,
+            (SELECT COUNT(*) FROM likes l WHERE l.post_id = p.id AND l.created_at >= ?) AS likes,
+            (SELECT COUNT(*) FROM click_logs cl
+                JOIN affiliate_links al ON al.id = cl.affiliate_link_id
+                WHERE al.post_id = p.id AND cl.created_at >= ?) AS clicks
+        FROM posts p
+        WHERE p.user_id = ? AND p.deleted_at IS NULL
+        ORDER BY views DESC, likes DESC
+    `, since, since, since, userID).Scan(&stats).Error
+	return stats, err
+}
+
+func (s *PostgresStore) Earnings(userID uint, since time.Time) (float64, error) {
+	var earnings float64
+	err := s.db.Model(&models.UserEarning{}).
+		Where("user_id = ? AND created_at >= ? AND status <> ?", userID, since, models.PaymentFailed).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&earnings).Error
+	return earnings, err
+}
+
+func (s *PostgresStore) MarkSent(userID uint, column string, at time.Time) error {
+	return s.UpdatePreferences(userID, map[string]interface{}{column: at})
+}
+
+func (s *PostgresStore) UpdatePreferences(userID uint, updates map[string]interface{}) error {
+	var pref models.NotificationPreference
+	if err := s.db.FirstOrCreate(&pref, models.NotificationPreference{UserID: userID}).Error; err != nil {
+		return err
+	}
+	return s.db.Model(&pref).Updates(updates).Error
+}
@@ -0,0 +1,56 @@
+// internal/digest/templates.go
+package digest
+
+import (
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+var digestText = texttemplate.Must(texttemplate.New("digest").Parse(`Hi {{.Name}},
+
+You have {{.Total}} unread notification(s) on Comfyn:
+{{range .Items}}
+- {{.}}{{end}}
+{{if gt .Total (len .Items)}}
+...and {{.More}} more.
+{{end}}
+Open Comfyn: {{.AppURL}}
+
+Unsubscribe from these emails: {{.UnsubscribeURL}}
+`))
+
+var digestHTML = htmltemplate.Must(htmltemplate.New("digest").Parse(`<p>Hi {{.Name}},</p>
+<p>You have <strong>{{.Total}}</strong> unread notification(s) on Comfyn:</p>
+<ul>{{range .Items}}<li>{{.}}</li>{{end}}</ul>
+{{if gt .Total (len .Items)}}<p>...and {{.More}} more.</p>{{end}}
+<p><a href="{{.AppURL}}">Open Comfyn</a></p>
+<p style="font-size:12px;color:#888"><a href="{{.UnsubscribeURL}}">Unsubscribe</a> from these emails.</p>
+`))
+
+var reportText = texttemplate.Must(texttemplate.New("report").Parse(`Hi {{.Name}},
+
+Here is how your posts performed in the last 7 days:
+
+Views:           {{.Views}}
+Likes:           {{.Likes}}
+Affiliate clicks: {{.Clicks}}
+Earnings:        {{printf "%.2f" .Earnings}}
+{{if .TopPosts}}
+Top posts:{{range .TopPosts}}
+- {{.Title}}: {{.Views}} views, {{.Likes}} likes, {{.Clicks}} clicks{{end}}
+{{end}}
+Unsubscribe from weekly reports: {{.UnsubscribeURL}}
+`))
+
+var reportHTML = htmltemplate.Must(htmltemplate.New("report").Parse(`<p>Hi {{.Name}},</p>
+<p>Here is how your posts performed in the last 7 days:</p>
+<table>
+<tr><td>Views</td><td><strong>{{.Views}}</strong></td></tr>
+<tr><td>Likes</td><td><strong>{{.Likes}}</strong></td></tr>
+<tr><td>Affiliate clicks</td><td><strong>{{.Clicks}}</strong></td></tr>
+<tr><td>Earnings</td><td><strong>{{printf "%.2f" .Earnings}}</strong></td></tr>
+</table>
+{{if .TopPosts}}<p>Top posts:</p>
+<ul>{{range .TopPosts}}<li>{{.Title}}: {{.Views}} views, {{.Likes}} likes, {{.Clicks}} clicks</li>{{end}}</ul>{{end}}
+<p style="font-size:12px;color:#888"><a href="{{.UnsubscribeURL}}">Unsubscribe</a> from weekly reports.</p>
+`))
@@ -0,0 +1,130 @@
+// internal/export/handler.go
+package export
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/apierror"
+	"github.com/sefazor/comfyn/pkg/jwt"
+)
+
+type ExportResponse struct {
+	ID          uint                    `json:"id"`
+	Status      models.DataExportStatus `json:"status"`
+	CreatedAt   time.Time               `json:"createdAt"`
+	CompletedAt *time.Time              `json:"completedAt"`
+	ExpiresAt   *time.Time              `json:"expiresAt"`
+	DownloadURL string                  `json:"downloadUrl,omitempty"` // Yalnızca hazır arşivlerde
+}
+
+// Talep ve indirme linkleri servise, listeleme doğrudan store'a gider
+type Handler struct {
+	exports *Service
+	store   Store
+}
+
+func NewHandler(exports *Service, store Store) *Handler {
+	return &Handler{exports: exports, store: store}
+}
+
+// İsteğin context'ine bağlı store ve servis
+func (h *Handler) storeFor(c *gin.Context) Store {
+	return h.store.WithContext(c.Request.Context())
+}
+
+func (h *Handler) exportsFor(c *gin.Context) *Service {
+	return h.exports.WithContext(c.Request.Context())
+}
+
+func (h *Handler) exportResponse(export *models.DataExport) ExportResponse {
+	resp := ExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+	if export.Status == models.DataExportReady && export.ExpiresAt != nil {
+		if downloadURL, err := h.exports.DownloadURL(export); err == nil {
+			resp.DownloadURL = downloadURL
+		}
+	}
+	return resp
+}
+
+// Kişisel veri dışa aktarım talebi
+func (h *Handler) RequestExportHandler(c *gin.Context) {
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	export, err := h.exportsFor(c).Request(currentUser.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrExportInProgress):
+			apierror.Conflict(c, "An export is already being prepared")
+		case errors.Is(err, ErrTooManyRequests):
+			apierror.TooManyRequests(c, "You can request one export per day")
+		default:
+			apierror.Internal(c, "Failed to request data export")
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Your data export is being prepared. You will be notified when it is ready.",
+		"export":  h.exportResponse(export),
+	})
+}
+
+// Kullanıcının dışa aktarım talepleri
+func (h *Handler) GetExportsHandler(c *gin.Context) {
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	exports, err := h.storeFor(c).UserExports(currentUser.ID, 10)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch data exports")
+		return
+	}
+
+	response := make([]ExportResponse, len(exports))
+	for i := range exports {
+		response[i] = h.exportResponse(&exports[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{"exports": response})
+}
+
+// İmzalı link ile arşiv indirme (giriş gerektirmez)
+func (h *Handler) DownloadExportHandler(c *gin.Context) {
+	exportID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid export ID")
+		return
+	}
+
+	userID, err := jwt.ValidateScopedToken(c.Query("token"), downloadScope(uint(exportID)))
+	if err != nil {
+		apierror.Forbidden(c, "Invalid or expired download link")
+		return
+	}
+
+	export, err := h.storeFor(c).FindUserExport(uint(exportID), userID)
+	if err != nil {
+		apierror.NotFound(c, "Export not found")
+		return
+	}
+
+	if export.Status != models.DataExportReady || export.ExpiresAt == nil || export.ExpiresAt.Before(time.Now()) {
+		apierror.Gone(c, "Export is no longer available")
+		return
+	}
+
+	c.FileAttachment(export.FilePath, fmt.Sprintf("comfyn-data-%s.zip", export.CreatedAt.Format("2006-01-02")))
+}
@@ -0,0 +1,262 @@
+// internal/export/service.go
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sefazor/comfyn/configs"
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/internal/notification"
+	"github.com/sefazor/comfyn/pkg/jobs"
+	"github.com/sefazor/comfyn/pkg/jwt"
+)
+
+const requestCooldown = 24 * time.Hour
+
+var (
+	ErrExportInProgress = errors.New("an export is already in progress")
+	ErrTooManyRequests  = errors.New("an export was already requested in the last 24 hours")
+)
+
+// Arşivler kuyruk üzerinden hazırlanır; iş tekrar denenirken kayıt
+// processing kalır, son deneme de başarısız olursa failed olur
+var runJob = jobs.NewKind[uint]("export.run", jobs.MaxAttempts(3))
+
+type Service struct {
+	store    Store
+	queue    *jobs.Queue
+	notifier notification.Notifier
+	settings configs.ExportConfig
+	// İndirme linklerinin üretildiği adres
+	appURL string
+	// Kuyruğa eklenen işler bu ctx'teki trace'e bağlanır
+	ctx context.Context
+}
+
+func NewService(store Store, queue *jobs.Queue, notifier notification.Notifier, cfg configs.ExportConfig, appURL string) *Service {
+	return &Service{store: store, queue: queue, notifier: notifier, settings: cfg, appURL: appURL, ctx: context.Background()}
+}
+
+// Store sorgularını ctx'e bağlayan kopya
+func (s *Service) WithContext(ctx context.Context) *Service {
+	copy := *s
+	copy.store = s.store.WithContext(ctx)
+	copy.ctx = ctx
+	return &copy
+}
+
+// Arşiv hazırlama ve saatlik temizlik işlerini worker'a ekler
+func (s *Service) RegisterJobs(w *jobs.Worker) {
+	jobs.Handle(w, runJob, func(ctx context.Context, exportID uint) error {
+		exports := s.WithContext(ctx)
+		err := exports.Run(exportID)
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, ErrNotFound):
+			return jobs.Permanent(err)
+		case jobs.IsLastAttempt(ctx):
+			if markErr := exports.markFailed(exportID, err); markErr != nil {
+				log.Printf("Failed to mark export %d as failed: %v", exportID, markErr)
+			}
+		}
+		return err
+	})
+
+	// Süresi dolan arşivleri saatte bir temizler
+	w.Every("export.cleanup", time.Hour, func(ctx context.Context, now time.Time) error {
+		count, err := s.WithContext(ctx).CleanupExpired(now)
+		if count > 0 {
+			log.Printf("Removed %d expired data exports", count)
+		}
+		return err
+	})
+}
+
+func downloadScope(exportID uint) string {
+	return fmt.Sprintf("export:%d", exportID)
+}
+
+// İmzalı ve süreli indirme adresi
+func (s *Service) DownloadURL(export *models.DataExport) (string, error) {
+	ttl := time.Until(*export.ExpiresAt)
+	if ttl <= 0 {
+		return "", errors.New("export has expired")
+	}
+	token, err := jwt.GenerateScopedToken(export.UserID, downloadScope(export.ID), ttl)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/api/exports/%d/download?token=%s", s.appURL, export.ID, url.QueryEscape(token)), nil
+}
+
+// Yeni dışa aktarım talebi oluşturup arka planda hazırlar
+func (s *Service) Request(userID uint) (*models.DataExport, error) {
+	last, err := s.store.LastExport(userID)
+	if err == nil {
+		if last.Status == models.DataExportPending || last.Status == models.DataExportProcessing {
+			return nil, ErrExportInProgress
+		}
+		if last.Status != models.DataExportFailed && time.Since(last.CreatedAt) < requestCooldown {
+			return nil, ErrTooManyRequests
+		}
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	// Kayıt ve iş birlikte oluşur; aksi halde talep sonsuza dek pending kalabilir
+	tx, err := s.store.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	export := models.DataExport{UserID: userID, Status: models.DataExportPending}
+	if err := tx.CreateExport(&export); err != nil {
+		return nil, err
+	}
+	if err := runJob.Enqueue(s.ctx, tx.Jobs(s.queue), export.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+// Arşivi oluşturur, kaydı hazır olarak işaretler ve bildirimi kuyruğa ekler
+func (s *Service) Run(exportID uint) error {
+	export, err := s.store.FindExport(exportID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.UpdateExport(export, map[string]interface{}{"status": models.DataExportProcessing}); err != nil {
+		return err
+	}
+
+	path, err := s.writeArchive(export)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if err := tx.UpdateExport(export, map[string]interface{}{
+		"status":       models.DataExportReady,
+		"file_path":    path,
+		"completed_at": now,
+		"expires_at":   now.Add(s.settings.LinkTTL),
+	}); err != nil {
+		return err
+	}
+	// Bildirim ayrı bir iş olarak oluşturulur; hatası arşivi yeniden hazırlatmaz
+	if err := s.notifier.Notify(s.ctx, tx.Jobs(s.queue), export.UserID, export.UserID, models.NotificationDataExport, nil, nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Deneme hakkı biten talebi kullanıcıya failed olarak gösterir
+func (s *Service) markFailed(exportID uint, cause error) error {
+	export, err := s.store.FindExport(exportID)
+	if err != nil {
+		return err
+	}
+	return s.store.UpdateExport(export, map[string]interface{}{
+		"status": models.DataExportFailed,
+		"error":  cause.Error(),
+	})
+}
+
+func (s *Service) writeArchive(export *models.DataExport) (string, error) {
+	if err := os.MkdirAll(s.settings.Dir, 0o700); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(s.settings.Dir, fmt.Sprintf("export-%d-%d.zip", export.UserID, export.ID))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+
+	zw := zip.NewWriter(file)
+	err = writeFiles(zw, s.store.ArchiveSections(export.UserID))
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	return path, nil
+}
+
+func writeJSON(zw *zip.Writer, name string, v interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Arşivdeki her dosya kullanıcının bir veri grubunu içerir
+func writeFiles(zw *zip.Writer, sections []Section) error {
+	for _, section := range sections {
+		data, err := section.Build()
+		if err != nil {
+			return fmt.Errorf("%s: %w", section.Name, err)
+		}
+		if err := writeJSON(zw, section.Name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Süresi dolan arşivleri diskten siler
+func (s *Service) CleanupExpired(now time.Time) (int, error) {
+	exports, err := s.store.ExpiredExports(now)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for i := range exports {
+		export := &exports[i]
+		if err := os.Remove(export.FilePath); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to remove export file %s: %v", export.FilePath, err)
+			continue
+		}
+		// Dosya silindiyse bir sonraki temizlikte kayıt yine güncellenir
+		if err := s.store.UpdateExport(export, map[string]interface{}{
+			"status":    models.DataExportExpired,
+			"file_path": "",
+		}); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
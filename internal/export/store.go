@@ -0,0 +1,391 @@
+// internal/export/store.go
+package export
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/jobs"
+	"gorm.io/gorm"
+)
+
+var ErrNotFound = errors.New("export not found")
+
+// Arşivdeki bir dosya ve içeriğini üreten sorgu
+type Section struct {
+	Name  string
+	Build func() (interface{}, error)
+}
+
+// Dışa aktarım kayıtlarının ve arşive giren kullanıcı verilerinin okunduğu yer
+type Store interface {
+	// Transaction başlatır; Tx üzerindeki işlemler Commit'e kadar kalıcı olmaz
+	Begin() (Tx, error)
+	// Sorguları ctx'e bağlı çalıştıran kopya
+	WithContext(ctx context.Context) Store
+
+	// Kullanıcının en son talebi; hiç yoksa ErrNotFound
+	LastExport(userID uint) (*models.DataExport, error)
+	FindExport(id uint) (*models.DataExport, error)
+	FindUserExport(id, userID uint) (*models.DataExport, error)
+	UserExports(userID uint, limit int) ([]models.DataExport, error)
+	CreateExport(export *models.DataExport) error
+	UpdateExport(export *models.DataExport, updates map[string]interface{}) error
+	// Linkinin süresi now'dan önce dolmuş hazır arşivler
+	ExpiredExports(now time.Time) ([]models.DataExport, error)
+
+	// Arşive yazılacak dosyalar, yazılma sırasıyla
+	ArchiveSections(userID uint) []Section
+}
+
+type Tx interface {
+	Store
+	// İşleri bu transaction'la birlikte kaydeden kuyruk
+	Jobs(queue *jobs.Queue) *jobs.Queue
+	Commit() error
+	Rollback() error
+}
+
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) WithContext(ctx context.Context) Store {
+	return &PostgresStore{db: s.db.WithContext(ctx)}
+}
+
+type postgresTx struct {
+	PostgresStore
+}
+
+func (s *PostgresStore) Begin() (Tx, error) {
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return &postgresTx{PostgresStore{db: tx}}, nil
+}
+
+func (t *postgresTx) Jobs(queue *jobs.Queue) *jobs.Queue {
+	return queue.WithTx(t.db)
+}
+
+func (t *postgresTx) Commit() error {
+	return t.db.Commit().Error
+}
+
+func (t *postgresTx) Rollback() error {
+	return t.db.Rollback().Error
+}
+
+func firstExport(query *gorm.DB) (*models.DataExport, error) {
+	var export models.DataExport
+	if err := query.First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (s *PostgresStore) LastExport(userID uint) (*models.DataExport, error) {
+	return firstExport(s.db.Where("user_id = ?", userID).Order("created_at DESC"))
+}
+
+func (s *PostgresStore) FindExport(id uint) (*models.DataExport, error) {
+	return firstExport(s.db.Where("id = ?", id))
+}
+
+func (s *PostgresStore) FindUserExport(id, userID uint) (*models.DataExport, error) {
+	return firstExport(s.db.Where("id = ? AND user_id = ?", id, userID))
+}
+
+func (s *PostgresStore) UserExports(userID uint, limit int) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	err := s.db.Where("user_id = ?", userID).Order("created_at DESC").Limit(limit).Find(&exports).Error
+	return exports, err
+}
+
+func (s *PostgresStore) CreateExport(export *models.DataExport) error {
+	return s.db.Create(export).Error
+}
+
+func (s *PostgresStore) UpdateExport(export *models.DataExport, updates map[string]interface{}) error {
+	return s.db.Model(export).Updates(updates).Error
+}
+
+func (s *PostgresStore) ExpiredExports(now time.Time) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	err := s.db.Where("status = ? AND expires_at < ?", models.DataExportReady, now).Find(&exports).Error
+	return exports, err
+}
+
+func (s *PostgresStore) ArchiveSections(userID uint) []Section {
+	sections := []struct {
+		name  string
+		build func(uint) (interface{}, error)
+	}{
+		{"profile.json", s.profileData},
+		{"posts.json", s.postsData},
+		{"comments.json", s.commentsData},
+		{"likes.json", s.likesData},
+		{"saved_posts.json", s.savedPostsData},
+		{"follows.json", s.followsData},
+		{"notifications.json", s.notificationsData},
+		{"affiliate_links.json", s.affiliateLinksData},
+		{"clicks.json", s.clicksData},
+		{"earnings.json", s.earningsData},
+	}
+
+	result := make([]Section, len(sections))
+	for i, section := range sections {
+		build := section.build
+		result[i] = Section{Name: section.name, Build: func() (interface{}, error) { return build(userID) }}
+	}
+	return result
+}
+
+func (s *PostgresStore) profileData(userID uint) (interface{}, error) {
+	var u models.User
+	if err := s.db.First(&u, userID).Error; err != nil {
+		return nil, err
+	}
+
+	var pref models.NotificationPreference
+	s.db.Where("user_id = ?", userID).First(&pref)
+
+	return map[string]interface{}{
+		"id":                u.ID,
+		"fullName":          u.FullName,
+		"email":             u.Email,
+		"username":          u.Username,
+		"profileImage":      u.ProfileImage,
+		"biography":         u.Biography,
+		"instagramUsername": u.InstagramUsername,
+		"isPrivate":         u.IsPrivate,
+		"role":              u.Role,
+		"followerCount":     u.FollowerCount,
+		"followingCount":    u.FollowingCount,
+		"totalViews":        u.TotalViews,
+		"createdAt":         u.CreatedAt,
+		"notificationPreferences": map[string]interface{}{
+			"newFollower":     pref.NewFollower,
+			"postLike":        pref.PostLike,
+			"comment":         pref.Comment,
+			"pushNewFollower": pref.PushNewFollower,
+			"pushPostLike":    pref.PushPostLike,
+			"pushComment":     pref.PushComment,
+			"emailDigest":     pref.EmailDigest,
+			"weeklyReport":    pref.WeeklyReport,
+		},
+	}, nil
+}
+
+func (s *PostgresStore) postsData(userID uint) (interface{}, error) {
+	var posts []models.Post
+	if err := s.db.Preload("User").Preload("Products").Preload("Categories").Preload("Hashtags").
+		Where("user_id = ?", userID).Order("created_at").Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]models.PostResponse, len(posts))
+	for i := range posts {
+		result[i] = posts[i].Response()
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) commentsData(userID uint) (interface{}, error) {
+	var comments []models.Comment
+	if err := s.db.Where("user_id = ?", userID).Order("created_at").Find(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, len(comments))
+	for i, comment := range comments {
+		result[i] = map[string]interface{}{
+			"id":        comment.ID,
+			"postId":    comment.PostID,
+			"content":   comment.Content,
+			"createdAt": comment.CreatedAt,
+			"updatedAt": comment.UpdatedAt,
+		}
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) likesData(userID uint) (interface{}, error) {
+	var likes []models.Like
+	if err := s.db.Where("user_id = ?", userID).Order("created_at").Find(&likes).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, len(likes))
+	for i, like := range likes {
+		result[i] = map[string]interface{}{
+			"postId":    like.PostID,
+			"createdAt": like.CreatedAt,
+		}
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) savedPostsData(userID uint) (interface{}, error) {
+	var saved []models.SavedPost
+	if err := s.db.Where("user_id = ?", userID).Order("created_at").Find(&saved).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, len(saved))
+	for i, s := range saved {
+		result[i] = map[string]interface{}{
+			"postId":    s.PostID,
+			"createdAt": s.CreatedAt,
+		}
+	}
+	return result, nil
+}
+
+type followEntry struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+func (s *PostgresStore) followsData(userID uint) (interface{}, error) {
+	var followers, following []followEntry
+	if err := s.db.Table("users").Select("users.id, users.username").
+		Joins("JOIN user_followers ON user_followers.follower_id = users.id").
+		Where("user_followers.following_id = ?", userID).Scan(&followers).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.Table("users").Select("users.id, users.username").
+		Joins("JOIN user_followers ON user_followers.following_id = users.id").
+		Where("user_followers.follower_id = ?", userID).Scan(&following).Error; err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"followers": followers,
+		"following": following,
+	}, nil
+}
+
+func (s *PostgresStore) notificationsData(userID uint) (interface{}, error) {
+	var notifications []models.Notification
+	if err := s.db.Preload("Actor").Where("user_id = ?", userID).
+		Order("created_at").Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, len(notifications))
+	for i, n := range notifications {
+		result[i] = map[string]interface{}{
+			"type":      n.Type,
+			"actor":     n.Actor.Username,
+			"postId":    n.PostID,
+			"commentId": n.CommentID,
+			"isRead":    n.IsRead,
+			"createdAt": n.CreatedAt,
+		}
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) affiliateLinksData(userID uint) (interface{}, error) {
+	var links []models.AffiliateLink
+	if err := s.db.Where("user_id = ?", userID).Order("created_at").Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, len(links))
+	for i, link := range links {
+		result[i] = map[string]interface{}{
+			"id":          link.ID,
+			"postId":      link.PostID,
+			"productId":   link.ProductID,
+			"originalUrl": link.OriginalURL,
+			"trackingUrl": link.TrackingURL,
+			"clickCount":  link.ClickCount,
+			"createdAt":   link.CreatedAt,
+		}
+	}
+	return result, nil
+}
+
+type dailyClicks struct {
+	AffiliateLinkID uint   `json:"affiliateLinkId"`
+	Day             string `json:"day"`
+	Clicks          int64  `json:"clicks"`
+}
+
+// Kullanıcının linklerine gelen tıklamalar günlük toplam olarak verilir;
+// tıklayanların IP ve tarayıcı bilgileri başkasına ait olduğu için dahil edilmez.
+func (s *PostgresStore) clicksData(userID uint) (interface{}, error) {
+	var daily []dailyClicks
+	if err := s.db.Table("click_logs").
+		Select("click_logs.affiliate_link_id, to_char(date_trunc('day', click_logs.created_at), 'YYYY-MM-DD') AS day, COUNT(*) AS clicks").
+		Joins("JOIN affiliate_links ON affiliate_links.id = click_logs.affiliate_link_id").
+		Where("affiliate_links.user_id = ?", userID).
+		Group("click_logs.affiliate_link_id, day").
+		Order("day, click_logs.affiliate_link_id").
+		Scan(&daily).Error; err != nil {
+		return nil, err
+	}
+
+	// Kullanıcının kendi yaptığı tıklamalar
+	var own []models.ClickLog
+	if err := s.db.Where("user_id = ?", userID).Order("created_at").Find(&own).Error; err != nil {
+		return nil, err
+	}
+
+	ownClicks := make([]map[string]interface{}, len(own))
+	for i, click := range own {
+		ownClicks[i] = map[string]interface{}{
+			"affiliateLinkId": click.AffiliateLinkID,
+			"ip":              click.IP,
+			"userAgent":       click.UserAgent,
+			"refererUrl":      click.RefererURL,
+			"createdAt":       click.CreatedAt,
+		}
+	}
+
+	return map[string]interface{}{
+		"dailyLinkClicks": daily,
+		"yourClicks":      ownClicks,
+	}, nil
+}
+
+func (s *PostgresStore) earningsData(userID uint) (interface{}, error) {
+	var earnings []models.UserEarning
+	if err := s.db.Preload("Transaction").Where("user_id = ?", userID).
+		Order("created_at").Find(&earnings).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, len(earnings))
+	for i, earning := range earnings {
+		result[i] = map[string]interface{}{
+			"id":          earning.ID,
+			"amount":      earning.Amount,
+			"status":      earning.Status,
+			"paymentDate": earning.PaymentDate,
+			"createdAt":   earning.CreatedAt,
+			"transaction": map[string]interface{}{
+				"orderId":         earning.Transaction.OrderID,
+				"linkId":          earning.Transaction.LinkID,
+				"amount":          earning.Transaction.Amount,
+				"commission":      earning.Transaction.Commission,
+				"status":          earning.Transaction.Status,
+				"transactionDate": earning.Transaction.TransactionDate,
+			},
+		}
+	}
+	return result, nil
+}
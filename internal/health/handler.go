@@ -0,0 +1,66 @@
+// internal/health/handler.go
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const pingTimeout = 2 * time.Second
+
+// Load balancer ve orkestratörün yoklama uçları
+type Handler struct {
+	db       *gorm.DB
+	draining atomic.Bool
+}
+
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// Kapanış başladığında çağrılır; readyz bundan sonra 503 döner ki
+// load balancer yeni istek göndermeyi bıraksın
+func (h *Handler) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Süreç ayakta ve veritabanına ulaşabiliyor mu
+func (h *Handler) LivenessHandler(c *gin.Context) {
+	if err := h.ping(c.Request.Context()); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Health check failed", "error", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "database": "unreachable"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "database": "ok"})
+}
+
+// Sunucu trafik almaya hazır mı; kapanırken veya veritabanı yokken hayır
+func (h *Handler) ReadinessHandler(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+	if err := h.ping(c.Request.Context()); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Readiness check failed", "error", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "database": "unreachable"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready", "database": "ok"})
+}
+
+func (h *Handler) ping(ctx context.Context) error {
+	sqlDB, err := h.db.DB()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
@@ -14,7 +14,34 @@ type Comment struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
+	HiddenAt  *time.Time     // Moderasyon ile gizlendi
 
 	User User `gorm:"foreignkey:UserID"`
 	Post Post `gorm:"foreignkey:PostID"`
 }
+
+type CommentResponse struct {
+	ID        uint         `json:"id"`
+	PostID    uint         `json:"postId"`
+	Content   string       `json:"content"`
+	User      UserResponse `json:"user"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+func (comment *Comment) Response() CommentResponse {
+	return CommentResponse{
+		ID:        comment.ID,
+		PostID:    comment.PostID,
+		Content:   comment.Content,
+		User:      comment.User.SafeResponse(),
+		CreatedAt: comment.CreatedAt,
+	}
+}
+
+func CommentResponses(comments []Comment) []CommentResponse {
+	response := make([]CommentResponse, len(comments))
+	for i := range comments {
+		response[i] = comments[i].Response()
+	}
+	return response
+}
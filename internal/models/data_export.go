@@ -0,0 +1,31 @@
+// internal/models/data_export.go
+package models
+
+import (
+	"time"
+)
+
+type DataExportStatus string
+
+const (
+	DataExportPending    DataExportStatus = "pending"
+	DataExportProcessing DataExportStatus = "processing"
+	DataExportReady      DataExportStatus = "ready"
+	DataExportFailed     DataExportStatus = "failed"
+	DataExportExpired    DataExportStatus = "expired"
+)
+
+// Kullanıcının kişisel veri dışa aktarım talebi (GDPR veri taşınabilirliği)
+type DataExport struct {
+	ID          uint             `gorm:"primaryKey"`
+	UserID      uint             `gorm:"not null;index"`
+	Status      DataExportStatus `gorm:"size:20;not null;default:'pending'"`
+	FilePath    string           `gorm:"size:255"`
+	Error       string           `gorm:"size:500"`
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	User User `gorm:"foreignkey:UserID"`
+}
@@ -0,0 +1,26 @@
+// internal/models/device.go
+package models
+
+import (
+	"time"
+)
+
+type DevicePlatform string
+
+const (
+	DevicePlatformIOS     DevicePlatform = "ios"
+	DevicePlatformAndroid DevicePlatform = "android"
+)
+
+type Device struct {
+	ID         uint           `gorm:"primaryKey"`
+	UserID     uint           `gorm:"not null;index"`
+	Token      string         `gorm:"size:255;not null;uniqueIndex"`
+	Platform   DevicePlatform `gorm:"size:20;not null"`
+	Locale     string         `gorm:"size:10;default:'tr'"` // Push mesajlarının dili
+	LastSeenAt time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	User User `gorm:"foreignkey:UserID"`
+}
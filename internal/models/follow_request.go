@@ -0,0 +1,17 @@
+// internal/models/follow_request.go
+package models
+
+import (
+	"time"
+)
+
+// Gizli hesaplara gönderilen, onay bekleyen takip istekleri
+type FollowRequest struct {
+	ID          uint `gorm:"primaryKey"`
+	RequesterID uint `gorm:"not null;uniqueIndex:idx_follow_requests_pair"`
+	TargetID    uint `gorm:"not null;uniqueIndex:idx_follow_requests_pair;index"`
+	CreatedAt   time.Time
+
+	Requester User `gorm:"foreignkey:RequesterID"`
+	Target    User `gorm:"foreignkey:TargetID"`
+}
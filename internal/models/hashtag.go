@@ -22,3 +22,12 @@ func NormalizeHashtag(tag string) string {
 	// Boşlukları kaldır
 	return strings.ReplaceAll(tag, " ", "")
 }
+
+type HashtagResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func (hashtag *Hashtag) Response() HashtagResponse {
+	return HashtagResponse{ID: hashtag.ID, Name: hashtag.Name}
+}
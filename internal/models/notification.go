@@ -9,9 +9,19 @@ import (
 type NotificationType string
 
 const (
-	NotificationNewFollower NotificationType = "new_follower"
-	NotificationPostLike    NotificationType = "post_like"
-	NotificationComment     NotificationType = "comment"
+	NotificationNewFollower   NotificationType = "new_follower"
+	NotificationPostLike      NotificationType = "post_like"
+	NotificationComment       NotificationType = "comment"
+	NotificationFollowRequest NotificationType = "follow_request"
+	NotificationDataExport    NotificationType = "data_export_ready"
+)
+
+type DigestFrequency string
+
+const (
+	DigestOff    DigestFrequency = "off"
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
 )
 
 type Notification struct {
@@ -38,32 +48,97 @@ type NotificationPreference struct {
 	NewFollower bool `gorm:"default:true"`
 	PostLike    bool `gorm:"default:true"`
 	Comment     bool `gorm:"default:true"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+
+	// Push bildirimi tercihleri (uygulama içi bildirimlerden bağımsız)
+	PushNewFollower bool `gorm:"default:true"`
+	PushPostLike    bool `gorm:"default:true"`
+	PushComment     bool `gorm:"default:true"`
+
+	// E-posta özeti ve haftalık performans raporu
+	EmailDigest      DigestFrequency `gorm:"size:10;default:'weekly'"`
+	WeeklyReport     bool            `gorm:"default:true"`
+	LastDigestSentAt *time.Time
+	LastReportSentAt *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
 
 	User User `gorm:"foreignkey:UserID"`
 }
 
-func (n *Notification) Response() map[string]interface{} {
-	resp := map[string]interface{}{
-		"id":        n.ID,
-		"type":      n.Type,
-		"isRead":    n.IsRead,
-		"createdAt": n.CreatedAt,
-		"actor":     n.Actor.SafeResponse(),
+type NotificationPreferenceResponse struct {
+	NewFollower     bool            `json:"newFollower"`
+	PostLike        bool            `json:"postLike"`
+	Comment         bool            `json:"comment"`
+	PushNewFollower bool            `json:"pushNewFollower"`
+	PushPostLike    bool            `json:"pushPostLike"`
+	PushComment     bool            `json:"pushComment"`
+	EmailDigest     DigestFrequency `json:"emailDigest"`
+	WeeklyReport    bool            `json:"weeklyReport"`
+}
+
+func (p *NotificationPreference) Response() NotificationPreferenceResponse {
+	return NotificationPreferenceResponse{
+		NewFollower:     p.NewFollower,
+		PostLike:        p.PostLike,
+		Comment:         p.Comment,
+		PushNewFollower: p.PushNewFollower,
+		PushPostLike:    p.PushPostLike,
+		PushComment:     p.PushComment,
+		EmailDigest:     p.EmailDigest,
+		WeeklyReport:    p.WeeklyReport,
+	}
+}
+
+// Bildirim tipi için push gönderilip gönderilmeyeceğini döner
+func (p *NotificationPreference) PushEnabled(notificationType NotificationType) bool {
+	switch notificationType {
+	case NotificationNewFollower, NotificationFollowRequest:
+		return p.PushNewFollower
+	case NotificationPostLike:
+		return p.PushPostLike
+	case NotificationComment:
+		return p.PushComment
+	case NotificationDataExport:
+		return true
+	}
+	return false
+}
+
+type NotificationResponse struct {
+	ID        uint             `json:"id"`
+	Type      NotificationType `json:"type"`
+	IsRead    bool             `json:"isRead"`
+	CreatedAt time.Time        `json:"createdAt"`
+	Actor     UserResponse     `json:"actor"`
+	// Beğeni ve yorum bildirimlerinde dolu
+	Post    *PostResponse    `json:"post,omitempty"`
+	Comment *CommentResponse `json:"comment,omitempty"`
+}
+
+func (n *Notification) Response() NotificationResponse {
+	resp := NotificationResponse{
+		ID:        n.ID,
+		Type:      n.Type,
+		IsRead:    n.IsRead,
+		CreatedAt: n.CreatedAt,
+		Actor:     n.Actor.SafeResponse(),
 	}
 
 	switch n.Type {
 	case NotificationPostLike:
 		if n.Post != nil {
-			resp["post"] = n.Post.Response()
+			post := n.Post.Response()
+			resp.Post = &post
 		}
 	case NotificationComment:
 		if n.Post != nil {
-			resp["post"] = n.Post.Response()
+			post := n.Post.Response()
+			resp.Post = &post
 		}
 		if n.Comment != nil {
-			resp["comment"] = n.Comment
+			comment := n.Comment.Response()
+			resp.Comment = &comment
 		}
 	}
 
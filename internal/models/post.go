@@ -14,6 +14,7 @@ type Post struct {
 	ImageURL    string     `gorm:"not null"`
 	Description string     `gorm:"type:text"`
 	ViewCount   int        `gorm:"default:0"`
+	HiddenAt    *time.Time `gorm:"index"` // Moderasyon ile gizlendi
 	Products    []Product  `gorm:"many2many:post_products;"`
 	Categories  []Category `gorm:"many2many:post_categories;"`
 	Hashtags    []Hashtag  `gorm:"many2many:post_hashtags;"`
@@ -23,31 +24,60 @@ type Post struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	DeletedAt   gorm.DeletedAt `gorm:"index"`
+
+	// Etkileşim sayaçları, beğeni/yorum/kaydetme işlemleriyle aynı transaction'da güncellenir
+	LikeCount    int `gorm:"not null;default:0"`
+	CommentCount int `gorm:"not null;default:0"`
+	SaveCount    int `gorm:"not null;default:0"`
+
+	// Takipçi akışlarına yazıldı; yazılmayan postlar akış okunurken çekilir
+	FannedOut bool `gorm:"not null;default:false"`
+
+	// Tam metin arama vektörü, search paketi tarafından güncellenir
+	SearchVector string `gorm:"type:tsvector;->:false;<-:false"`
 }
 
-func (post *Post) Response() map[string]interface{} {
-	productsResponse := make([]map[string]interface{}, len(post.Products))
-	for i, product := range post.Products {
-		productsResponse[i] = map[string]interface{}{
-			"id":          product.ID,
-			"name":        product.Name,
-			"price":       product.Price,
-			"link":        product.Link,
-			"trackingUrl": product.TrackingURL,
-			"description": product.Description,
-		}
-	}
+type PostResponse struct {
+	ID           uint               `json:"id"`
+	User         UserResponse       `json:"user"`
+	ImageURL     string             `json:"imageUrl"`
+	Description  string             `json:"description"`
+	Products     []ProductResponse  `json:"products"`
+	Categories   []CategoryResponse `json:"categories"`
+	Hashtags     []HashtagResponse  `json:"hashtags"`
+	LikeCount    int                `json:"likeCount"`
+	CommentCount int                `json:"commentCount"`
+	SaveCount    int                `json:"saveCount"`
+	CreatedAt    time.Time          `json:"createdAt"`
 
-	return map[string]interface{}{
-		"id":           post.ID,
-		"user":         post.User.SafeResponse(),
-		"imageUrl":     post.ImageURL,
-		"description":  post.Description,
-		"products":     productsResponse,
-		"categories":   post.Categories,
-		"hashtags":     post.Hashtags,
-		"likeCount":    len(post.Likes),
-		"commentCount": len(post.Comments),
-		"createdAt":    post.CreatedAt,
+	// İzleyiciye özel durumlar; sadece izleyici bilindiğinde doldurulur
+	LikedByMe       *bool `json:"likedByMe,omitempty"`
+	SavedByMe       *bool `json:"savedByMe,omitempty"`
+	FollowingAuthor *bool `json:"followingAuthor,omitempty"`
+}
+
+func (post *Post) Response() PostResponse {
+	response := PostResponse{
+		ID:           post.ID,
+		User:         post.User.SafeResponse(),
+		ImageURL:     post.ImageURL,
+		Description:  post.Description,
+		Products:     make([]ProductResponse, len(post.Products)),
+		Categories:   make([]CategoryResponse, len(post.Categories)),
+		Hashtags:     make([]HashtagResponse, len(post.Hashtags)),
+		LikeCount:    post.LikeCount,
+		CommentCount: post.CommentCount,
+		SaveCount:    post.SaveCount,
+		CreatedAt:    post.CreatedAt,
+	}
+	for i := range post.Products {
+		response.Products[i] = post.Products[i].Response()
+	}
+	for i := range post.Categories {
+		response.Categories[i] = post.Categories[i].Response()
+	}
+	for i := range post.Hashtags {
+		response.Hashtags[i] = post.Hashtags[i].Response()
 	}
+	return response
 }
@@ -26,3 +26,23 @@ func (p *Product) GenerateTrackingURL(userID uint, postID uint) {
 	trackingID := fmt.Sprintf("cmf_%d_%d_%d", userID, postID, p.ID)
 	p.TrackingURL = fmt.Sprintf("http://localhost:8080/go/%s", trackingID)
 }
+
+type ProductResponse struct {
+	ID          uint    `json:"id"`
+	Name        string  `json:"name"`
+	Price       float64 `json:"price"`
+	Link        string  `json:"link"`
+	TrackingURL string  `json:"trackingUrl"`
+	Description string  `json:"description"`
+}
+
+func (p *Product) Response() ProductResponse {
+	return ProductResponse{
+		ID:          p.ID,
+		Name:        p.Name,
+		Price:       p.Price,
+		Link:        p.Link,
+		TrackingURL: p.TrackingURL,
+		Description: p.Description,
+	}
+}
@@ -0,0 +1,75 @@
+// internal/models/report.go
+package models
+
+import (
+	"time"
+)
+
+type ReportTargetType string
+
+const (
+	ReportTargetPost    ReportTargetType = "post"
+	ReportTargetComment ReportTargetType = "comment"
+	ReportTargetUser    ReportTargetType = "user"
+)
+
+type ReportReason string
+
+const (
+	ReportSpam       ReportReason = "spam"
+	ReportHarassment ReportReason = "harassment"
+	ReportNudity     ReportReason = "nudity"
+	ReportViolence   ReportReason = "violence"
+	ReportHate       ReportReason = "hate"
+	ReportOther      ReportReason = "other"
+)
+
+type ReportStatus string
+
+const (
+	ReportPending   ReportStatus = "pending"
+	ReportDismissed ReportStatus = "dismissed"
+	ReportActioned  ReportStatus = "actioned"
+)
+
+// Bir kullanıcı aynı içeriği sadece bir kez raporlayabilir
+type Report struct {
+	ID           uint             `gorm:"primaryKey"`
+	ReporterID   uint             `gorm:"not null;uniqueIndex:idx_reports_reporter_target"`
+	TargetType   ReportTargetType `gorm:"size:20;not null;uniqueIndex:idx_reports_reporter_target;index:idx_reports_target"`
+	TargetID     uint             `gorm:"not null;uniqueIndex:idx_reports_reporter_target;index:idx_reports_target"`
+	Reason       ReportReason     `gorm:"size:20;not null"`
+	Details      string           `gorm:"size:1000"`
+	Status       ReportStatus     `gorm:"size:20;not null;default:'pending';index"`
+	ResolvedByID *uint
+	ResolvedAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	Reporter   User  `gorm:"foreignkey:ReporterID"`
+	ResolvedBy *User `gorm:"foreignkey:ResolvedByID"`
+}
+
+type ModerationActionType string
+
+const (
+	ModerationDismiss   ModerationActionType = "dismiss"
+	ModerationHide      ModerationActionType = "hide"
+	ModerationDelete    ModerationActionType = "delete"
+	ModerationSuspend   ModerationActionType = "suspend"
+	ModerationAutoHide  ModerationActionType = "auto_hide"
+	ModerationUnsuspend ModerationActionType = "unsuspend"
+)
+
+// Moderatör işlemlerinin denetim kaydı. Otomatik işlemlerde ModeratorID boştur.
+type ModerationAction struct {
+	ID          uint                 `gorm:"primaryKey"`
+	ModeratorID *uint                `gorm:"index"`
+	TargetType  ReportTargetType     `gorm:"size:20;not null;index:idx_moderation_actions_target"`
+	TargetID    uint                 `gorm:"not null;index:idx_moderation_actions_target"`
+	Action      ModerationActionType `gorm:"size:20;not null"`
+	Note        string               `gorm:"size:1000"`
+	CreatedAt   time.Time
+
+	Moderator *User `gorm:"foreignkey:ModeratorID"`
+}
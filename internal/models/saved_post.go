@@ -0,0 +1,17 @@
+// internal/models/saved_post.go
+package models
+
+import (
+	"time"
+)
+
+// Kullanıcının daha sonra bakmak için kaydettiği post
+type SavedPost struct {
+	ID        uint `gorm:"primaryKey"`
+	UserID    uint `gorm:"not null;uniqueIndex:idx_saved_posts_user_post"`
+	PostID    uint `gorm:"not null;uniqueIndex:idx_saved_posts_user_post;index"`
+	CreatedAt time.Time
+
+	User User `gorm:"foreignkey:UserID"`
+	Post Post `gorm:"foreignkey:PostID"`
+}
@@ -0,0 +1,14 @@
+// internal/models/timeline_entry.go
+package models
+
+import (
+	"time"
+)
+
+// Takip edilen üreticinin postunun takipçinin ana sayfa akışına yazılmış kaydı
+type TimelineEntry struct {
+	OwnerID       uint      `gorm:"primaryKey;autoIncrement:false;index:idx_timeline_entries_owner_time,priority:1;index:idx_timeline_entries_owner_author,priority:1"`
+	PostID        uint      `gorm:"primaryKey;autoIncrement:false;index;index:idx_timeline_entries_owner_time,priority:3,sort:desc"`
+	AuthorID      uint      `gorm:"not null;index:idx_timeline_entries_owner_author,priority:2"`
+	PostCreatedAt time.Time `gorm:"not null;index:idx_timeline_entries_owner_time,priority:2,sort:desc"`
+}
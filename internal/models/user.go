@@ -6,6 +6,14 @@ import (
 	"gorm.io/gorm"
 )
 
+type UserRole string
+
+const (
+	RoleUser      UserRole = "user"
+	RoleModerator UserRole = "moderator"
+	RoleAdmin     UserRole = "admin"
+)
+
 type User struct {
 	ID                uint   `gorm:"primaryKey"`
 	FullName          string `gorm:"size:100;not null"`
@@ -18,26 +26,82 @@ type User struct {
 	FollowerCount     int    `gorm:"default:0"`
 	FollowingCount    int    `gorm:"default:0"`
 	TotalViews        int    `gorm:"default:0"`
+	IsPrivate         bool   `gorm:"default:false"` // Postlar sadece onaylı takipçilere görünür
 	Followers         []User `gorm:"many2many:user_followers;joinForeignKey:following_id;joinReferences:follower_id"`
 	Following         []User `gorm:"many2many:user_followers;joinForeignKey:follower_id;joinReferences:following_id"`
 	Posts             []Post `gorm:"foreignKey:UserID"`
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	DeletedAt         gorm.DeletedAt `gorm:"index"`
+
+	// Yetki ve hesap askıya alma
+	Role             UserRole `gorm:"size:20;not null;default:'user'"`
+	SuspendedAt      *time.Time
+	SuspendedUntil   *time.Time // Boşsa süresiz askıya alınmıştır
+	SuspensionReason string     `gorm:"size:500"`
+
+	// Hesap kapatma ve silme
+	DeactivatedAt       *time.Time
+	DeletionScheduledAt *time.Time
+	AnonymizedAt        *time.Time
+}
+
+// Kullanıcının şu anda askıda olup olmadığını döner
+func (user *User) IsSuspended(now time.Time) bool {
+	if user.SuspendedAt == nil {
+		return false
+	}
+	return user.SuspendedUntil == nil || now.Before(*user.SuspendedUntil)
+}
+
+func (user *User) HasRole(roles ...UserRole) bool {
+	for _, role := range roles {
+		if user.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// API'de dönen kullanıcı; parola ve hesap durumu alanlarını içermez
+type UserResponse struct {
+	ID                uint      `json:"id"`
+	FullName          string    `json:"fullName"`
+	Email             string    `json:"email"`
+	Username          string    `json:"username"`
+	ProfileImage      string    `json:"profileImage"`
+	Biography         string    `json:"biography"`
+	InstagramUsername string    `json:"instagramUsername"`
+	FollowerCount     int       `json:"followerCount"`
+	FollowingCount    int       `json:"followingCount"`
+	TotalViews        int       `json:"totalViews"`
+	IsPrivate         bool      `json:"isPrivate"`
+	Role              UserRole  `json:"role"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+func (user *User) SafeResponse() UserResponse {
+	return UserResponse{
+		ID:                user.ID,
+		FullName:          user.FullName,
+		Email:             user.Email,
+		Username:          user.Username,
+		ProfileImage:      user.ProfileImage,
+		Biography:         user.Biography,
+		InstagramUsername: user.InstagramUsername,
+		FollowerCount:     user.FollowerCount,
+		FollowingCount:    user.FollowingCount,
+		TotalViews:        user.TotalViews,
+		IsPrivate:         user.IsPrivate,
+		Role:              user.Role,
+		CreatedAt:         user.CreatedAt,
+	}
 }
 
-func (user *User) SafeResponse() map[string]interface{} {
-	return map[string]interface{}{
-		"id":                user.ID,
-		"fullName":          user.FullName,
-		"email":             user.Email,
-		"username":          user.Username,
-		"profileImage":      user.ProfileImage,
-		"biography":         user.Biography,
-		"instagramUsername": user.InstagramUsername,
-		"followerCount":     user.FollowerCount,
-		"followingCount":    user.FollowingCount,
-		"totalViews":        user.TotalViews,
-		"createdAt":         user.CreatedAt,
+func SafeResponses(users []User) []UserResponse {
+	response := make([]UserResponse, len(users))
+	for i := range users {
+		response[i] = users[i].SafeResponse()
 	}
+	return response
 }
@@ -0,0 +1,28 @@
+// internal/models/user_block.go
+package models
+
+import (
+	"time"
+)
+
+// Engellenen kullanıcı takip edemez, yorum yapamaz, beğenemez ve profili göremez
+type UserBlock struct {
+	ID        uint `gorm:"primaryKey"`
+	BlockerID uint `gorm:"not null;uniqueIndex:idx_user_blocks_pair"`
+	BlockedID uint `gorm:"not null;uniqueIndex:idx_user_blocks_pair;index"`
+	CreatedAt time.Time
+
+	Blocker User `gorm:"foreignkey:BlockerID"`
+	Blocked User `gorm:"foreignkey:BlockedID"`
+}
+
+// Sessize alınan kullanıcının postları akışlarda gösterilmez
+type UserMute struct {
+	ID        uint `gorm:"primaryKey"`
+	MuterID   uint `gorm:"not null;uniqueIndex:idx_user_mutes_pair"`
+	MutedID   uint `gorm:"not null;uniqueIndex:idx_user_mutes_pair"`
+	CreatedAt time.Time
+
+	Muter User `gorm:"foreignkey:MuterID"`
+	Muted User `gorm:"foreignkey:MutedID"`
+}
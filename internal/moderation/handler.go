@@ -0,0 +1,292 @@
+// internal/moderation/handler.go
+package moderation
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sefazor/comfyn/internal/account"
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/apierror"
+	"github.com/sefazor/comfyn/pkg/pagination"
+)
+
+type ReportInput struct {
+	Reason  string `json:"reason" binding:"required,oneof=spam harassment nudity violence hate other"`
+	Details string `json:"details" binding:"max=1000"`
+}
+
+type ModerationActionInput struct {
+	Action      string `json:"action" binding:"required,oneof=dismiss hide delete suspend"`
+	Note        string `json:"note" binding:"max=1000"`
+	SuspendDays int    `json:"suspendDays" binding:"min=0"`
+}
+
+type QueueQuery struct {
+	Status string `form:"status,default=pending" binding:"oneof=pending dismissed actioned"`
+	Page   int    `form:"page,default=1" binding:"min=1"`
+	Limit  int    `form:"limit,default=20" binding:"min=1"`
+}
+
+// Kuyrukta hedef başına gruplanmış raporlar
+type QueueItem struct {
+	TargetType      models.ReportTargetType `json:"targetType"`
+	TargetID        uint                    `json:"targetId"`
+	ReportCount     int64                   `json:"reportCount"`
+	Reasons         string                  `json:"reasons"` // Virgülle ayrılmış, tekrarsız
+	FirstReportedAt time.Time               `json:"firstReportedAt"`
+	LastReportedAt  time.Time               `json:"lastReportedAt"`
+}
+
+type ActionsQuery struct {
+	Moderator uint `form:"moderator"`
+	Page      int  `form:"page,default=1"`
+	Limit     int  `form:"limit,default=50"`
+}
+
+type ReportResponse struct {
+	ID         uint                `json:"id"`
+	Reporter   models.UserResponse `json:"reporter"`
+	Reason     models.ReportReason `json:"reason"`
+	Details    string              `json:"details"`
+	Status     models.ReportStatus `json:"status"`
+	ResolvedAt *time.Time          `json:"resolvedAt"`
+	CreatedAt  time.Time           `json:"createdAt"`
+}
+
+type ActionResponse struct {
+	ID         uint                        `json:"id"`
+	Moderator  *models.UserResponse        `json:"moderator"` // Silinmiş moderatörde boş
+	TargetType models.ReportTargetType     `json:"targetType"`
+	TargetID   uint                        `json:"targetId"`
+	Action     models.ModerationActionType `json:"action"`
+	Note       string                      `json:"note"`
+	CreatedAt  time.Time                   `json:"createdAt"`
+}
+
+// Rapor oluşturma ve moderatör kararları servise, kuyruk ve kayıt okumaları doğrudan store'a gider
+type Handler struct {
+	moderation *Service
+	store      Store
+}
+
+func NewHandler(moderation *Service, store Store) *Handler {
+	return &Handler{moderation: moderation, store: store}
+}
+
+// İsteğin context'ine bağlı store ve servis
+func (h *Handler) storeFor(c *gin.Context) Store {
+	return h.store.WithContext(c.Request.Context())
+}
+
+func (h *Handler) moderationFor(c *gin.Context) *Service {
+	return h.moderation.WithContext(c.Request.Context())
+}
+
+func (h *Handler) ReportPostHandler(c *gin.Context) {
+	h.reportHandler(c, models.ReportTargetPost)
+}
+
+func (h *Handler) ReportCommentHandler(c *gin.Context) {
+	h.reportHandler(c, models.ReportTargetComment)
+}
+
+func (h *Handler) ReportUserHandler(c *gin.Context) {
+	h.reportHandler(c, models.ReportTargetUser)
+}
+
+func (h *Handler) reportHandler(c *gin.Context, targetType models.ReportTargetType) {
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid ID")
+		return
+	}
+
+	var input ReportInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierror.Validation(c, err)
+		return
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	report, err := h.moderationFor(c).CreateReport(currentUser.ID, targetType, uint(targetID), models.ReportReason(input.Reason), input.Details)
+	switch {
+	case errors.Is(err, ErrTargetNotFound):
+		apierror.NotFound(c, "Content not found")
+		return
+	case errors.Is(err, ErrOwnContent):
+		apierror.BadRequest(c, "You cannot report your own content")
+		return
+	case errors.Is(err, ErrAlreadyReported):
+		apierror.Conflict(c, "You have already reported this content")
+		return
+	case err != nil:
+		apierror.Internal(c, "Failed to create report")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Report submitted successfully",
+		"reportId": report.ID,
+	})
+}
+
+// Raporlanan içerikleri hedefe göre gruplayan moderasyon kuyruğu
+func (h *Handler) GetModerationQueueHandler(c *gin.Context) {
+	var query QueueQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Validation(c, err)
+		return
+	}
+
+	if query.Limit > 100 {
+		query.Limit = 100
+	}
+
+	items, total, err := h.storeFor(c).Queue(models.ReportStatus(query.Status), query.Page, query.Limit)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch moderation queue")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":      items,
+		"pagination": pagination.NumberedPage(query.Page, query.Limit, total),
+	})
+}
+
+// Bir hedefe ait tüm raporlar ve geçmiş moderasyon işlemleri
+func (h *Handler) GetTargetReportsHandler(c *gin.Context) {
+	targetType := models.ReportTargetType(c.Param("type"))
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid ID")
+		return
+	}
+
+	store := h.storeFor(c)
+
+	reports, err := store.TargetReports(targetType, uint(targetID))
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch reports")
+		return
+	}
+
+	actions, err := store.TargetActions(targetType, uint(targetID))
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch moderation actions")
+		return
+	}
+
+	reportsResponse := make([]ReportResponse, len(reports))
+	for i, report := range reports {
+		reportsResponse[i] = ReportResponse{
+			ID:         report.ID,
+			Reporter:   report.Reporter.SafeResponse(),
+			Reason:     report.Reason,
+			Details:    report.Details,
+			Status:     report.Status,
+			ResolvedAt: report.ResolvedAt,
+			CreatedAt:  report.CreatedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reports": reportsResponse,
+		"actions": actionsResponse(actions),
+	})
+}
+
+// Moderatör kararını uygulama
+func (h *Handler) TakeActionHandler(c *gin.Context) {
+	targetType := models.ReportTargetType(c.Param("type"))
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid ID")
+		return
+	}
+
+	var input ModerationActionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierror.Validation(c, err)
+		return
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	err = h.moderationFor(c).ApplyAction(currentUser.ID, targetType, uint(targetID), ActionInput{
+		Action:      models.ModerationActionType(input.Action),
+		Note:        input.Note,
+		SuspendDays: input.SuspendDays,
+	})
+	switch {
+	case errors.Is(err, ErrTargetNotFound):
+		apierror.NotFound(c, "Content not found")
+		return
+	case errors.Is(err, ErrInvalidAction):
+		apierror.BadRequest(c, "Action is not valid for this target")
+		return
+	case errors.Is(err, account.ErrCannotSuspend):
+		apierror.Forbidden(c, "This user cannot be suspended")
+		return
+	case err != nil:
+		apierror.Internal(c, "Failed to apply moderation action")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Moderation action applied"})
+}
+
+// Moderatör işlemlerinin denetim kaydı
+func (h *Handler) GetModerationActionsHandler(c *gin.Context) {
+	var query ActionsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Validation(c, err)
+		return
+	}
+	page, limit := query.Page, query.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	actions, total, err := h.storeFor(c).Actions(query.Moderator, page, limit)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch moderation actions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"actions":    actionsResponse(actions),
+		"pagination": pagination.NumberedPage(page, limit, total),
+	})
+}
+
+func actionsResponse(actions []models.ModerationAction) []ActionResponse {
+	response := make([]ActionResponse, len(actions))
+	for i, action := range actions {
+		var moderator *models.UserResponse
+		if action.Moderator != nil {
+			safe := action.Moderator.SafeResponse()
+			moderator = &safe
+		}
+		response[i] = ActionResponse{
+			ID:         action.ID,
+			Moderator:  moderator,
+			TargetType: action.TargetType,
+			TargetID:   action.TargetID,
+			Action:     action.Action,
+			Note:       action.Note,
+			CreatedAt:  action.CreatedAt,
+		}
+	}
+	return response
+}
@@ -0,0 +1,184 @@
+// internal/moderation/service.go
+package moderation
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/sefazor/comfyn/configs"
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/jobs"
+)
+
+var (
+	ErrTargetNotFound  = errors.New("report target not found")
+	ErrOwnContent      = errors.New("you cannot report your own content")
+	ErrAlreadyReported = errors.New("you have already reported this content")
+	ErrInvalidAction   = errors.New("action is not valid for this target")
+)
+
+// Silinen postların akışlardan kaldırılması; iş silme transaction'ının
+// kuyruğuna eklenir
+type Timeline interface {
+	Remove(ctx context.Context, q *jobs.Queue, postID uint) error
+}
+
+type Service struct {
+	store    Store
+	timeline Timeline
+	// Bu kadar bekleyen rapora ulaşan içerik otomatik gizlenir
+	autoHideThreshold int
+	queue             *jobs.Queue
+	// Akıştan kaldırma işi bu ctx'teki trace'e bağlanır
+	ctx context.Context
+}
+
+func NewService(store Store, cfg configs.ModerationConfig, timeline Timeline, queue *jobs.Queue) *Service {
+	return &Service{store: store, timeline: timeline, autoHideThreshold: cfg.AutoHideThreshold, queue: queue, ctx: context.Background()}
+}
+
+// Store sorgularını ctx'e bağlayan kopya
+func (s *Service) WithContext(ctx context.Context) *Service {
+	copy := *s
+	copy.store = s.store.WithContext(ctx)
+	copy.ctx = ctx
+	return &copy
+}
+
+func (s *Service) CreateReport(reporterID uint, targetType models.ReportTargetType, targetID uint, reason models.ReportReason, details string) (*models.Report, error) {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ownerID, err := tx.TargetOwner(targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID == reporterID {
+		return nil, ErrOwnContent
+	}
+
+	reported, err := tx.HasReported(reporterID, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if reported {
+		return nil, ErrAlreadyReported
+	}
+
+	report := models.Report{
+		ReporterID: reporterID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     reason,
+		Details:    details,
+		Status:     models.ReportPending,
+	}
+	if err := tx.CreateReport(&report); err != nil {
+		return nil, err
+	}
+
+	// Eşik aşıldıysa içeriği moderatör incelemesine kadar gizle
+	if targetType != models.ReportTargetUser {
+		pending, err := tx.PendingReportCount(targetType, targetID)
+		if err != nil {
+			return nil, err
+		}
+
+		if pending >= int64(s.autoHideThreshold) {
+			hidden, err := tx.HideTarget(targetType, targetID)
+			if err != nil {
+				return nil, err
+			}
+			if hidden {
+				if err := tx.RecordAction(&models.ModerationAction{
+					TargetType: targetType,
+					TargetID:   targetID,
+					Action:     models.ModerationAutoHide,
+					Note:       "Automatically hidden after " + strconv.FormatInt(pending, 10) + " reports",
+				}); err != nil {
+					return nil, err
+				}
+				log.Printf("Auto-hid %s %d after %d reports", targetType, targetID, pending)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+type ActionInput struct {
+	Action      models.ModerationActionType
+	Note        string
+	SuspendDays int // 0 ise süresiz
+}
+
+// Moderatör kararını uygular ve hedefe ait bekleyen raporları kapatır
+func (s *Service) ApplyAction(moderatorID uint, targetType models.ReportTargetType, targetID uint, input ActionInput) error {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	ownerID, err := tx.TargetOwner(targetType, targetID)
+	if err != nil {
+		return err
+	}
+
+	status := models.ReportActioned
+	switch input.Action {
+	case models.ModerationDismiss:
+		status = models.ReportDismissed
+		// Raporlar asılsızsa otomatik gizleme geri alınır
+		if targetType != models.ReportTargetUser {
+			if _, err := tx.UnhideTarget(targetType, targetID); err != nil {
+				return err
+			}
+		}
+	case models.ModerationHide:
+		if _, err := tx.HideTarget(targetType, targetID); err != nil {
+			return err
+		}
+	case models.ModerationDelete:
+		if err := tx.DeleteTarget(targetType, targetID); err != nil {
+			return err
+		}
+		if targetType == models.ReportTargetPost {
+			if err := s.timeline.Remove(s.ctx, tx.Jobs(s.queue), targetID); err != nil {
+				return err
+			}
+		}
+	case models.ModerationSuspend:
+		// İçerik raporlarında içeriğin sahibi askıya alınır
+		if err := tx.SuspendUser(moderatorID, ownerID, input.Note, input.SuspendDays); err != nil {
+			return err
+		}
+	default:
+		return ErrInvalidAction
+	}
+
+	if err := tx.RecordAction(&models.ModerationAction{
+		ModeratorID: &moderatorID,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Action:      input.Action,
+		Note:        input.Note,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.ResolveReports(targetType, targetID, status, moderatorID, time.Now()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
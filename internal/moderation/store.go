@@ -0,0 +1,285 @@
+// internal/moderation/store.go
+package moderation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sefazor/comfyn/internal/account"
+	"github.com/sefazor/comfyn/internal/models"
+	postsvc "github.com/sefazor/comfyn/internal/post"
+	"github.com/sefazor/comfyn/pkg/jobs"
+	"gorm.io/gorm"
+)
+
+// Raporların ve moderasyon işlemlerinin saklandığı yer
+type Store interface {
+	// Transaction başlatır; Tx üzerindeki işlemler Commit'e kadar kalıcı olmaz
+	Begin() (Tx, error)
+	// Sorguları ctx'e bağlı çalıştıran kopya
+	WithContext(ctx context.Context) Store
+
+	// Raporlanan hedefin sahibini döner
+	TargetOwner(targetType models.ReportTargetType, targetID uint) (uint, error)
+	HasReported(reporterID uint, targetType models.ReportTargetType, targetID uint) (bool, error)
+	CreateReport(report *models.Report) error
+	PendingReportCount(targetType models.ReportTargetType, targetID uint) (int64, error)
+	// Hedefin bekleyen raporlarını verilen durumla kapatır
+	ResolveReports(targetType models.ReportTargetType, targetID uint, status models.ReportStatus, moderatorID uint, at time.Time) error
+
+	// İçeriği gizler, zaten gizliyse false döner
+	HideTarget(targetType models.ReportTargetType, targetID uint) (bool, error)
+	// Gizli içeriği tekrar görünür yapar, gizli değilse false döner
+	UnhideTarget(targetType models.ReportTargetType, targetID uint) (bool, error)
+	DeleteTarget(targetType models.ReportTargetType, targetID uint) error
+	// Moderatörün kendisi ve yöneticiler askıya alınamaz (account.ErrCannotSuspend)
+	SuspendUser(moderatorID, userID uint, reason string, days int) error
+	RecordAction(action *models.ModerationAction) error
+
+	// Hedef başına gruplanmış raporlar ve toplam hedef sayısı
+	Queue(status models.ReportStatus, page, limit int) ([]QueueItem, int64, error)
+	TargetReports(targetType models.ReportTargetType, targetID uint) ([]models.Report, error)
+	TargetActions(targetType models.ReportTargetType, targetID uint) ([]models.ModerationAction, error)
+	// moderatorID sıfırsa tüm moderatörlerin işlemleri döner
+	Actions(moderatorID uint, page, limit int) ([]models.ModerationAction, int64, error)
+}
+
+type Tx interface {
+	Store
+	// İşleri bu transaction'la birlikte kaydeden kuyruk
+	Jobs(queue *jobs.Queue) *jobs.Queue
+	Commit() error
+	Rollback() error
+}
+
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) WithContext(ctx context.Context) Store {
+	return &PostgresStore{db: s.db.WithContext(ctx)}
+}
+
+type postgresTx struct {
+	PostgresStore
+}
+
+func (s *PostgresStore) Begin() (Tx, error) {
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return &postgresTx{PostgresStore{db: tx}}, nil
+}
+
+func (t *postgresTx) Jobs(queue *jobs.Queue) *jobs.Queue {
+	return queue.WithTx(t.db)
+}
+
+func (t *postgresTx) Commit() error {
+	return t.db.Commit().Error
+}
+
+func (t *postgresTx) Rollback() error {
+	return t.db.Rollback().Error
+}
+
+func (s *PostgresStore) TargetOwner(targetType models.ReportTargetType, targetID uint) (uint, error) {
+	var ownerID uint
+	var err error
+
+	switch targetType {
+	case models.ReportTargetPost:
+		var post models.Post
+		err = s.db.Select("id, user_id").First(&post, targetID).Error
+		ownerID = post.UserID
+	case models.ReportTargetComment:
+		var comment models.Comment
+		err = s.db.Select("id, user_id").First(&comment, targetID).Error
+		ownerID = comment.UserID
+	case models.ReportTargetUser:
+		var user models.User
+		err = s.db.Select("id").First(&user, targetID).Error
+		ownerID = user.ID
+	default:
+		return 0, ErrTargetNotFound
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrTargetNotFound
+	}
+	return ownerID, err
+}
+
+func (s *PostgresStore) HasReported(reporterID uint, targetType models.ReportTargetType, targetID uint) (bool, error) {
+	var count int64
+	err := s.db.Model(&models.Report{}).
+		Where("reporter_id = ? AND target_type = ? AND target_id = ?", reporterID, targetType, targetID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (s *PostgresStore) CreateReport(report *models.Report) error {
+	return s.db.Create(report).Error
+}
+
+func (s *PostgresStore) PendingReportCount(targetType models.ReportTargetType, targetID uint) (int64, error) {
+	var count int64
+	err := s.db.Model(&models.Report{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, models.ReportPending).
+		Count(&count).Error
+	return count, err
+}
+
+func (s *PostgresStore) ResolveReports(targetType models.ReportTargetType, targetID uint, status models.ReportStatus, moderatorID uint, at time.Time) error {
+	return s.db.Model(&models.Report{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, models.ReportPending).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"resolved_by_id": moderatorID,
+			"resolved_at":    at,
+		}).Error
+}
+
+func (s *PostgresStore) HideTarget(targetType models.ReportTargetType, targetID uint) (bool, error) {
+	var result *gorm.DB
+	switch targetType {
+	case models.ReportTargetPost:
+		result = s.db.Model(&models.Post{}).Where("id = ? AND hidden_at IS NULL", targetID).Update("hidden_at", time.Now())
+	case models.ReportTargetComment:
+		var comment models.Comment
+		if err := s.db.First(&comment, targetID).Error; err != nil {
+			return false, err
+		}
+		result = s.db.Model(&models.Comment{}).Where("id = ? AND hidden_at IS NULL", targetID).Update("hidden_at", time.Now())
+		// Gizlenen yorum post sayacından düşülür
+		if result.Error == nil && result.RowsAffected > 0 {
+			if err := postsvc.AdjustCounter(s.db, comment.PostID, postsvc.CommentCounter, -1); err != nil {
+				return false, err
+			}
+		}
+	default:
+		return false, ErrInvalidAction
+	}
+	return result.RowsAffected > 0, result.Error
+}
+
+func (s *PostgresStore) UnhideTarget(targetType models.ReportTargetType, targetID uint) (bool, error) {
+	var result *gorm.DB
+	switch targetType {
+	case models.ReportTargetPost:
+		result = s.db.Model(&models.Post{}).Where("id = ? AND hidden_at IS NOT NULL", targetID).Update("hidden_at", nil)
+	case models.ReportTargetComment:
+		var comment models.Comment
+		if err := s.db.First(&comment, targetID).Error; err != nil {
+			return false, err
+		}
+		result = s.db.Model(&models.Comment{}).Where("id = ? AND hidden_at IS NOT NULL", targetID).Update("hidden_at", nil)
+		// Görünür olan yorum post sayacına geri eklenir
+		if result.Error == nil && result.RowsAffected > 0 {
+			if err := postsvc.AdjustCounter(s.db, comment.PostID, postsvc.CommentCounter, 1); err != nil {
+				return false, err
+			}
+		}
+	default:
+		return false, ErrInvalidAction
+	}
+	return result.RowsAffected > 0, result.Error
+}
+
+func (s *PostgresStore) DeleteTarget(targetType models.ReportTargetType, targetID uint) error {
+	switch targetType {
+	case models.ReportTargetPost:
+		// Kullanıcının kendi silmesiyle aynı temizlik (ürün ilişkileri) yapılır
+		return postsvc.NewPostgresStore(s.db).DeletePost(&models.Post{ID: targetID})
+	case models.ReportTargetComment:
+		var comment models.Comment
+		if err := s.db.First(&comment, targetID).Error; err != nil {
+			return err
+		}
+		if err := s.db.Delete(&comment).Error; err != nil {
+			return err
+		}
+		// Gizli yorumlar sayaca zaten dahil değil
+		if comment.HiddenAt != nil {
+			return nil
+		}
+		return postsvc.AdjustCounter(s.db, comment.PostID, postsvc.CommentCounter, -1)
+	default:
+		return ErrInvalidAction
+	}
+}
+
+func (s *PostgresStore) SuspendUser(moderatorID, userID uint, reason string, days int) error {
+	return account.Suspend(s.db, moderatorID, userID, reason, days)
+}
+
+func (s *PostgresStore) RecordAction(action *models.ModerationAction) error {
+	return s.db.Create(action).Error
+}
+
+func (s *PostgresStore) Queue(status models.ReportStatus, page, limit int) ([]QueueItem, int64, error) {
+	db := s.db.Model(&models.Report{}).
+		Where("status = ?", status).
+		Group("target_type, target_id").
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := s.db.Table("(?) AS q", db.Select("target_type, target_id")).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []QueueItem
+	err := db.Select(`target_type, target_id, COUNT(*) AS report_count,
+			string_agg(DISTINCT reason, ',') AS reasons,
+			MIN(created_at) AS first_reported_at,
+			MAX(created_at) AS last_reported_at`).
+		Order("report_count DESC, MIN(created_at)").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Scan(&items).Error
+	return items, total, err
+}
+
+func (s *PostgresStore) TargetReports(targetType models.ReportTargetType, targetID uint) ([]models.Report, error) {
+	var reports []models.Report
+	err := s.db.Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Preload("Reporter").
+		Order("created_at DESC").
+		Find(&reports).Error
+	return reports, err
+}
+
+func (s *PostgresStore) TargetActions(targetType models.ReportTargetType, targetID uint) ([]models.ModerationAction, error) {
+	var actions []models.ModerationAction
+	err := s.db.Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Preload("Moderator").
+		Order("created_at DESC").
+		Find(&actions).Error
+	return actions, err
+}
+
+func (s *PostgresStore) Actions(moderatorID uint, page, limit int) ([]models.ModerationAction, int64, error) {
+	db := s.db.Model(&models.ModerationAction{})
+	if moderatorID != 0 {
+		db = db.Where("moderator_id = ?", moderatorID)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var actions []models.ModerationAction
+	err := db.Preload("Moderator").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&actions).Error
+	return actions, total, err
+}
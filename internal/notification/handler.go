@@ -1,53 +1,76 @@
 package notification
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sefazor/comfyn/internal/models"
-	"github.com/sefazor/comfyn/pkg/database"
+	"github.com/sefazor/comfyn/pkg/apierror"
+	"github.com/sefazor/comfyn/pkg/pagination"
 )
 
+type Handler struct {
+	store Store
+}
+
+func NewHandler(store Store) *Handler {
+	return &Handler{store: store}
+}
+
+// İsteğin context'ine bağlı store
+func (h *Handler) storeFor(c *gin.Context) Store {
+	return h.store.WithContext(c.Request.Context())
+}
+
 // Bildirimleri listeleme
-func GetNotificationsHandler(c *gin.Context) {
+func (h *Handler) GetNotificationsHandler(c *gin.Context) {
+	params, err := pagination.Parse(c)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid cursor")
+		return
+	}
+
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	var notifications []models.Notification
-	if err := database.DB.Where("user_id = ?", currentUser.ID).
-		Preload("Actor").
-		Preload("Post").
-		Preload("Comment").
-		Order("created_at DESC").
-		Find(&notifications).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+	notifications, err := h.storeFor(c).List(currentUser.ID, params)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch notifications")
 		return
 	}
+	notifications, page := pagination.Paginate(notifications, params, notificationKey)
 
 	// Response'ları hazırla
-	response := make([]map[string]interface{}, len(notifications))
+	response := make([]models.NotificationResponse, len(notifications))
 	for i, notification := range notifications {
 		response[i] = notification.Response()
 	}
 
-	c.JSON(http.StatusOK, gin.H{"notifications": response})
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": response,
+		"pagination":    page,
+	})
 }
 
 // Bildirimi okundu olarak işaretle
-func MarkNotificationReadHandler(c *gin.Context) {
-	notificationID := c.Param("id")
-	user, _ := c.Get("user")
-	currentUser := user.(models.User)
-
-	var notification models.Notification
-	if err := database.DB.Where("id = ? AND user_id = ?", notificationID, currentUser.ID).
-		First(&notification).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+func (h *Handler) MarkNotificationReadHandler(c *gin.Context) {
+	notificationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.NotFound(c, "Notification not found")
 		return
 	}
 
-	if err := database.DB.Model(&notification).Update("is_read", true).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark notification as read"})
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	if err := h.storeFor(c).MarkRead(uint(notificationID), currentUser.ID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			apierror.NotFound(c, "Notification not found")
+			return
+		}
+		apierror.Internal(c, "Failed to mark notification as read")
 		return
 	}
 
@@ -59,23 +82,32 @@ type UpdatePreferencesInput struct {
 	NewFollower bool `json:"newFollower"`
 	PostLike    bool `json:"postLike"`
 	Comment     bool `json:"comment"`
+
+	// Push tercihleri gönderilmezse mevcut değerler korunur
+	PushNewFollower *bool `json:"pushNewFollower"`
+	PushPostLike    *bool `json:"pushPostLike"`
+	PushComment     *bool `json:"pushComment"`
+
+	// E-posta tercihleri
+	EmailDigest  *string `json:"emailDigest" binding:"omitempty,oneof=off daily weekly"`
+	WeeklyReport *bool   `json:"weeklyReport"`
 }
 
 // Bildirim tercihlerini güncelleme
-func UpdateNotificationPreferencesHandler(c *gin.Context) {
+func (h *Handler) UpdateNotificationPreferencesHandler(c *gin.Context) {
 	var input UpdatePreferencesInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Validation(c, err)
 		return
 	}
 
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	var pref models.NotificationPreference
 	// Tercihler yoksa oluştur, varsa güncelle
-	if err := database.DB.FirstOrCreate(&pref, models.NotificationPreference{UserID: currentUser.ID}).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get preferences"})
+	pref, err := h.storeFor(c).Preferences(currentUser.ID)
+	if err != nil {
+		apierror.Internal(c, "Failed to get preferences")
 		return
 	}
 
@@ -85,27 +117,40 @@ func UpdateNotificationPreferencesHandler(c *gin.Context) {
 		"post_like":    input.PostLike,
 		"comment":      input.Comment,
 	}
+	if input.PushNewFollower != nil {
+		updates["push_new_follower"] = *input.PushNewFollower
+	}
+	if input.PushPostLike != nil {
+		updates["push_post_like"] = *input.PushPostLike
+	}
+	if input.PushComment != nil {
+		updates["push_comment"] = *input.PushComment
+	}
+	if input.EmailDigest != nil {
+		updates["email_digest"] = *input.EmailDigest
+	}
+	if input.WeeklyReport != nil {
+		updates["weekly_report"] = *input.WeeklyReport
+	}
 
-	if err := database.DB.Model(&pref).Updates(updates).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update preferences"})
+	if err := h.storeFor(c).UpdatePreferences(pref, updates); err != nil {
+		apierror.Internal(c, "Failed to update preferences")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message":     "Notification preferences updated",
-		"preferences": pref,
+		"preferences": pref.Response(),
 	})
 }
 
 // Tüm bildirimleri okundu olarak işaretle
-func MarkAllNotificationsReadHandler(c *gin.Context) {
+func (h *Handler) MarkAllNotificationsReadHandler(c *gin.Context) {
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	if err := database.DB.Model(&models.Notification{}).
-		Where("user_id = ? AND is_read = ?", currentUser.ID, false).
-		Update("is_read", true).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark notifications as read"})
+	if err := h.storeFor(c).MarkAllRead(currentUser.ID); err != nil {
+		apierror.Internal(c, "Failed to mark notifications as read")
 		return
 	}
 
@@ -113,15 +158,13 @@ func MarkAllNotificationsReadHandler(c *gin.Context) {
 }
 
 // Okunmamış bildirim sayısını getir
-func GetUnreadNotificationCountHandler(c *gin.Context) {
+func (h *Handler) GetUnreadNotificationCountHandler(c *gin.Context) {
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	var count int64
-	if err := database.DB.Model(&models.Notification{}).
-		Where("user_id = ? AND is_read = ?", currentUser.ID, false).
-		Count(&count).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get unread notification count"})
+	count, err := h.storeFor(c).UnreadCount(currentUser.ID)
+	if err != nil {
+		apierror.Internal(c, "Failed to get unread notification count")
 		return
 	}
 
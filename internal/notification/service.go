@@ -1,34 +1,89 @@
 package notification
 
 import (
+	"context"
+
 	"github.com/sefazor/comfyn/internal/models"
-	"github.com/sefazor/comfyn/pkg/database"
+	"github.com/sefazor/comfyn/pkg/jobs"
+	"github.com/sefazor/comfyn/pkg/metrics"
 )
 
+// Diğer domain'lerin bildirim göndermek için kullandığı arayüz. q bildirime
+// yol açan işlemin transaction'ına bağlı kuyruktur (Tx.Jobs); bildirim işi
+// ancak o işlem commit edilirse görünür olur.
+type Notifier interface {
+	Notify(ctx context.Context, q *jobs.Queue, userID, actorID uint, notificationType models.NotificationType, postID, commentID *uint) error
+}
+
+// Bildirimler isteği bekletmemek için kuyruk üzerinden oluşturulur
+var createJob = jobs.NewKind[createPayload]("notification.create")
+
+type createPayload struct {
+	UserID    uint                    `json:"userId"`
+	ActorID   uint                    `json:"actorId"`
+	Type      models.NotificationType `json:"type"`
+	PostID    *uint                   `json:"postId,omitempty"`
+	CommentID *uint                   `json:"commentId,omitempty"`
+}
+
 // Bildirim oluşturma servisi
-func CreateNotification(userID, actorID uint, notificationType models.NotificationType, postID, commentID *uint) error {
+type Service struct {
+	store   Store
+	queue   *jobs.Queue
+	deliver func(ctx context.Context, q *jobs.Queue, notificationID uint) error
+}
+
+// deliver oluşturulan bildirimi cihazlara iletecek işi q'ya ekler (örn.
+// push.DeliverAsync), nil olabilir
+func NewService(store Store, queue *jobs.Queue, deliver func(ctx context.Context, q *jobs.Queue, notificationID uint) error) *Service {
+	return &Service{store: store, queue: queue, deliver: deliver}
+}
+
+// Kuyruktaki bildirimleri oluşturan handler'ı worker'a ekler
+func (s *Service) RegisterJobs(w *jobs.Worker) {
+	jobs.Handle(w, createJob, func(ctx context.Context, p createPayload) error {
+		return s.create(ctx, p.UserID, p.ActorID, p.Type, p.PostID, p.CommentID)
+	})
+}
+
+// Bildirimi oluşturulmak üzere kuyruğa ekler; iş ctx'teki trace'e bağlanır
+func (s *Service) Notify(ctx context.Context, q *jobs.Queue, userID, actorID uint, notificationType models.NotificationType, postID, commentID *uint) error {
+	return createJob.Enqueue(ctx, q, createPayload{
+		UserID:    userID,
+		ActorID:   actorID,
+		Type:      notificationType,
+		PostID:    postID,
+		CommentID: commentID,
+	})
+}
+
+func (s *Service) create(ctx context.Context, userID, actorID uint, notificationType models.NotificationType, postID, commentID *uint) error {
 	// Kullanıcının bildirim tercihlerini kontrol et
-	var pref models.NotificationPreference
-	if err := database.DB.FirstOrCreate(&pref, models.NotificationPreference{UserID: userID}).Error; err != nil {
+	store := s.store.WithContext(ctx)
+
+	pref, err := store.Preferences(userID)
+	if err != nil {
 		return err
 	}
 
 	// Tercihlere göre bildirimi kontrol et
 	shouldNotify := false
 	switch notificationType {
-	case models.NotificationNewFollower:
+	case models.NotificationNewFollower, models.NotificationFollowRequest:
 		shouldNotify = pref.NewFollower
 	case models.NotificationPostLike:
 		shouldNotify = pref.PostLike
 	case models.NotificationComment:
 		shouldNotify = pref.Comment
+	case models.NotificationDataExport:
+		// Sistem bildirimleri tercihlerden bağımsız gönderilir
+		shouldNotify = true
 	}
 
 	if !shouldNotify {
 		return nil
 	}
 
-	// Bildirimi oluştur
 	notification := models.Notification{
 		UserID:    userID,
 		ActorID:   actorID,
@@ -37,5 +92,38 @@ func CreateNotification(userID, actorID uint, notificationType models.Notificati
 		CommentID: commentID,
 	}
 
-	return database.DB.Create(&notification).Error
+	// İş kuyrukta beklerken beğeni geri alınmış ya da takip isteği iptal
+	// edilmiş olabilir; bu durumda silinmiş bildirim yeniden oluşmaz
+	exists, err := store.SourceExists(&notification)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	// Bildirim ve push işi birlikte kaydedilir; iş tekrar çalışırsa
+	// aynı bildirim iki kez oluşmaz
+	tx, err := store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.Create(&notification); err != nil {
+		return err
+	}
+
+	// Mobil cihazlara push gönder
+	if s.deliver != nil {
+		if err := s.deliver(ctx, tx.Jobs(s.queue), notification.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	metrics.NotificationsCreated.WithLabelValues(string(notificationType)).Inc()
+	return nil
 }
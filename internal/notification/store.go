@@ -0,0 +1,170 @@
+// internal/notification/store.go
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/jobs"
+	"github.com/sefazor/comfyn/pkg/pagination"
+	"gorm.io/gorm"
+)
+
+var ErrNotFound = errors.New("notification not found")
+
+// Bildirimlerin ve bildirim tercihlerinin saklandığı yer
+type Store interface {
+	// Transaction başlatır; Tx üzerindeki işlemler Commit'e kadar kalıcı olmaz
+	Begin() (Tx, error)
+	// Sorguları ctx'e bağlı çalıştıran kopya
+	WithContext(ctx context.Context) Store
+
+	// Tercihler yoksa varsayılanlarla oluşturulur
+	Preferences(userID uint) (*models.NotificationPreference, error)
+	UpdatePreferences(pref *models.NotificationPreference, updates map[string]interface{}) error
+
+	Create(notification *models.Notification) error
+	// Bildirime yol açan beğeni, yorum, takip ya da takip isteği hâlâ duruyor mu
+	SourceExists(notification *models.Notification) (bool, error)
+	// Sıralama keyset'e göre yapılır, sayfa boyutundan bir fazla kayıt döner
+	List(userID uint, params pagination.Params) ([]models.Notification, error)
+	MarkRead(id, userID uint) error
+	MarkAllRead(userID uint) error
+	UnreadCount(userID uint) (int64, error)
+}
+
+type Tx interface {
+	Store
+	// İşleri bu transaction'la birlikte kaydeden kuyruk
+	Jobs(queue *jobs.Queue) *jobs.Queue
+	Commit() error
+	Rollback() error
+}
+
+var notificationOrder = pagination.Order{Column: "notifications.created_at", IDColumn: "notifications.id"}
+
+func notificationKey(n models.Notification) pagination.Cursor {
+	return pagination.TimeKey(n.CreatedAt, n.ID)
+}
+
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) WithContext(ctx context.Context) Store {
+	return &PostgresStore{db: s.db.WithContext(ctx)}
+}
+
+type postgresTx struct {
+	PostgresStore
+}
+
+func (s *PostgresStore) Begin() (Tx, error) {
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return &postgresTx{PostgresStore{db: tx}}, nil
+}
+
+func (t *postgresTx) Jobs(queue *jobs.Queue) *jobs.Queue {
+	return queue.WithTx(t.db)
+}
+
+func (t *postgresTx) Commit() error {
+	return t.db.Commit().Error
+}
+
+func (t *postgresTx) Rollback() error {
+	return t.db.Rollback().Error
+}
+
+func (s *PostgresStore) Preferences(userID uint) (*models.NotificationPreference, error) {
+	var pref models.NotificationPreference
+	if err := s.db.FirstOrCreate(&pref, models.NotificationPreference{UserID: userID}).Error; err != nil {
+		return nil, err
+	}
+	return &pref, nil
+}
+
+func (s *PostgresStore) UpdatePreferences(pref *models.NotificationPreference, updates map[string]interface{}) error {
+	return s.db.Model(pref).Updates(updates).Error
+}
+
+func (s *PostgresStore) Create(notification *models.Notification) error {
+	return s.db.Create(notification).Error
+}
+
+func (s *PostgresStore) SourceExists(notification *models.Notification) (bool, error) {
+	var query *gorm.DB
+	switch notification.Type {
+	case models.NotificationPostLike:
+		if notification.PostID == nil {
+			return false, nil
+		}
+		query = s.db.Model(&models.Like{}).
+			Where("post_id = ? AND user_id = ?", *notification.PostID, notification.ActorID)
+	case models.NotificationComment:
+		if notification.CommentID == nil {
+			return false, nil
+		}
+		query = s.db.Model(&models.Comment{}).
+			Where("id = ? AND hidden_at IS NULL", *notification.CommentID)
+	case models.NotificationFollowRequest:
+		query = s.db.Model(&models.FollowRequest{}).
+			Where("requester_id = ? AND target_id = ?", notification.ActorID, notification.UserID)
+	case models.NotificationNewFollower:
+		query = s.db.Table("user_followers").
+			Where("follower_id = ? AND following_id = ?", notification.ActorID, notification.UserID)
+	default:
+		// Sistem bildirimlerinin geri alınabilir bir kaynağı yok
+		return true, nil
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (s *PostgresStore) List(userID uint, params pagination.Params) ([]models.Notification, error) {
+	query := s.db.Where("user_id = ?", userID).
+		Preload("Actor").
+		Preload("Post").
+		Preload("Comment")
+
+	var notifications []models.Notification
+	err := params.Apply(query, notificationOrder).Find(&notifications).Error
+	return notifications, err
+}
+
+func (s *PostgresStore) MarkRead(id, userID uint) error {
+	var notification models.Notification
+	if err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return s.db.Model(&notification).Update("is_read", true).Error
+}
+
+func (s *PostgresStore) MarkAllRead(userID uint) error {
+	return s.db.Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Update("is_read", true).Error
+}
+
+func (s *PostgresStore) UnreadCount(userID uint) (int64, error) {
+	var count int64
+	err := s.db.Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Count(&count).Error
+	return count, err
+}
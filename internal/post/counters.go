@@ -0,0 +1,48 @@
+// internal/post/counters.go
+package post
+
+import (
+	"gorm.io/gorm"
+)
+
+// Post üzerindeki etkileşim sayaç sütunları
+type Counter string
+
+const (
+	LikeCounter    Counter = "like_count"
+	CommentCounter Counter = "comment_count"
+	SaveCounter    Counter = "save_count"
+	ViewCounter    Counter = "view_count"
+)
+
+var Counters = []Counter{LikeCounter, CommentCounter, SaveCounter, ViewCounter}
+
+// Sayaçların kaynak tablolardan hesaplanma şekli
+var counterSources = map[Counter]string{
+	LikeCounter:    "SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id",
+	CommentCounter: "SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id AND comments.deleted_at IS NULL AND comments.hidden_at IS NULL",
+	SaveCounter:    "SELECT COUNT(*) FROM saved_posts WHERE saved_posts.post_id = posts.id",
+	ViewCounter:    "SELECT COUNT(*) FROM post_views WHERE post_views.post_id = posts.id",
+}
+
+// Sayaç değerini delta kadar değiştirir. Çağıran transaction içinde kullanılmalıdır.
+func AdjustCounter(tx *gorm.DB, postID uint, counter Counter, delta int) error {
+	column := string(counter)
+	return tx.Table("posts").Where("id = ?", postID).
+		UpdateColumn(column, gorm.Expr("GREATEST("+column+" + ?, 0)", delta)).Error
+}
+
+// Sayaçları kaynak tablolardan yeniden hesaplar ve düzeltilen post sayısını döner
+func ReconcileCounters(db *gorm.DB) (map[Counter]int64, error) {
+	fixed := make(map[Counter]int64, len(counterSources))
+	for _, counter := range Counters {
+		column, source := string(counter), counterSources[counter]
+		result := db.Exec("UPDATE posts SET " + column + " = (" + source + ") WHERE " +
+			column + " IS DISTINCT FROM (" + source + ")")
+		if result.Error != nil {
+			return fixed, result.Error
+		}
+		fixed[counter] = result.RowsAffected
+	}
+	return fixed, nil
+}
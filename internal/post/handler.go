@@ -1,901 +1,521 @@
 package post
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
-	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sefazor/comfyn/internal/models"
-	"github.com/sefazor/comfyn/internal/notification"
-	"github.com/sefazor/comfyn/pkg/database"
-	"gorm.io/gorm"
+	"github.com/sefazor/comfyn/internal/ranking"
+	"github.com/sefazor/comfyn/pkg/apierror"
+	"github.com/sefazor/comfyn/pkg/pagination"
 )
 
-type CreatePostInput struct {
-	ImageURL    string    `json:"imageUrl" binding:"required"`
-	Description string    `json:"description"`
-	Products    []Product `json:"products" binding:"required,dive,required"`
-	CategoryIDs []uint    `json:"categoryIds" binding:"required,min=1"`
-	Hashtags    []string  `json:"hashtags"`
+// Akış okuması için gereken işlem
+type FeedReader interface {
+	Read(ctx context.Context, viewerID uint, params pagination.Params) ([]uint, pagination.Page, error)
 }
 
-type Product struct {
-	Name        string  `json:"name" binding:"required"`
-	Price       float64 `json:"price" binding:"required"`
-	Link        string  `json:"link"`
-	Description string  `json:"description"`
-	CategoryIDs []uint  `json:"categoryIds" binding:"required,min=1"`
+// Yazma işlemleri servise, listeleme ve okuma işlemleri doğrudan store'a gider
+type Handler struct {
+	posts *Service
+	store Store
+	feed  FeedReader
 }
 
-type CreateCommentInput struct {
-	Content string `json:"content" binding:"required"`
+func NewHandler(posts *Service, store Store, feed FeedReader) *Handler {
+	return &Handler{posts: posts, store: store, feed: feed}
 }
 
-func CreatePostHandler(c *gin.Context) {
+// İsteğin context'ine bağlı store ve servis
+func (h *Handler) storeFor(c *gin.Context) Store {
+	return h.store.WithContext(c.Request.Context())
+}
+
+func (h *Handler) postsFor(c *gin.Context) *Service {
+	return h.posts.WithContext(c.Request.Context())
+}
+
+// Servis hatalarını HTTP yanıtına çevirir; tanınmayan hatalar fallback mesajıyla 500 döner
+func writeError(c *gin.Context, err error, fallback string) {
+	var tooMany *TooManyProductsError
+	switch {
+	case errors.Is(err, ErrNotFound):
+		apierror.NotFound(c, "Post not found")
+	case errors.Is(err, ErrCannotInteract):
+		apierror.Forbidden(c, "You cannot interact with this post")
+	case errors.Is(err, ErrPrivateAccount):
+		apierror.PrivateAccount(c)
+	case errors.Is(err, ErrInvalidCategories):
+		apierror.BadRequest(c, "Invalid category IDs")
+	case errors.Is(err, ErrInvalidProductCategories):
+		apierror.BadRequest(c, "Invalid product category IDs")
+	case errors.Is(err, ErrEmptyComment):
+		apierror.BadRequest(c, "Comment content is required")
+	case errors.As(err, &tooMany):
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.CodeTooManyProducts, tooMany.Error()).
+			With("currentCount", tooMany.Count).
+			With("maxAllowed", models.MaxProductsPerPost))
+	default:
+		slog.ErrorContext(c.Request.Context(), fallback, "error", err)
+		apierror.Internal(c, fallback)
+	}
+}
+
+// URL'deki post ID'sini okur, geçersizse 404 yazar
+func postIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.NotFound(c, "Post not found")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *Handler) CreatePostHandler(c *gin.Context) {
 	var input CreatePostInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Validation(c, err)
 		return
 	}
 
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	tx := database.DB.Begin()
-
-	// Kategorileri kontrol et
-	var categories []models.Category
-	if err := tx.Find(&categories, input.CategoryIDs).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category IDs"})
+	post, err := h.postsFor(c).CreatePost(currentUser.ID, input)
+	if err != nil {
+		writeError(c, err, "Failed to create post")
 		return
 	}
 
-	// Hashtag'leri işle
-	var hashtags []models.Hashtag
-	for _, tagName := range input.Hashtags {
-		normalizedTag := models.NormalizeHashtag(tagName)
-		if normalizedTag == "" {
-			continue
-		}
-
-		var hashtag models.Hashtag
-		err := tx.Where("name = ?", normalizedTag).FirstOrCreate(&hashtag, models.Hashtag{
-			Name: normalizedTag,
-		}).Error
-		if err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process hashtags"})
-			return
-		}
-		hashtags = append(hashtags, hashtag)
-	}
-
-	// Önce post'u oluştur (ürünlerin tracking URL'lerinde post ID'ye ihtiyacımız var)
-	post := models.Post{
-		UserID:      currentUser.ID,
-		ImageURL:    input.ImageURL,
-		Description: input.Description,
-		Categories:  categories,
-		Hashtags:    hashtags,
-	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Post created successfully",
+		"post":    post.Response(),
+	})
+}
 
-	if err := tx.Create(&post).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
+func (h *Handler) ListPostsHandler(c *gin.Context) {
+	params, err := pagination.Parse(c)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid cursor")
 		return
 	}
 
-	// Şimdi ürünleri oluştur ve tracking URL'lerini ekle
-	var products []models.Product
-	for _, p := range input.Products {
-		var productCategories []models.Category
-		if err := tx.Find(&productCategories, p.CategoryIDs).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product category IDs"})
-			return
-		}
-
-		product := models.Product{
-			Name:        p.Name,
-			Price:       p.Price,
-			Link:        p.Link,
-			Description: p.Description,
-			Categories:  productCategories,
-		}
-
-		// Önce ürünü oluştur
-		if err := tx.Create(&product).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create product"})
-			return
-		}
-
-		// Affiliate link oluştur
-		affiliateLink := models.AffiliateLink{
-			UserID:      currentUser.ID,
-			PostID:      post.ID,
-			ProductID:   product.ID,
-			OriginalURL: product.Link,
-			TrackingURL: fmt.Sprintf("https://comfyn.com/go/cmf_%d_%d_%d", currentUser.ID, post.ID, product.ID),
-		}
-
-		if err := tx.Create(&affiliateLink).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create affiliate link"})
-			return
-		}
-
-		// Product'a tracking URL'i ekle
-		product.TrackingURL = affiliateLink.TrackingURL
-		if err := tx.Save(&product).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update product"})
-			return
-		}
-
-		products = append(products, product)
-	}
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
 
-	// Post'a ürünleri ekle
-	post.Products = products
-	if err := tx.Save(&post).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post with products"})
+	posts, err := h.storeFor(c).ListPosts(currentUser.ID, params)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch posts")
 		return
 	}
+	posts, page := pagination.Paginate(posts, params, postKey)
 
-	// Post'u tüm ilişkileriyle birlikte yükle
-	if err := tx.Preload("User").
-		Preload("Categories").
-		Preload("Products").
-		Preload("Products.Categories").
-		Preload("Hashtags").
-		First(&post, post.ID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load post data"})
+	response, err := h.postResponses(c, currentUser.ID, posts)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch posts")
 		return
 	}
 
-	tx.Commit()
-
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Post created successfully",
-		"post":    post.Response(),
+	c.JSON(http.StatusOK, gin.H{
+		"posts":      response,
+		"pagination": page,
 	})
 }
-func ListPostsHandler(c *gin.Context) {
-	var posts []models.Post
 
-	if err := database.DB.Preload("User").
-		Preload("Products").
-		Preload("Categories").
-		Preload("Hashtags").
-		Find(&posts).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+func (h *Handler) GetPostHandler(c *gin.Context) {
+	postID, ok := postIDParam(c)
+	if !ok {
 		return
 	}
 
-	response := make([]map[string]interface{}, len(posts))
-	for i, post := range posts {
-		response[i] = post.Response()
-	}
-
-	c.JSON(http.StatusOK, gin.H{"posts": response})
-}
-
-func GetPostHandler(c *gin.Context) {
-	postID := c.Param("id")
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	var post models.Post
-	if err := database.DB.Preload("User").
-		Preload("Products").
-		Preload("Products.Categories").
-		Preload("Categories").
-		Preload("Hashtags").
-		Preload("Likes").
-		Preload("Comments").
-		Preload("Comments.User").
-		First(&post, postID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
-		return
-	}
-
-	var isLiked bool
-	for _, like := range post.Likes {
-		if like.UserID == currentUser.ID {
-			isLiked = true
-			break
-		}
+	post, err := h.postsFor(c).Get(currentUser.ID, postID)
+	if err != nil {
+		writeError(c, err, "Failed to fetch post")
+		return
 	}
 
-	response := post.Response()
-	response["isLiked"] = isLiked
-	response["comments"] = post.Comments
+	state, err := h.storeFor(c).ViewerState(currentUser.ID, []models.Post{*post})
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch post")
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"post": response})
-}
+	// Yorumların ilk sayfası, devamı /posts/:id/comments ile alınır
+	comments, commentsPage, err := h.listComments(c, post.ID, pagination.Params{Limit: pagination.DefaultLimit})
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch comments")
+		return
+	}
 
-func DeletePostHandler(c *gin.Context) {
-	postID := c.Param("id")
-	user, _ := c.Get("user")
-	currentUser := user.(models.User)
+	c.JSON(http.StatusOK, gin.H{"post": PostDetailResponse{
+		PostResponse:       state.Apply(post.Response(), post),
+		Comments:           comments,
+		CommentsPagination: commentsPage,
+	}})
+}
 
-	var post models.Post
-	if err := database.DB.Preload("Products").First(&post, postID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+// Postun yorumları, eskiden yeniye
+func (h *Handler) GetCommentsHandler(c *gin.Context) {
+	params, err := pagination.Parse(c)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid cursor")
 		return
 	}
 
-	if post.UserID != currentUser.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own posts"})
+	postID, ok := postIDParam(c)
+	if !ok {
 		return
 	}
 
-	tx := database.DB.Begin()
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
 
-	if err := tx.Model(&post).Association("Products").Clear(); err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete post products"})
+	post, err := h.postsFor(c).Find(currentUser.ID, postID)
+	if err != nil {
+		writeError(c, err, "Failed to fetch comments")
 		return
 	}
 
-	if err := tx.Delete(&post).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete post"})
+	comments, page, err := h.listComments(c, post.ID, params)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch comments")
 		return
 	}
 
-	tx.Commit()
-
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Post deleted successfully",
+		"comments":   comments,
+		"pagination": page,
 	})
 }
 
-func LikePostHandler(c *gin.Context) {
-	postID := c.Param("id")
-	user, _ := c.Get("user")
-	currentUser := user.(models.User)
-
-	tx := database.DB.Begin()
-
-	var post models.Post
-	if err := tx.First(&post, postID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+func (h *Handler) DeletePostHandler(c *gin.Context) {
+	postID, ok := postIDParam(c)
+	if !ok {
 		return
 	}
 
-	var existingLike models.Like
-	err := tx.Where("post_id = ? AND user_id = ?", post.ID, currentUser.ID).First(&existingLike).Error
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
 
-	if err == nil {
-		if err := tx.Delete(&existingLike).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlike post"})
+	if err := h.postsFor(c).DeletePost(currentUser.ID, postID); err != nil {
+		if errors.Is(err, ErrNotOwner) {
+			apierror.Forbidden(c, "You can only delete your own posts")
 			return
 		}
+		writeError(c, err, "Failed to delete post")
+		return
+	}
 
-		// İlgili bildirimi sil
-		tx.Where("actor_id = ? AND post_id = ? AND type = ?",
-			currentUser.ID, post.ID, models.NotificationPostLike).
-			Delete(&models.Notification{})
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post deleted successfully",
+	})
+}
 
-		tx.Commit()
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Post unliked successfully",
-			"liked":   false,
-		})
+func (h *Handler) LikePostHandler(c *gin.Context) {
+	postID, ok := postIDParam(c)
+	if !ok {
 		return
 	}
 
-	like := models.Like{
-		PostID: post.ID,
-		UserID: currentUser.ID,
-	}
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
 
-	if err := tx.Create(&like).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to like post"})
+	liked, err := h.postsFor(c).Like(currentUser.ID, postID)
+	if err != nil {
+		writeError(c, err, "Failed to update like")
 		return
 	}
 
-	if post.UserID != currentUser.ID {
-		if err := notification.CreateNotification(
-			post.UserID,
-			currentUser.ID,
-			models.NotificationPostLike,
-			&post.ID,
-			nil,
-		); err != nil {
-			log.Printf("Failed to create notification: %v", err)
-		}
+	message := "Post unliked successfully"
+	if liked {
+		message = "Post liked successfully"
 	}
-
-	tx.Commit()
-
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Post liked successfully",
-		"liked":   true,
+		"message": message,
+		"liked":   liked,
 	})
 }
 
-func CreateCommentHandler(c *gin.Context) {
-	postID := c.Param("id")
+func (h *Handler) CreateCommentHandler(c *gin.Context) {
+	postID, ok := postIDParam(c)
+	if !ok {
+		return
+	}
+
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
 	var input CreateCommentInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	tx := database.DB.Begin()
-
-	var post models.Post
-	if err := tx.First(&post, postID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
-		return
-	}
-
-	comment := models.Comment{
-		PostID:  post.ID,
-		UserID:  currentUser.ID,
-		Content: input.Content,
-	}
-
-	if err := tx.Create(&comment).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+		apierror.Validation(c, err)
 		return
 	}
 
-	if err := tx.Preload("User").First(&comment, comment.ID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load comment"})
+	comment, err := h.postsFor(c).Comment(currentUser.ID, postID, input)
+	if err != nil {
+		writeError(c, err, "Failed to create comment")
 		return
 	}
 
-	if post.UserID != currentUser.ID {
-		if err := notification.CreateNotification(
-			post.UserID,
-			currentUser.ID,
-			models.NotificationComment,
-			&post.ID,
-			&comment.ID,
-		); err != nil {
-			log.Printf("Failed to create notification: %v", err)
-		}
-	}
-
-	tx.Commit()
-
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Comment added successfully",
-		"comment": comment,
+		"comment": comment.Response(),
 	})
 }
 
-func IncrementViewHandler(c *gin.Context) {
-	postID := c.Param("id")
+// Postu kaydetme / kayıttan çıkarma
+func (h *Handler) SavePostHandler(c *gin.Context) {
+	postID, ok := postIDParam(c)
+	if !ok {
+		return
+	}
+
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	tx := database.DB.Begin()
-
-	var post models.Post
-	if err := tx.First(&post, postID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+	saved, err := h.postsFor(c).Save(currentUser.ID, postID)
+	if err != nil {
+		writeError(c, err, "Failed to update saved posts")
 		return
 	}
 
-	var existingView models.PostView
-	isNewView := tx.Where("post_id = ? AND user_id = ?", post.ID, currentUser.ID).
-		First(&existingView).Error == gorm.ErrRecordNotFound
-
-	if isNewView {
-		view := models.PostView{
-			PostID: post.ID,
-			UserID: currentUser.ID,
-		}
-		if err := tx.Create(&view).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record view"})
-			return
-		}
-
-		if err := tx.Model(&post).Update("view_count", gorm.Expr("view_count + ?", 1)).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to increment view count"})
-			return
-		}
-
-		if err := tx.Model(&models.User{}).Where("id = ?", post.UserID).
-			Update("total_views", gorm.Expr("total_views + ?", 1)).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user total views"})
-			return
-		}
+	message := "Post removed from saved posts"
+	if saved {
+		message = "Post saved successfully"
 	}
-
-	tx.Commit()
-
 	c.JSON(http.StatusOK, gin.H{
-		"message":   "View count updated",
-		"isNewView": isNewView,
-		"viewCount": post.ViewCount + 1,
+		"message": message,
+		"saved":   saved,
 	})
 }
 
-func GetPersonalFeedHandler(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
+// Kullanıcının kaydettiği postlar, kaydedilme sırasına göre
+func (h *Handler) GetSavedPostsHandler(c *gin.Context) {
+	params, err := pagination.Parse(c)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid cursor")
+		return
+	}
 
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	// Takip edilen kullanıcıların postlarını getir
-	query := database.DB.Model(&models.Post{}).
-		Preload("User").
-		Preload("Products").
-		Preload("Categories").
-		Preload("Hashtags").
-		Preload("Likes").
-		Joins("JOIN user_followers uf ON posts.user_id = uf.following_id").
-		Where("uf.follower_id = ?", currentUser.ID).
-		Order("posts.created_at DESC")
-
-	// Toplam post sayısını al
-	var total int64
-	query.Count(&total)
-
-	// Postları getir
-	var posts []models.Post
-	if err := query.Limit(limit).Offset(offset).Find(&posts).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch feed posts"})
-		return
-	}
-
-	response := make([]map[string]interface{}, len(posts))
-	for i, post := range posts {
-		postResponse := post.Response()
-
-		// Like durumunu kontrol et
-		var isLiked bool
-		for _, like := range post.Likes {
-			if like.UserID == currentUser.ID {
-				isLiked = true
-				break
-			}
-		}
-		postResponse["isLiked"] = isLiked
-
-		response[i] = postResponse
+	saved, err := h.storeFor(c).ListSaved(currentUser.ID, params)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch saved posts")
+		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"posts": response,
-		"pagination": gin.H{
-			"current": page,
-			"limit":   limit,
-			"total":   total,
-			"pages":   (total + int64(limit) - 1) / int64(limit),
-		},
+	saved, page := pagination.Paginate(saved, params, func(s models.SavedPost) pagination.Cursor {
+		return pagination.TimeKey(s.CreatedAt, s.ID)
 	})
-}
-
-func GetSuggestedPostsHandler(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
 
-	user, _ := c.Get("user")
-	currentUser := user.(models.User)
+	postIDs := make([]uint, len(saved))
+	for i, s := range saved {
+		postIDs[i] = s.PostID
+	}
 
-	// Takip edilmeyen kullanıcıların popüler postlarını getir
-	query := database.DB.Model(&models.Post{}).
-		Preload("User").
-		Preload("Products").
-		Preload("Categories").
-		Preload("Hashtags").
-		Preload("Likes").
-		// Takip edilmeyen kullanıcıların postlarını seç
-		Where("posts.user_id NOT IN (?)",
-			database.DB.Table("user_followers").
-				Select("following_id").
-				Where("follower_id = ?", currentUser.ID)).
-		// Kendi postlarını hariç tut
-		Where("posts.user_id != ?", currentUser.ID).
-		// Son 7 günün popüler postları
-		Where("posts.created_at >= ?", time.Now().AddDate(0, 0, -7)).
-		// Popülerliğe göre sırala
-		Order("(posts.view_count * 0.6 + (SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id) * 0.4) DESC")
-
-	// Toplam post sayısını al
-	var total int64
-	query.Count(&total)
-
-	// Postları getir
-	var posts []models.Post
-	if err := query.Limit(limit).Offset(offset).Find(&posts).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suggested posts"})
-		return
-	}
-
-	response := make([]map[string]interface{}, len(posts))
-	for i, post := range posts {
-		postResponse := post.Response()
-
-		// Like durumunu kontrol et
-		var isLiked bool
-		for _, like := range post.Likes {
-			if like.UserID == currentUser.ID {
-				isLiked = true
-				break
-			}
-		}
-		postResponse["isLiked"] = isLiked
+	posts, err := h.storeFor(c).PostsByIDs(postIDs)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch saved posts")
+		return
+	}
 
-		response[i] = postResponse
+	// İzleyiciye özel durumları sayfa için toplu yükle
+	response, err := h.postResponses(c, currentUser.ID, posts)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch saved posts")
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"posts": response,
-		"pagination": gin.H{
-			"current": page,
-			"limit":   limit,
-			"total":   total,
-			"pages":   (total + int64(limit) - 1) / int64(limit),
-		},
+		"posts":      response,
+		"pagination": page,
 	})
 }
 
-func SearchPostsByHashtagHandler(c *gin.Context) {
-	tag := c.Param("tag")
-	normalizedTag := models.NormalizeHashtag(tag)
-
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
+func (h *Handler) IncrementViewHandler(c *gin.Context) {
+	postID, ok := postIDParam(c)
+	if !ok {
+		return
+	}
 
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	query := database.DB.Model(&models.Post{}).
-		Preload("User").
-		Preload("Products").
-		Preload("Categories").
-		Preload("Hashtags").
-		Preload("Likes").
-		Joins("JOIN post_hashtags ph ON ph.post_id = posts.id").
-		Joins("JOIN hashtags h ON h.id = ph.hashtag_id").
-		Where("h.name = ?", normalizedTag).
-		Order("posts.created_at DESC")
-
-	// Toplam post sayısını al
-	var total int64
-	query.Count(&total)
-
-	// Postları getir
-	var posts []models.Post
-	if err := query.Limit(limit).Offset(offset).Find(&posts).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
-		return
-	}
-
-	response := make([]map[string]interface{}, len(posts))
-	for i, post := range posts {
-		postResponse := post.Response()
-
-		// Like durumunu kontrol et
-		var isLiked bool
-		for _, like := range post.Likes {
-			if like.UserID == currentUser.ID {
-				isLiked = true
-				break
-			}
-		}
-		postResponse["isLiked"] = isLiked
-
-		response[i] = postResponse
+	view, err := h.postsFor(c).View(currentUser.ID, postID)
+	if err != nil {
+		writeError(c, err, "Failed to record view")
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"posts":   response,
-		"hashtag": normalizedTag,
-		"pagination": gin.H{
-			"current": page,
-			"limit":   limit,
-			"total":   total,
-			"pages":   (total + int64(limit) - 1) / int64(limit),
-		},
+		"message":   "View count updated",
+		"isNewView": view.IsNewView,
+		"viewCount": view.ViewCount,
 	})
 }
 
-func GetTrendingHashtagsHandler(c *gin.Context) {
-	type HashtagCount struct {
-		Name  string `json:"name"`
-		Count int    `json:"count"`
-	}
-
-	var trendingHashtags []HashtagCount
-
-	if err := database.DB.Raw(`
-        SELECT h.name, COUNT(DISTINCT ph.post_id) as count
-        FROM hashtags h
-        JOIN post_hashtags ph ON ph.hashtag_id = h.id
-        JOIN posts p ON p.id = ph.post_id
-        WHERE p.created_at >= NOW() - INTERVAL '7 days'
-        GROUP BY h.name
-        ORDER BY count DESC
-        LIMIT 10
-    `).Scan(&trendingHashtags).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trending hashtags"})
+func (h *Handler) GetPersonalFeedHandler(c *gin.Context) {
+	params, err := pagination.Parse(c)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid cursor")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"trendingHashtags": trendingHashtags})
-}
-
-func UpdatePostHandler(c *gin.Context) {
-	postID := c.Param("id")
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	// Post'u bul
-	var post models.Post
-	if err := database.DB.Preload("Products").
-		Preload("Categories").
-		Preload("Hashtags").
-		First(&post, postID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+	// Takip edilen kullanıcıların postları akıştan okunur
+	postIDs, page, err := h.feed.Read(c.Request.Context(), currentUser.ID, params)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch feed posts")
 		return
 	}
 
-	// Post sahibi kontrolü
-	if post.UserID != currentUser.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You can only update your own posts"})
+	posts, err := h.storeFor(c).FeedPostsByIDs(currentUser.ID, postIDs)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch feed posts")
 		return
 	}
 
-	var input CreatePostInput
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	// İzleyiciye özel durumları sayfa için toplu yükle
+	response, err := h.postResponses(c, currentUser.ID, posts)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch feed posts")
 		return
 	}
 
-	tx := database.DB.Begin()
-
-	// Description güncelle
-	if input.Description != "" {
-		post.Description = input.Description
-	}
-
-	// ImageURL güncelle
-	if input.ImageURL != "" {
-		post.ImageURL = input.ImageURL
-	}
-
-	// Kategorileri güncelle
-	if len(input.CategoryIDs) > 0 {
-		var categories []models.Category
-		if err := tx.Find(&categories, input.CategoryIDs).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category IDs"})
-			return
-		}
-
-		if err := tx.Model(&post).Association("Categories").Replace(categories); err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update categories"})
-			return
-		}
-	}
-
-	// Ürünleri güncelle
-	if len(input.Products) > 0 {
-		if len(input.Products) > models.MaxProductsPerPost {
-			tx.Rollback()
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":        "maximum 8 products can be added to a post",
-				"currentCount": len(input.Products),
-				"maxAllowed":   models.MaxProductsPerPost,
-			})
-			return
-		}
-
-		// Mevcut ürünleri temizle
-		if err := tx.Model(&post).Association("Products").Clear(); err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear products"})
-			return
-		}
-
-		// Yeni ürünleri ekle
-		var newProducts []models.Product
-		for _, p := range input.Products {
-			product := models.Product{
-				Name:        p.Name,
-				Price:       p.Price,
-				Link:        p.Link,
-				Description: p.Description,
-			}
-			newProducts = append(newProducts, product)
-		}
+	c.JSON(http.StatusOK, gin.H{
+		"posts":      response,
+		"pagination": page,
+	})
+}
 
-		if err := tx.Create(&newProducts).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create products"})
-			return
-		}
+func (h *Handler) GetSuggestedPostsHandler(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	limit = pagination.ClampLimit(limit)
 
-		if err := tx.Model(&post).Association("Products").Replace(newProducts); err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update products"})
+	var cursor *ranking.Cursor
+	if value := c.Query("cursor"); value != "" {
+		var err error
+		if cursor, err = ranking.DecodeCursor(value); err != nil {
+			apierror.BadRequest(c, "Invalid cursor")
 			return
 		}
 	}
 
-	// Hashtag'leri güncelle
-	if len(input.Hashtags) > 0 {
-		// Mevcut hashtag'leri temizle
-		if err := tx.Model(&post).Association("Hashtags").Clear(); err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear hashtags"})
-			return
-		}
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
 
-		// Yeni hashtag'leri ekle
-		for _, tagName := range input.Hashtags {
-			normalizedTag := models.NormalizeHashtag(tagName)
-			if normalizedTag == "" {
-				continue
-			}
-
-			var hashtag models.Hashtag
-			err := tx.Where("name = ?", normalizedTag).FirstOrCreate(&hashtag, models.Hashtag{
-				Name: normalizedTag,
-			}).Error
-			if err != nil {
-				tx.Rollback()
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process hashtags"})
-				return
-			}
-
-			if err := tx.Model(&post).Association("Hashtags").Append(&hashtag); err != nil {
-				tx.Rollback()
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add hashtag"})
-				return
-			}
-		}
+	// Zaman azalımlı etkileşim, ilgi alanı ve çeşitlilik kurallarıyla sıralanmış postlar
+	postIDs, next, err := h.storeFor(c).Suggested(currentUser.ID, cursor, limit)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch suggested posts")
+		return
 	}
 
-	// Post'u kaydet
-	if err := tx.Save(&post).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+	posts, err := h.storeFor(c).PostsByIDs(postIDs)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch suggested posts")
 		return
 	}
 
-	// İlişkili verileri yükle
-	if err := tx.Preload("User").
-		Preload("Products").
-		Preload("Categories").
-		Preload("Hashtags").
-		First(&post, post.ID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load updated post"})
+	response, err := h.postResponses(c, currentUser.ID, posts)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch suggested posts")
 		return
 	}
 
-	tx.Commit()
+	page := pagination.Page{Limit: limit}
+	if next != nil {
+		encoded := next.Encode()
+		page.NextCursor = &encoded
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Post updated successfully",
-		"post":    post.Response(),
+		"posts":      response,
+		"pagination": page,
 	})
 }
 
-func RedirectHandler(c *gin.Context) {
-	trackingID := c.Param("tracking_id") // cmf_3_7_12 gibi bir değer alacağız
-
-	// tracking_id'den bilgileri parse et
-	parts := strings.Split(trackingID, "_")
-	if len(parts) != 4 || parts[0] != "cmf" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tracking ID"})
-		return
-	}
+func (h *Handler) SearchPostsByHashtagHandler(c *gin.Context) {
+	tag := c.Param("tag")
+	normalizedTag := models.NormalizeHashtag(tag)
 
-	// Affiliate linki bul
-	var affiliateLink models.AffiliateLink
-	if err := database.DB.Where(
-		"user_id = ? AND post_id = ? AND product_id = ?",
-		parts[1], parts[2], parts[3],
-	).First(&affiliateLink).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+	params, err := pagination.Parse(c)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid cursor")
 		return
 	}
 
-	// Transaction başlat
-	tx := database.DB.Begin()
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
 
-	// Click log oluştur
-	clickLog := models.ClickLog{
-		AffiliateLinkID: affiliateLink.ID,
-		IP:              c.ClientIP(),
-		UserAgent:       c.Request.UserAgent(),
-		RefererURL:      c.Request.Referer(),
+	posts, err := h.storeFor(c).ListByHashtag(currentUser.ID, normalizedTag, params)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch posts")
+		return
 	}
+	posts, page := pagination.Paginate(posts, params, postKey)
 
-	// Eğer giriş yapmış bir kullanıcı tıkladıysa
-	if user, exists := c.Get("user"); exists {
-		currentUser := user.(models.User)
-		clickLog.UserID = &currentUser.ID
+	// İzleyiciye özel durumları sayfa için toplu yükle
+	response, err := h.postResponses(c, currentUser.ID, posts)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch posts")
+		return
 	}
 
-	// Click logu kaydet
-	if err := tx.Create(&clickLog).Error; err != nil {
-		tx.Rollback()
-		log.Printf("Failed to create click log: %v", err)
-		// Log hatası olsa bile yönlendirmeye devam et
-	}
+	c.JSON(http.StatusOK, gin.H{
+		"posts":      response,
+		"hashtag":    normalizedTag,
+		"pagination": page,
+	})
+}
 
-	// Tıklanma sayısını artır
-	if err := tx.Model(&affiliateLink).
-		Update("click_count", gorm.Expr("click_count + ?", 1)).
-		Error; err != nil {
-		tx.Rollback()
-		log.Printf("Failed to update click count: %v", err)
+func (h *Handler) GetTrendingHashtagsHandler(c *gin.Context) {
+	trendingHashtags, err := h.storeFor(c).TrendingHashtags()
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch trending hashtags")
+		return
 	}
 
-	tx.Commit()
-
-	// Orijinal URL'e yönlendir
-	c.Redirect(http.StatusTemporaryRedirect, affiliateLink.OriginalURL)
+	c.JSON(http.StatusOK, gin.H{"trendingHashtags": trendingHashtags})
 }
 
-func GetClickStatsHandler(c *gin.Context) {
+func (h *Handler) UpdatePostHandler(c *gin.Context) {
+	postID, ok := postIDParam(c)
+	if !ok {
+		return
+	}
+
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	var links []models.AffiliateLink
-	if err := database.DB.Where("user_id = ?", currentUser.ID).
-		Preload("Product").
-		Preload("Post").
-		Preload("ClickLogs", func(db *gorm.DB) *gorm.DB {
-			return db.Order("created_at DESC").Limit(10) // Son 10 tıklama
-		}).
-		Find(&links).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch click stats"})
-		return
-	}
-
-	stats := make([]map[string]interface{}, len(links))
-	for i, link := range links {
-		stats[i] = map[string]interface{}{
-			"trackingUrl":     link.TrackingURL,
-			"originalUrl":     link.OriginalURL,
-			"productName":     link.Product.Name,
-			"postDescription": link.Post.Description,
-			"clickCount":      link.ClickCount,
-			"recentClicks":    link.ClickLogs,
-			"createdAt":       link.CreatedAt,
+	var input UpdatePostInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierror.Validation(c, err)
+		return
+	}
+
+	post, err := h.postsFor(c).UpdatePost(currentUser.ID, postID, input)
+	if err != nil {
+		if errors.Is(err, ErrNotOwner) {
+			apierror.Forbidden(c, "You can only update your own posts")
+			return
 		}
+		writeError(c, err, "Failed to update post")
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"stats":      stats,
-		"totalLinks": len(links),
+		"message": "Post updated successfully",
+		"post":    post.Response(),
 	})
 }
@@ -0,0 +1,43 @@
+// internal/post/list.go
+package post
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/pagination"
+)
+
+// Post listelerinde kullanılan keyset sıralaması (en yeni önce)
+var postOrder = pagination.Order{Column: "posts.created_at", IDColumn: "posts.id"}
+
+func postKey(post models.Post) pagination.Cursor {
+	return pagination.TimeKey(post.CreatedAt, post.ID)
+}
+
+func commentKey(comment models.Comment) pagination.Cursor {
+	return pagination.TimeKey(comment.CreatedAt, comment.ID)
+}
+
+func (h *Handler) listComments(c *gin.Context, postID uint, params pagination.Params) ([]models.CommentResponse, pagination.Page, error) {
+	comments, err := h.storeFor(c).Comments(postID, params)
+	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	comments, page := pagination.Paginate(comments, params, commentKey)
+	return models.CommentResponses(comments), page, nil
+}
+
+// Postları izleyici durumlarıyla birlikte yanıta çevirir
+func (h *Handler) postResponses(c *gin.Context, viewerID uint, posts []models.Post) ([]models.PostResponse, error) {
+	state, err := h.storeFor(c).ViewerState(viewerID, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]models.PostResponse, len(posts))
+	for i := range posts {
+		response[i] = state.Apply(posts[i].Response(), &posts[i])
+	}
+	return response, nil
+}
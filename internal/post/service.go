@@ -0,0 +1,476 @@
+// internal/post/service.go
+package post
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/internal/notification"
+	"github.com/sefazor/comfyn/pkg/jobs"
+)
+
+// Post yazma işlemlerinin akışa yansıtılması; işler postu yazan
+// transaction'ın kuyruğuna eklenir
+type Timeline interface {
+	PublishAsync(ctx context.Context, q *jobs.Queue, post models.Post) error
+	Remove(ctx context.Context, q *jobs.Queue, postID uint) error
+}
+
+// Post oluşturma, güncelleme ve etkileşim işlemleri. HTTP handler'ları ve
+// toplu içe aktarma gibi araçlar aynı kuralları bu servis üzerinden uygular.
+type Service struct {
+	store         Store
+	notifications notification.Notifier
+	timeline      Timeline
+	queue         *jobs.Queue
+	// Kuyruğa eklenen bildirim ve akış işleri bu ctx'teki trace'e bağlanır
+	ctx context.Context
+}
+
+func NewService(store Store, notifications notification.Notifier, timeline Timeline, queue *jobs.Queue) *Service {
+	return &Service{store: store, notifications: notifications, timeline: timeline, queue: queue, ctx: context.Background()}
+}
+
+// Store sorgularını ctx'e bağlayan kopya; handler'lar istek context'iyle kullanır
+func (s *Service) WithContext(ctx context.Context) *Service {
+	copy := *s
+	copy.store = s.store.WithContext(ctx)
+	copy.ctx = ctx
+	return &copy
+}
+
+func (s *Service) CreatePost(userID uint, input CreatePostInput) (*models.Post, error) {
+	if len(input.Products) > models.MaxProductsPerPost {
+		return nil, &TooManyProductsError{Count: len(input.Products)}
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	categories, err := findCategories(tx, input.CategoryIDs, ErrInvalidCategories)
+	if err != nil {
+		return nil, err
+	}
+
+	hashtags, err := resolveHashtags(tx, input.Hashtags)
+	if err != nil {
+		return nil, err
+	}
+
+	// Önce post'u oluştur (ürünlerin tracking URL'lerinde post ID'ye ihtiyacımız var)
+	post := models.Post{
+		UserID:      userID,
+		ImageURL:    input.ImageURL,
+		Description: input.Description,
+		Categories:  categories,
+		Hashtags:    hashtags,
+	}
+	if err := tx.CreatePost(&post); err != nil {
+		return nil, fmt.Errorf("create post: %w", err)
+	}
+
+	products, err := createProducts(tx, &post, input.Products)
+	if err != nil {
+		return nil, err
+	}
+
+	// Post'a ürünleri ekle
+	post.Products = products
+	if err := tx.SavePost(&post); err != nil {
+		return nil, fmt.Errorf("save post products: %w", err)
+	}
+
+	// Takipçilerin akışlarına yaz
+	if err := s.timeline.PublishAsync(s.ctx, tx.Jobs(s.queue), post); err != nil {
+		return nil, err
+	}
+
+	return finish(tx, post.ID)
+}
+
+func (s *Service) UpdatePost(userID, postID uint, input UpdatePostInput) (*models.Post, error) {
+	if len(input.Products) > models.MaxProductsPerPost {
+		return nil, &TooManyProductsError{Count: len(input.Products)}
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	post, err := tx.LoadPost(postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID != userID {
+		return nil, ErrNotOwner
+	}
+
+	if input.Description != "" {
+		post.Description = input.Description
+	}
+	if input.ImageURL != "" {
+		post.ImageURL = input.ImageURL
+	}
+
+	if len(input.CategoryIDs) > 0 {
+		categories, err := findCategories(tx, input.CategoryIDs, ErrInvalidCategories)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.ReplaceCategories(post, categories); err != nil {
+			return nil, fmt.Errorf("replace categories: %w", err)
+		}
+	}
+
+	if len(input.Products) > 0 {
+		products, err := createProducts(tx, post, input.Products)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.ReplaceProducts(post, products); err != nil {
+			return nil, fmt.Errorf("replace products: %w", err)
+		}
+	}
+
+	if len(input.Hashtags) > 0 {
+		hashtags, err := resolveHashtags(tx, input.Hashtags)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.ReplaceHashtags(post, hashtags); err != nil {
+			return nil, fmt.Errorf("replace hashtags: %w", err)
+		}
+	}
+
+	if err := tx.SavePost(post); err != nil {
+		return nil, fmt.Errorf("save post: %w", err)
+	}
+
+	return finish(tx, post.ID)
+}
+
+func (s *Service) DeletePost(userID, postID uint) error {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	post, err := tx.FindPost(postID)
+	if err != nil {
+		return err
+	}
+	if post.UserID != userID {
+		return ErrNotOwner
+	}
+
+	if err := tx.DeletePost(post); err != nil {
+		return fmt.Errorf("delete post: %w", err)
+	}
+	if err := s.timeline.Remove(s.ctx, tx.Jobs(s.queue), post.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Beğeniyi açıp kapatır ve yeni durumu döner
+func (s *Service) Like(userID, postID uint) (bool, error) {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	post, err := interactablePost(tx, userID, postID)
+	if err != nil {
+		return false, err
+	}
+
+	unliked, err := tx.DeleteLike(post.ID, userID)
+	if err != nil {
+		return false, fmt.Errorf("delete like: %w", err)
+	}
+
+	if unliked {
+		if err := tx.AdjustCounter(post.ID, LikeCounter, -1); err != nil {
+			return false, err
+		}
+
+		// İlgili bildirimi sil
+		if err := tx.DeleteLikeNotification(userID, post.ID); err != nil {
+			return false, fmt.Errorf("delete like notification: %w", err)
+		}
+
+		return false, tx.Commit()
+	}
+
+	if err := tx.CreateLike(&models.Like{PostID: post.ID, UserID: userID}); err != nil {
+		return false, fmt.Errorf("create like: %w", err)
+	}
+	if err := tx.AdjustCounter(post.ID, LikeCounter, 1); err != nil {
+		return false, err
+	}
+	if err := s.notifyAuthor(tx, post, userID, models.NotificationPostLike, nil); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (s *Service) Comment(userID, postID uint, input CreateCommentInput) (*models.Comment, error) {
+	if strings.TrimSpace(input.Content) == "" {
+		return nil, ErrEmptyComment
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	post, err := interactablePost(tx, userID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := models.Comment{
+		PostID:  post.ID,
+		UserID:  userID,
+		Content: input.Content,
+	}
+	if err := tx.CreateComment(&comment); err != nil {
+		return nil, fmt.Errorf("create comment: %w", err)
+	}
+	if err := tx.AdjustCounter(post.ID, CommentCounter, 1); err != nil {
+		return nil, err
+	}
+
+	created, err := tx.LoadComment(comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.notifyAuthor(tx, post, userID, models.NotificationComment, &created.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Kaydetmeyi açıp kapatır ve yeni durumu döner
+func (s *Service) Save(userID, postID uint) (bool, error) {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	post, err := interactablePost(tx, userID, postID)
+	if err != nil {
+		return false, err
+	}
+
+	removed, err := tx.DeleteSaved(post.ID, userID)
+	if err != nil {
+		return false, fmt.Errorf("delete saved post: %w", err)
+	}
+
+	delta := -1
+	if !removed {
+		if err := tx.CreateSaved(&models.SavedPost{PostID: post.ID, UserID: userID}); err != nil {
+			return false, fmt.Errorf("create saved post: %w", err)
+		}
+		delta = 1
+	}
+
+	if err := tx.AdjustCounter(post.ID, SaveCounter, delta); err != nil {
+		return false, err
+	}
+	return !removed, tx.Commit()
+}
+
+// Kullanıcının görebildiği postu kategori, ürün ve hashtag'leriyle yükler
+func (s *Service) Get(userID, postID uint) (*models.Post, error) {
+	if _, err := visiblePost(s.store, userID, postID); err != nil {
+		return nil, err
+	}
+	return s.store.LoadPost(postID)
+}
+
+// Kullanıcının görebildiği postu ilişkileri olmadan yükler
+func (s *Service) Find(userID, postID uint) (*models.Post, error) {
+	return visiblePost(s.store, userID, postID)
+}
+
+// Kullanıcının postu ilk görüntülemesini sayar
+func (s *Service) View(userID, postID uint) (*ViewResult, error) {
+	post, err := visiblePost(s.store, userID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	isNewView, err := s.store.RecordView(post, userID)
+	if err != nil {
+		return nil, fmt.Errorf("record view: %w", err)
+	}
+
+	result := &ViewResult{IsNewView: isNewView, ViewCount: post.ViewCount}
+	if isNewView {
+		result.ViewCount++
+	}
+	return result, nil
+}
+
+// Bildirim işi etkileşimle aynı transaction'da kuyruğa eklenir
+func (s *Service) notifyAuthor(tx Tx, post *models.Post, actorID uint, notificationType models.NotificationType, commentID *uint) error {
+	if post.UserID == actorID {
+		return nil
+	}
+	return s.notifications.Notify(s.ctx, tx.Jobs(s.queue), post.UserID, actorID, notificationType, &post.ID, commentID)
+}
+
+// Postu kullanıcının görebildiği durumda yükler. Gizlenmiş ya da kapatılmış
+// hesaba ait post sahibi dışındakiler için yok sayılır; gizli hesabın
+// postunu yalnızca takipçiler görebilir.
+func visiblePost(store Store, userID, postID uint) (*models.Post, error) {
+	post, err := store.FindPost(postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID == userID {
+		return post, nil
+	}
+
+	if post.HiddenAt != nil || post.User.DeactivatedAt != nil {
+		return nil, ErrNotFound
+	}
+
+	canView, err := store.CanViewPosts(userID, post.User)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrPrivateAccount
+	}
+	return post, nil
+}
+
+// Görünür postu yükler; iki taraftan biri diğerini engellediyse etkileşime izin vermez
+func interactablePost(tx Tx, userID, postID uint) (*models.Post, error) {
+	post, err := visiblePost(tx, userID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := tx.IsBlockedBetween(userID, post.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, ErrCannotInteract
+	}
+	return post, nil
+}
+
+// Kategorilerin hepsi bulunamazsa invalid hatasını döner
+func findCategories(tx Tx, ids []uint, invalid error) ([]models.Category, error) {
+	categories, err := tx.FindCategories(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	unique := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+	if len(categories) != len(unique) {
+		return nil, invalid
+	}
+	return categories, nil
+}
+
+// Hashtag'leri normalize eder, tekrarları ve boşları atar, olmayanları oluşturur
+func resolveHashtags(tx Tx, names []string) ([]models.Hashtag, error) {
+	seen := make(map[string]bool, len(names))
+	var hashtags []models.Hashtag
+	for _, name := range names {
+		normalized := models.NormalizeHashtag(name)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+
+		hashtag, err := tx.FindOrCreateHashtag(normalized)
+		if err != nil {
+			return nil, fmt.Errorf("process hashtag %q: %w", normalized, err)
+		}
+		hashtags = append(hashtags, *hashtag)
+	}
+	return hashtags, nil
+}
+
+// Ürünleri kategorileriyle oluşturur ve her biri için affiliate link üretir
+func createProducts(tx Tx, post *models.Post, inputs []ProductInput) ([]models.Product, error) {
+	products := make([]models.Product, 0, len(inputs))
+	for _, input := range inputs {
+		categories, err := findCategories(tx, input.CategoryIDs, ErrInvalidProductCategories)
+		if err != nil {
+			return nil, err
+		}
+
+		product := models.Product{
+			Name:        input.Name,
+			Price:       input.Price,
+			Link:        input.Link,
+			Description: input.Description,
+			Categories:  categories,
+		}
+		if err := tx.CreateProduct(&product); err != nil {
+			return nil, fmt.Errorf("create product: %w", err)
+		}
+
+		affiliateLink := models.AffiliateLink{
+			UserID:      post.UserID,
+			PostID:      post.ID,
+			ProductID:   product.ID,
+			OriginalURL: product.Link,
+			TrackingURL: fmt.Sprintf("https://comfyn.com/go/cmf_%d_%d_%d", post.UserID, post.ID, product.ID),
+		}
+		if err := tx.CreateAffiliateLink(&affiliateLink); err != nil {
+			return nil, fmt.Errorf("create affiliate link: %w", err)
+		}
+
+		// Product'a tracking URL'i ekle
+		product.TrackingURL = affiliateLink.TrackingURL
+		if err := tx.SaveProduct(&product); err != nil {
+			return nil, fmt.Errorf("save product: %w", err)
+		}
+
+		products = append(products, product)
+	}
+	return products, nil
+}
+
+// Arama indeksini günceller, postu ilişkileriyle yükler ve transaction'ı tamamlar
+func finish(tx Tx, postID uint) (*models.Post, error) {
+	if err := tx.RefreshSearchIndex(postID); err != nil {
+		return nil, fmt.Errorf("index post: %w", err)
+	}
+
+	post, err := tx.LoadPost(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
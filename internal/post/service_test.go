@@ -0,0 +1,741 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/jobs"
+)
+
+// Postları, etkileşimleri ve sayaçları haritalarda tutan Store. Tx aynı
+// haritalara yazar, yani Rollback hiçbir şeyi geri almaz; testler hatalı
+// akışlarda finish'e ulaşılmadığını indexed üzerinden kontrol eder.
+type fakeStore struct {
+	Store
+
+	posts      map[uint]*models.Post
+	categories map[uint]models.Category
+	hashtags   map[string]*models.Hashtag
+	products   []models.Product
+	links      []models.AffiliateLink
+	indexed    []uint
+
+	likes     map[[2]uint]bool
+	saved     map[[2]uint]bool
+	views     map[[2]uint]bool
+	comments  map[uint]*models.Comment
+	counters  map[uint]map[Counter]int
+	blocked   map[[2]uint]bool
+	following map[[2]uint]bool
+
+	deletedPosts          []uint
+	deletedNotifications  int
+	deleteNotificationErr error
+}
+
+func newFakeStore(posts ...models.Post) *fakeStore {
+	s := &fakeStore{
+		posts:      make(map[uint]*models.Post),
+		categories: make(map[uint]models.Category),
+		hashtags:   make(map[string]*models.Hashtag),
+		likes:      make(map[[2]uint]bool),
+		saved:      make(map[[2]uint]bool),
+		views:      make(map[[2]uint]bool),
+		comments:   make(map[uint]*models.Comment),
+		counters:   make(map[uint]map[Counter]int),
+		blocked:    make(map[[2]uint]bool),
+		following:  make(map[[2]uint]bool),
+	}
+	for i := range posts {
+		s.posts[posts[i].ID] = &posts[i]
+	}
+	return s
+}
+
+type fakeTx struct {
+	*fakeStore
+}
+
+func (s *fakeStore) Begin() (Tx, error) { return fakeTx{s}, nil }
+
+func (t fakeTx) Jobs(queue *jobs.Queue) *jobs.Queue { return queue }
+func (t fakeTx) Commit() error                      { return nil }
+func (t fakeTx) Rollback() error                    { return nil }
+
+func (s *fakeStore) FindPost(id uint) (*models.Post, error) {
+	post, ok := s.posts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copy := *post
+	return &copy, nil
+}
+
+func (s *fakeStore) LoadPost(id uint) (*models.Post, error) {
+	return s.FindPost(id)
+}
+
+func (s *fakeStore) FindCategories(ids []uint) ([]models.Category, error) {
+	var categories []models.Category
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if category, ok := s.categories[id]; ok && !seen[id] {
+			seen[id] = true
+			categories = append(categories, category)
+		}
+	}
+	return categories, nil
+}
+
+func (s *fakeStore) FindOrCreateHashtag(name string) (*models.Hashtag, error) {
+	if hashtag, ok := s.hashtags[name]; ok {
+		return hashtag, nil
+	}
+	hashtag := &models.Hashtag{ID: uint(len(s.hashtags) + 1), Name: name}
+	s.hashtags[name] = hashtag
+	return hashtag, nil
+}
+
+func (s *fakeStore) CreatePost(post *models.Post) error {
+	post.ID = uint(len(s.posts) + postID)
+	return s.SavePost(post)
+}
+
+func (s *fakeStore) SavePost(post *models.Post) error {
+	copy := *post
+	s.posts[post.ID] = &copy
+	return nil
+}
+
+func (s *fakeStore) ReplaceCategories(post *models.Post, categories []models.Category) error {
+	post.Categories = categories
+	return nil
+}
+
+func (s *fakeStore) ReplaceProducts(post *models.Post, products []models.Product) error {
+	post.Products = products
+	return nil
+}
+
+func (s *fakeStore) ReplaceHashtags(post *models.Post, hashtags []models.Hashtag) error {
+	post.Hashtags = hashtags
+	return nil
+}
+
+func (s *fakeStore) RefreshSearchIndex(postID uint) error {
+	s.indexed = append(s.indexed, postID)
+	return nil
+}
+
+func (s *fakeStore) CreateProduct(product *models.Product) error {
+	product.ID = uint(len(s.products) + 1)
+	s.products = append(s.products, *product)
+	return nil
+}
+
+func (s *fakeStore) SaveProduct(product *models.Product) error {
+	s.products[product.ID-1] = *product
+	return nil
+}
+
+func (s *fakeStore) CreateAffiliateLink(link *models.AffiliateLink) error {
+	s.links = append(s.links, *link)
+	return nil
+}
+
+func (s *fakeStore) DeletePost(post *models.Post) error {
+	delete(s.posts, post.ID)
+	s.deletedPosts = append(s.deletedPosts, post.ID)
+	return nil
+}
+
+func (s *fakeStore) DeleteLike(postID, userID uint) (bool, error) {
+	key := [2]uint{postID, userID}
+	existed := s.likes[key]
+	delete(s.likes, key)
+	return existed, nil
+}
+
+func (s *fakeStore) CreateLike(like *models.Like) error {
+	s.likes[[2]uint{like.PostID, like.UserID}] = true
+	return nil
+}
+
+func (s *fakeStore) DeleteLikeNotification(actorID, postID uint) error {
+	if s.deleteNotificationErr != nil {
+		return s.deleteNotificationErr
+	}
+	s.deletedNotifications++
+	return nil
+}
+
+func (s *fakeStore) AdjustCounter(postID uint, counter Counter, delta int) error {
+	if s.counters[postID] == nil {
+		s.counters[postID] = make(map[Counter]int)
+	}
+	s.counters[postID][counter] = max(s.counters[postID][counter]+delta, 0)
+	return nil
+}
+
+func (s *fakeStore) CreateComment(comment *models.Comment) error {
+	comment.ID = uint(len(s.comments) + 1)
+	s.comments[comment.ID] = comment
+	return nil
+}
+
+func (s *fakeStore) LoadComment(id uint) (*models.Comment, error) {
+	comment, ok := s.comments[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return comment, nil
+}
+
+func (s *fakeStore) DeleteSaved(postID, userID uint) (bool, error) {
+	key := [2]uint{postID, userID}
+	existed := s.saved[key]
+	delete(s.saved, key)
+	return existed, nil
+}
+
+func (s *fakeStore) CreateSaved(saved *models.SavedPost) error {
+	s.saved[[2]uint{saved.PostID, saved.UserID}] = true
+	return nil
+}
+
+func (s *fakeStore) RecordView(post *models.Post, userID uint) (bool, error) {
+	key := [2]uint{post.ID, userID}
+	if s.views[key] {
+		return false, nil
+	}
+	s.views[key] = true
+	s.posts[post.ID].ViewCount++
+	return true, nil
+}
+
+func (s *fakeStore) IsBlockedBetween(userID, otherID uint) (bool, error) {
+	return s.blocked[[2]uint{userID, otherID}] || s.blocked[[2]uint{otherID, userID}], nil
+}
+
+func (s *fakeStore) CanViewPosts(viewerID uint, owner models.User) (bool, error) {
+	if !owner.IsPrivate || owner.ID == viewerID {
+		return true, nil
+	}
+	return s.following[[2]uint{viewerID, owner.ID}], nil
+}
+
+type sentNotification struct {
+	userID, actorID  uint
+	notificationType models.NotificationType
+	commentID        *uint
+}
+
+type fakeNotifier struct {
+	sent []sentNotification
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, q *jobs.Queue, userID, actorID uint, notificationType models.NotificationType, postID, commentID *uint) error {
+	n.sent = append(n.sent, sentNotification{userID, actorID, notificationType, commentID})
+	return nil
+}
+
+type fakeTimeline struct {
+	published []uint
+	removed   []uint
+}
+
+func (t *fakeTimeline) PublishAsync(ctx context.Context, q *jobs.Queue, post models.Post) error {
+	t.published = append(t.published, post.ID)
+	return nil
+}
+
+func (t *fakeTimeline) Remove(ctx context.Context, q *jobs.Queue, postID uint) error {
+	t.removed = append(t.removed, postID)
+	return nil
+}
+
+const (
+	authorID = 1
+	viewerID = 2
+	postID   = 10
+)
+
+func authorPost(author models.User) models.Post {
+	author.ID = authorID
+	return models.Post{ID: postID, UserID: authorID, User: author}
+}
+
+func newTestService(store *fakeStore) (*Service, *fakeNotifier, *fakeTimeline) {
+	notifier, timeline := &fakeNotifier{}, &fakeTimeline{}
+	return NewService(store, notifier, timeline, nil), notifier, timeline
+}
+
+func TestLikeTogglesLikeAndCounter(t *testing.T) {
+	store := newFakeStore(authorPost(models.User{}))
+	service, notifier, _ := newTestService(store)
+
+	liked, err := service.Like(viewerID, postID)
+	if err != nil || !liked {
+		t.Fatalf("Like = %v, %v; want true, nil", liked, err)
+	}
+	if got := store.counters[postID][LikeCounter]; got != 1 {
+		t.Errorf("like_count = %d, want 1", got)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0].userID != authorID ||
+		notifier.sent[0].notificationType != models.NotificationPostLike {
+		t.Errorf("notifications = %+v, want one post_like for the author", notifier.sent)
+	}
+
+	liked, err = service.Like(viewerID, postID)
+	if err != nil || liked {
+		t.Fatalf("second Like = %v, %v; want false, nil", liked, err)
+	}
+	if got := store.counters[postID][LikeCounter]; got != 0 {
+		t.Errorf("like_count after unlike = %d, want 0", got)
+	}
+	if store.likes[[2]uint{postID, viewerID}] {
+		t.Error("like still stored after unlike")
+	}
+	if store.deletedNotifications != 1 {
+		t.Errorf("deleted notifications = %d, want 1", store.deletedNotifications)
+	}
+	if len(notifier.sent) != 1 {
+		t.Errorf("unlike sent a notification")
+	}
+}
+
+func TestUnlikeFailsWhenNotificationCannotBeDeleted(t *testing.T) {
+	store := newFakeStore(authorPost(models.User{}))
+	store.likes[[2]uint{postID, viewerID}] = true
+	store.deleteNotificationErr = errors.New("connection reset")
+	service, _, _ := newTestService(store)
+
+	if _, err := service.Like(viewerID, postID); !errors.Is(err, store.deleteNotificationErr) {
+		t.Errorf("Like error = %v, want the notification delete error", err)
+	}
+}
+
+func TestLikeOwnPostDoesNotNotify(t *testing.T) {
+	store := newFakeStore(authorPost(models.User{}))
+	service, notifier, _ := newTestService(store)
+
+	if _, err := service.Like(authorID, postID); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.sent) != 0 {
+		t.Errorf("notifications = %+v, want none", notifier.sent)
+	}
+}
+
+func TestInteractionsRespectVisibility(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		post    models.Post
+		setup   func(*fakeStore)
+		wantErr error
+	}{
+		{
+			name:    "blocked",
+			post:    authorPost(models.User{}),
+			setup:   func(s *fakeStore) { s.blocked[[2]uint{authorID, viewerID}] = true },
+			wantErr: ErrCannotInteract,
+		},
+		{
+			name:    "private account",
+			post:    authorPost(models.User{IsPrivate: true}),
+			wantErr: ErrPrivateAccount,
+		},
+		{
+			name: "hidden post",
+			post: func() models.Post {
+				post := authorPost(models.User{})
+				post.HiddenAt = &now
+				return post
+			}(),
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "deactivated author",
+			post:    authorPost(models.User{DeactivatedAt: &now}),
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "missing post",
+			post:    models.Post{ID: postID + 1},
+			wantErr: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeStore(tt.post)
+			if tt.setup != nil {
+				tt.setup(store)
+			}
+			service, notifier, _ := newTestService(store)
+
+			if _, err := service.Like(viewerID, postID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Like error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := service.Comment(viewerID, postID, CreateCommentInput{Content: "hi"}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Comment error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := service.Save(viewerID, postID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Save error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != ErrCannotInteract {
+				if _, err := service.View(viewerID, postID); !errors.Is(err, tt.wantErr) {
+					t.Errorf("View error = %v, want %v", err, tt.wantErr)
+				}
+			}
+
+			if len(store.likes)+len(store.comments)+len(store.saved)+len(store.views) != 0 || len(notifier.sent) != 0 {
+				t.Error("rejected interaction was stored")
+			}
+		})
+	}
+}
+
+func TestPrivateAccountFollowerCanInteract(t *testing.T) {
+	store := newFakeStore(authorPost(models.User{IsPrivate: true}))
+	store.following[[2]uint{viewerID, authorID}] = true
+	service, _, _ := newTestService(store)
+
+	if _, err := service.Like(viewerID, postID); err != nil {
+		t.Errorf("Like: %v", err)
+	}
+	if _, err := service.View(viewerID, postID); err != nil {
+		t.Errorf("View: %v", err)
+	}
+}
+
+func TestCommentUpdatesCounterAndNotifies(t *testing.T) {
+	store := newFakeStore(authorPost(models.User{}))
+	service, notifier, _ := newTestService(store)
+
+	if _, err := service.Comment(viewerID, postID, CreateCommentInput{Content: "  "}); !errors.Is(err, ErrEmptyComment) {
+		t.Fatalf("blank comment error = %v, want ErrEmptyComment", err)
+	}
+
+	comment, err := service.Comment(viewerID, postID, CreateCommentInput{Content: "nice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comment.PostID != postID || comment.UserID != viewerID || comment.Content != "nice" {
+		t.Errorf("comment = %+v", comment)
+	}
+	if got := store.counters[postID][CommentCounter]; got != 1 {
+		t.Errorf("comment_count = %d, want 1", got)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0].notificationType != models.NotificationComment ||
+		notifier.sent[0].commentID == nil || *notifier.sent[0].commentID != comment.ID {
+		t.Errorf("notifications = %+v, want one comment notification with the comment ID", notifier.sent)
+	}
+}
+
+func TestSaveToggles(t *testing.T) {
+	store := newFakeStore(authorPost(models.User{}))
+	service, notifier, _ := newTestService(store)
+
+	saved, err := service.Save(viewerID, postID)
+	if err != nil || !saved {
+		t.Fatalf("Save = %v, %v; want true, nil", saved, err)
+	}
+	if got := store.counters[postID][SaveCounter]; got != 1 {
+		t.Errorf("save_count = %d, want 1", got)
+	}
+
+	saved, err = service.Save(viewerID, postID)
+	if err != nil || saved {
+		t.Fatalf("second Save = %v, %v; want false, nil", saved, err)
+	}
+	if got := store.counters[postID][SaveCounter]; got != 0 {
+		t.Errorf("save_count after unsave = %d, want 0", got)
+	}
+	if len(notifier.sent) != 0 {
+		t.Errorf("saving sent notifications: %+v", notifier.sent)
+	}
+}
+
+func TestViewCountsOncePerUser(t *testing.T) {
+	store := newFakeStore(authorPost(models.User{}))
+	service, _, _ := newTestService(store)
+
+	first, err := service.View(viewerID, postID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.IsNewView || first.ViewCount != 1 {
+		t.Errorf("first view = %+v, want new view with count 1", first)
+	}
+
+	second, err := service.View(viewerID, postID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.IsNewView || second.ViewCount != 1 {
+		t.Errorf("second view = %+v, want repeat view with count 1", second)
+	}
+}
+
+func TestDeletePostRequiresOwner(t *testing.T) {
+	store := newFakeStore(authorPost(models.User{}))
+	service, _, timeline := newTestService(store)
+
+	if err := service.DeletePost(viewerID, postID); !errors.Is(err, ErrNotOwner) {
+		t.Fatalf("DeletePost by non-owner error = %v, want ErrNotOwner", err)
+	}
+	if len(store.deletedPosts) != 0 || len(timeline.removed) != 0 {
+		t.Fatal("post deleted by non-owner")
+	}
+
+	if err := service.DeletePost(authorID, postID); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.deletedPosts) != 1 || len(timeline.removed) != 1 || timeline.removed[0] != postID {
+		t.Errorf("deleted = %v, removed from timelines = %v", store.deletedPosts, timeline.removed)
+	}
+}
+
+const (
+	shoesID = 1
+	bagsID  = 2
+)
+
+func newCatalogStore(posts ...models.Post) *fakeStore {
+	store := newFakeStore(posts...)
+	store.categories[shoesID] = models.Category{ID: shoesID, Name: "Shoes"}
+	store.categories[bagsID] = models.Category{ID: bagsID, Name: "Bags"}
+	return store
+}
+
+func products(n int) []ProductInput {
+	inputs := make([]ProductInput, n)
+	for i := range inputs {
+		inputs[i] = ProductInput{Name: "Sneaker", Price: 100, Link: "https://shop.example/sneaker", CategoryIDs: []uint{shoesID}}
+	}
+	return inputs
+}
+
+func hashtagNames(post *models.Post) []string {
+	var names []string
+	for _, hashtag := range post.Hashtags {
+		names = append(names, hashtag.Name)
+	}
+	return names
+}
+
+func TestCreatePost(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       CreatePostInput
+		wantErr     error
+		wantTooMany int
+		check       func(t *testing.T, post *models.Post, store *fakeStore)
+	}{
+		{
+			name: "hashtags normalized and deduplicated",
+			input: CreatePostInput{
+				ImageURL:    "https://cdn.example/look.jpg",
+				Products:    products(1),
+				CategoryIDs: []uint{shoesID, shoesID, bagsID},
+				Hashtags:    []string{"#Summer", "summer", " ", "#", "Street Style"},
+			},
+			check: func(t *testing.T, post *models.Post, store *fakeStore) {
+				if got := hashtagNames(post); !slices.Equal(got, []string{"summer", "streetstyle"}) {
+					t.Errorf("hashtags = %v, want [summer streetstyle]", got)
+				}
+				if len(store.hashtags) != 2 {
+					t.Errorf("created %d hashtags, want 2", len(store.hashtags))
+				}
+				if len(post.Categories) != 2 {
+					t.Errorf("categories = %+v, want shoes and bags", post.Categories)
+				}
+			},
+		},
+		{
+			name:  "products get tracking links",
+			input: CreatePostInput{ImageURL: "https://cdn.example/look.jpg", Products: products(2), CategoryIDs: []uint{shoesID}},
+			check: func(t *testing.T, post *models.Post, store *fakeStore) {
+				if len(post.Products) != 2 || len(store.links) != 2 {
+					t.Fatalf("products = %d, links = %d; want 2 each", len(post.Products), len(store.links))
+				}
+				for _, product := range post.Products {
+					want := fmt.Sprintf("https://comfyn.com/go/cmf_%d_%d_%d", authorID, post.ID, product.ID)
+					if product.TrackingURL != want {
+						t.Errorf("tracking URL = %q, want %q", product.TrackingURL, want)
+					}
+				}
+			},
+		},
+		{
+			name:        "too many products",
+			input:       CreatePostInput{Products: products(models.MaxProductsPerPost + 1), CategoryIDs: []uint{shoesID}},
+			wantTooMany: models.MaxProductsPerPost + 1,
+		},
+		{
+			name:    "unknown category",
+			input:   CreatePostInput{Products: products(1), CategoryIDs: []uint{shoesID, 99}},
+			wantErr: ErrInvalidCategories,
+		},
+		{
+			name: "unknown product category",
+			input: CreatePostInput{
+				Products:    []ProductInput{{Name: "Bag", Price: 50, CategoryIDs: []uint{99}}},
+				CategoryIDs: []uint{bagsID},
+			},
+			wantErr: ErrInvalidProductCategories,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newCatalogStore()
+			service, _, timeline := newTestService(store)
+
+			post, err := service.CreatePost(authorID, tt.input)
+
+			var tooMany *TooManyProductsError
+			switch {
+			case tt.wantTooMany > 0:
+				if !errors.As(err, &tooMany) || tooMany.Count != tt.wantTooMany {
+					t.Fatalf("error = %v, want TooManyProductsError with count %d", err, tt.wantTooMany)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			if err != nil {
+				if len(store.indexed) != 0 || len(timeline.published) != 0 {
+					t.Error("rejected post was indexed or published")
+				}
+				return
+			}
+			if post.UserID != authorID || !slices.Equal(store.indexed, []uint{post.ID}) ||
+				!slices.Equal(timeline.published, []uint{post.ID}) {
+				t.Errorf("post = %+v, indexed = %v, published = %v", post, store.indexed, timeline.published)
+			}
+			tt.check(t, post, store)
+		})
+	}
+}
+
+func TestUpdatePost(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      uint
+		postID      uint
+		input       UpdatePostInput
+		wantErr     error
+		wantTooMany int
+		check       func(t *testing.T, post *models.Post)
+	}{
+		{
+			name:   "empty fields keep current values",
+			userID: authorID,
+			postID: postID,
+			input:  UpdatePostInput{Description: "new", Hashtags: []string{"#OOTD", "ootd", "Fall"}},
+			check: func(t *testing.T, post *models.Post) {
+				if post.Description != "new" || post.ImageURL != "https://cdn.example/old.jpg" {
+					t.Errorf("description = %q, image = %q", post.Description, post.ImageURL)
+				}
+				if got := hashtagNames(post); !slices.Equal(got, []string{"ootd", "fall"}) {
+					t.Errorf("hashtags = %v, want [ootd fall]", got)
+				}
+				if len(post.Categories) != 1 || post.Categories[0].ID != shoesID {
+					t.Errorf("categories = %+v, want unchanged", post.Categories)
+				}
+			},
+		},
+		{
+			name:   "categories and products replaced",
+			userID: authorID,
+			postID: postID,
+			input:  UpdatePostInput{CategoryIDs: []uint{bagsID}, Products: products(1)},
+			check: func(t *testing.T, post *models.Post) {
+				if len(post.Categories) != 1 || post.Categories[0].ID != bagsID {
+					t.Errorf("categories = %+v, want bags", post.Categories)
+				}
+				if len(post.Products) != 1 || post.Products[0].TrackingURL == "" {
+					t.Errorf("products = %+v, want one tracked product", post.Products)
+				}
+			},
+		},
+		{
+			name:    "not the owner",
+			userID:  viewerID,
+			postID:  postID,
+			input:   UpdatePostInput{Description: "mine now"},
+			wantErr: ErrNotOwner,
+		},
+		{
+			name:    "missing post",
+			userID:  authorID,
+			postID:  postID + 1,
+			input:   UpdatePostInput{Description: "new"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:        "too many products",
+			userID:      authorID,
+			postID:      postID,
+			input:       UpdatePostInput{Products: products(models.MaxProductsPerPost + 1)},
+			wantTooMany: models.MaxProductsPerPost + 1,
+		},
+		{
+			name:    "unknown category",
+			userID:  authorID,
+			postID:  postID,
+			input:   UpdatePostInput{CategoryIDs: []uint{99}},
+			wantErr: ErrInvalidCategories,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := authorPost(models.User{})
+			existing.ImageURL = "https://cdn.example/old.jpg"
+			existing.Description = "old"
+			existing.Categories = []models.Category{{ID: shoesID, Name: "Shoes"}}
+			store := newCatalogStore(existing)
+			service, _, _ := newTestService(store)
+
+			post, err := service.UpdatePost(tt.userID, tt.postID, tt.input)
+
+			var tooMany *TooManyProductsError
+			switch {
+			case tt.wantTooMany > 0:
+				if !errors.As(err, &tooMany) || tooMany.Count != tt.wantTooMany {
+					t.Fatalf("error = %v, want TooManyProductsError with count %d", err, tt.wantTooMany)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			if err != nil {
+				if stored := store.posts[postID]; stored.Description != "old" || len(store.indexed) != 0 {
+					t.Errorf("rejected update changed the post: %+v", stored)
+				}
+				return
+			}
+			if !slices.Equal(store.indexed, []uint{postID}) {
+				t.Errorf("indexed = %v, want [%d]", store.indexed, postID)
+			}
+			tt.check(t, post)
+		})
+	}
+}
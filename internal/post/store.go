@@ -0,0 +1,405 @@
+// internal/post/store.go
+package post
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/internal/ranking"
+	"github.com/sefazor/comfyn/internal/search"
+	usersvc "github.com/sefazor/comfyn/internal/user"
+	"github.com/sefazor/comfyn/pkg/jobs"
+	"github.com/sefazor/comfyn/pkg/pagination"
+	"gorm.io/gorm"
+)
+
+var ErrNotFound = errors.New("post not found")
+
+// Postların, ürünlerinin ve post etkileşimlerinin saklandığı yer
+type Store interface {
+	// Transaction başlatır; Tx üzerindeki işlemler Commit'e kadar kalıcı olmaz
+	Begin() (Tx, error)
+	// Sorguları ctx'e bağlı çalıştıran kopya; istek iptal edilince sorgular da
+	// iptal edilir ve loglar isteğin request_id'sini taşır
+	WithContext(ctx context.Context) Store
+
+	// Sadece yazarıyla birlikte yükler
+	FindPost(id uint) (*models.Post, error)
+	// Kategori, ürün ve hashtag ilişkileriyle birlikte yükler
+	LoadPost(id uint) (*models.Post, error)
+	FindCategories(ids []uint) ([]models.Category, error)
+	FindOrCreateHashtag(name string) (*models.Hashtag, error)
+
+	CreatePost(post *models.Post) error
+	SavePost(post *models.Post) error
+	DeletePost(post *models.Post) error
+	ReplaceCategories(post *models.Post, categories []models.Category) error
+	ReplaceProducts(post *models.Post, products []models.Product) error
+	ReplaceHashtags(post *models.Post, hashtags []models.Hashtag) error
+	RefreshSearchIndex(postID uint) error
+
+	CreateProduct(product *models.Product) error
+	SaveProduct(product *models.Product) error
+	CreateAffiliateLink(link *models.AffiliateLink) error
+
+	// Beğeni yoksa false döner
+	DeleteLike(postID, userID uint) (bool, error)
+	CreateLike(like *models.Like) error
+	DeleteLikeNotification(actorID, postID uint) error
+	AdjustCounter(postID uint, counter Counter, delta int) error
+	CreateComment(comment *models.Comment) error
+	LoadComment(id uint) (*models.Comment, error)
+	// Kayıt yoksa false döner
+	DeleteSaved(postID, userID uint) (bool, error)
+	CreateSaved(saved *models.SavedPost) error
+	// Görüntülemeyi ilk kez kaydedildiyse sayaçlarla birlikte yazar ve true döner
+	RecordView(post *models.Post, userID uint) (bool, error)
+
+	IsBlockedBetween(userID, otherID uint) (bool, error)
+	CanViewPosts(viewerID uint, owner models.User) (bool, error)
+
+	// Post listeleri keyset'e göre sıralanır ve sayfa boyutundan bir fazla kayıt döner
+	ListPosts(viewerID uint, params pagination.Params) ([]models.Post, error)
+	ListByHashtag(viewerID uint, tag string, params pagination.Params) ([]models.Post, error)
+	ListSaved(viewerID uint, params pagination.Params) ([]models.SavedPost, error)
+	Comments(postID uint, params pagination.Params) ([]models.Comment, error)
+	// Postları verilen ID sırasıyla yükler, görülemeyenleri çıkarır
+	PostsByIDs(ids []uint) ([]models.Post, error)
+	// Akış için; sessize alınan ve görülemeyen hesapların postlarını da çıkarır
+	FeedPostsByIDs(viewerID uint, ids []uint) ([]models.Post, error)
+	Suggested(viewerID uint, cursor *ranking.Cursor, limit int) ([]uint, *ranking.Cursor, error)
+	TrendingHashtags() ([]HashtagCount, error)
+	ViewerState(viewerID uint, posts []models.Post) (*usersvc.ViewerState, error)
+}
+
+type Tx interface {
+	Store
+	// İşleri bu transaction'la birlikte kaydeden kuyruk
+	Jobs(queue *jobs.Queue) *jobs.Queue
+	Commit() error
+	Rollback() error
+}
+
+type HashtagCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) WithContext(ctx context.Context) Store {
+	return &PostgresStore{db: s.db.WithContext(ctx)}
+}
+
+type postgresTx struct {
+	PostgresStore
+}
+
+func (s *PostgresStore) Begin() (Tx, error) {
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return &postgresTx{PostgresStore{db: tx}}, nil
+}
+
+func (t *postgresTx) Jobs(queue *jobs.Queue) *jobs.Queue {
+	return queue.WithTx(t.db)
+}
+
+func (t *postgresTx) Commit() error {
+	return t.db.Commit().Error
+}
+
+func (t *postgresTx) Rollback() error {
+	return t.db.Rollback().Error
+}
+
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (s *PostgresStore) FindPost(id uint) (*models.Post, error) {
+	var post models.Post
+	if err := s.db.Preload("User").First(&post, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &post, nil
+}
+
+func (s *PostgresStore) LoadPost(id uint) (*models.Post, error) {
+	var post models.Post
+	if err := s.db.Preload("User").
+		Preload("Categories").
+		Preload("Products").
+		Preload("Products.Categories").
+		Preload("Hashtags").
+		First(&post, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &post, nil
+}
+
+func (s *PostgresStore) FindCategories(ids []uint) ([]models.Category, error) {
+	var categories []models.Category
+	err := s.db.Find(&categories, ids).Error
+	return categories, err
+}
+
+func (s *PostgresStore) FindOrCreateHashtag(name string) (*models.Hashtag, error) {
+	var hashtag models.Hashtag
+	if err := s.db.Where("name = ?", name).FirstOrCreate(&hashtag, models.Hashtag{Name: name}).Error; err != nil {
+		return nil, err
+	}
+	return &hashtag, nil
+}
+
+func (s *PostgresStore) CreatePost(post *models.Post) error {
+	return s.db.Create(post).Error
+}
+
+func (s *PostgresStore) SavePost(post *models.Post) error {
+	return s.db.Save(post).Error
+}
+
+// Ürün bağlantıları temizlenir, post soft delete ile silinir
+func (s *PostgresStore) DeletePost(post *models.Post) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(post).Association("Products").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(post).Error
+	})
+}
+
+func (s *PostgresStore) ReplaceCategories(post *models.Post, categories []models.Category) error {
+	return s.db.Model(post).Association("Categories").Replace(categories)
+}
+
+func (s *PostgresStore) ReplaceProducts(post *models.Post, products []models.Product) error {
+	return s.db.Model(post).Association("Products").Replace(products)
+}
+
+func (s *PostgresStore) ReplaceHashtags(post *models.Post, hashtags []models.Hashtag) error {
+	return s.db.Model(post).Association("Hashtags").Replace(hashtags)
+}
+
+func (s *PostgresStore) RefreshSearchIndex(postID uint) error {
+	return search.RefreshPostIndex(s.db, postID)
+}
+
+func (s *PostgresStore) CreateProduct(product *models.Product) error {
+	return s.db.Create(product).Error
+}
+
+func (s *PostgresStore) SaveProduct(product *models.Product) error {
+	return s.db.Save(product).Error
+}
+
+func (s *PostgresStore) CreateAffiliateLink(link *models.AffiliateLink) error {
+	return s.db.Create(link).Error
+}
+
+func (s *PostgresStore) DeleteLike(postID, userID uint) (bool, error) {
+	result := s.db.Where("post_id = ? AND user_id = ?", postID, userID).Delete(&models.Like{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (s *PostgresStore) CreateLike(like *models.Like) error {
+	return s.db.Create(like).Error
+}
+
+func (s *PostgresStore) DeleteLikeNotification(actorID, postID uint) error {
+	return s.db.Where("actor_id = ? AND post_id = ? AND type = ?",
+		actorID, postID, models.NotificationPostLike).
+		Delete(&models.Notification{}).Error
+}
+
+func (s *PostgresStore) AdjustCounter(postID uint, counter Counter, delta int) error {
+	return AdjustCounter(s.db, postID, counter, delta)
+}
+
+func (s *PostgresStore) CreateComment(comment *models.Comment) error {
+	return s.db.Create(comment).Error
+}
+
+func (s *PostgresStore) LoadComment(id uint) (*models.Comment, error) {
+	var comment models.Comment
+	if err := s.db.Preload("User").First(&comment, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &comment, nil
+}
+
+func (s *PostgresStore) DeleteSaved(postID, userID uint) (bool, error) {
+	result := s.db.Where("post_id = ? AND user_id = ?", postID, userID).Delete(&models.SavedPost{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (s *PostgresStore) CreateSaved(saved *models.SavedPost) error {
+	return s.db.Create(saved).Error
+}
+
+func (s *PostgresStore) RecordView(post *models.Post, userID uint) (bool, error) {
+	isNewView := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var existingView models.PostView
+		err := tx.Where("post_id = ? AND user_id = ?", post.ID, userID).First(&existingView).Error
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		isNewView = true
+
+		if err := tx.Create(&models.PostView{PostID: post.ID, UserID: userID}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(post).Update("view_count", gorm.Expr("view_count + ?", 1)).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.User{}).Where("id = ?", post.UserID).
+			Update("total_views", gorm.Expr("total_views + ?", 1)).Error
+	})
+	return isNewView, err
+}
+
+func (s *PostgresStore) IsBlockedBetween(userID, otherID uint) (bool, error) {
+	return usersvc.IsBlockedBetween(s.db, userID, otherID)
+}
+
+func (s *PostgresStore) CanViewPosts(viewerID uint, owner models.User) (bool, error) {
+	return usersvc.CanViewPosts(s.db, viewerID, owner)
+}
+
+func (s *PostgresStore) listQuery() *gorm.DB {
+	return s.db.Model(&models.Post{}).
+		Preload("User").
+		Preload("Products").
+		Preload("Categories").
+		Preload("Hashtags")
+}
+
+func (s *PostgresStore) ListPosts(viewerID uint, params pagination.Params) ([]models.Post, error) {
+	query := s.listQuery().Scopes(usersvc.VisiblePosts(viewerID))
+
+	var posts []models.Post
+	err := params.Apply(query, postOrder).Find(&posts).Error
+	return posts, err
+}
+
+func (s *PostgresStore) ListByHashtag(viewerID uint, tag string, params pagination.Params) ([]models.Post, error) {
+	query := s.listQuery().
+		Joins("JOIN post_hashtags ph ON ph.post_id = posts.id").
+		Joins("JOIN hashtags h ON h.id = ph.hashtag_id").
+		Where("h.name = ?", tag).
+		Scopes(usersvc.VisiblePosts(viewerID))
+
+	var posts []models.Post
+	err := params.Apply(query, postOrder).Find(&posts).Error
+	return posts, err
+}
+
+// Sayfalama kaydetme zamanına göre yapılır
+func (s *PostgresStore) ListSaved(viewerID uint, params pagination.Params) ([]models.SavedPost, error) {
+	query := s.db.Model(&models.SavedPost{}).
+		Joins("JOIN posts ON posts.id = saved_posts.post_id AND posts.deleted_at IS NULL").
+		Where("saved_posts.user_id = ?", viewerID).
+		Where("posts.user_id NOT IN (?)", usersvc.BlockedUserIDs(s.db, viewerID)).
+		Scopes(usersvc.VisiblePosts(viewerID))
+
+	var saved []models.SavedPost
+	err := params.Apply(query, pagination.Order{Column: "saved_posts.created_at", IDColumn: "saved_posts.id"}).
+		Find(&saved).Error
+	return saved, err
+}
+
+// Yorumlar eskiden yeniye sıralanır, gizlenmiş yorumlar dönmez
+func (s *PostgresStore) Comments(postID uint, params pagination.Params) ([]models.Comment, error) {
+	query := s.db.Preload("User").
+		Where("post_id = ? AND hidden_at IS NULL", postID)
+
+	var comments []models.Comment
+	err := params.Apply(query, pagination.Order{
+		Column:    "comments.created_at",
+		IDColumn:  "comments.id",
+		Ascending: true,
+	}).Find(&comments).Error
+	return comments, err
+}
+
+func (s *PostgresStore) PostsByIDs(ids []uint) ([]models.Post, error) {
+	return s.postsInOrder(ids)
+}
+
+func (s *PostgresStore) FeedPostsByIDs(viewerID uint, ids []uint) ([]models.Post, error) {
+	return s.postsInOrder(ids,
+		// Sessize alınan kullanıcıların postlarını hariç tut
+		func(db *gorm.DB) *gorm.DB {
+			return db.Where("posts.user_id NOT IN (?)", usersvc.MutedUserIDs(s.db, viewerID))
+		},
+		usersvc.VisiblePosts(viewerID))
+}
+
+// Postları ilişkileriyle yükler ve verilen ID sırasını korur.
+// Scope'lara uymayan postlar sonuçtan çıkarılır.
+func (s *PostgresStore) postsInOrder(postIDs []uint, scopes ...func(*gorm.DB) *gorm.DB) ([]models.Post, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+
+	var posts []models.Post
+	if err := s.listQuery().
+		Where("posts.id IN ?", postIDs).
+		Scopes(scopes...).
+		Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]models.Post, len(posts))
+	for _, post := range posts {
+		byID[post.ID] = post
+	}
+
+	ordered := make([]models.Post, 0, len(postIDs))
+	for _, id := range postIDs {
+		if post, ok := byID[id]; ok {
+			ordered = append(ordered, post)
+		}
+	}
+	return ordered, nil
+}
+
+func (s *PostgresStore) Suggested(viewerID uint, cursor *ranking.Cursor, limit int) ([]uint, *ranking.Cursor, error) {
+	return ranking.Suggested(s.db, viewerID, cursor, limit)
+}
+
+// Son 7 günde en çok postta kullanılan 10 hashtag
+func (s *PostgresStore) TrendingHashtags() ([]HashtagCount, error) {
+	var trendingHashtags []HashtagCount
+	err := s.db.Raw(`
+        SELECT h.name, COUNT(DISTINCT ph.post_id) as count
+        FROM hashtags h
+        JOIN post_hashtags ph ON ph.hashtag_id = h.id
+        JOIN posts p ON p.id = ph.post_id
+        WHERE p.created_at >= NOW() - INTERVAL '7 days'
+        GROUP BY h.name
+        ORDER BY count DESC
+        LIMIT 10
+    `).Scan(&trendingHashtags).Error
+	return trendingHashtags, err
+}
+
+func (s *PostgresStore) ViewerState(viewerID uint, posts []models.Post) (*usersvc.ViewerState, error) {
+	return usersvc.LoadViewerState(s.db, viewerID, posts)
+}
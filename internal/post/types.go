@@ -0,0 +1,70 @@
+// internal/post/types.go
+package post
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/pagination"
+)
+
+type CreatePostInput struct {
+	ImageURL    string         `json:"imageUrl" binding:"required"`
+	Description string         `json:"description"`
+	Products    []ProductInput `json:"products" binding:"required,dive,required"`
+	CategoryIDs []uint         `json:"categoryIds" binding:"required,min=1"`
+	Hashtags    []string       `json:"hashtags"`
+}
+
+// Boş bırakılan alanlar değiştirilmez; ürün, kategori veya hashtag
+// verilirse mevcutların yerini alır
+type UpdatePostInput struct {
+	ImageURL    string         `json:"imageUrl"`
+	Description string         `json:"description"`
+	Products    []ProductInput `json:"products" binding:"dive,required"`
+	CategoryIDs []uint         `json:"categoryIds"`
+	Hashtags    []string       `json:"hashtags"`
+}
+
+type ProductInput struct {
+	Name        string  `json:"name" binding:"required"`
+	Price       float64 `json:"price" binding:"required"`
+	Link        string  `json:"link"`
+	Description string  `json:"description"`
+	CategoryIDs []uint  `json:"categoryIds" binding:"required,min=1"`
+}
+
+type CreateCommentInput struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// Tek post yanıtı; yorumların ilk sayfasını da içerir
+type PostDetailResponse struct {
+	models.PostResponse
+	Comments           []models.CommentResponse `json:"comments"`
+	CommentsPagination pagination.Page          `json:"commentsPagination"`
+}
+
+type ViewResult struct {
+	IsNewView bool
+	ViewCount int
+}
+
+var (
+	ErrNotOwner                 = errors.New("post belongs to another user")
+	ErrCannotInteract           = errors.New("you cannot interact with this post")
+	ErrPrivateAccount           = errors.New("this account is private")
+	ErrInvalidCategories        = errors.New("invalid category IDs")
+	ErrInvalidProductCategories = errors.New("invalid product category IDs")
+	ErrEmptyComment             = errors.New("comment content is required")
+)
+
+// Posta izin verilenden fazla ürün eklenmeye çalışıldığında döner
+type TooManyProductsError struct {
+	Count int
+}
+
+func (e *TooManyProductsError) Error() string {
+	return fmt.Sprintf("maximum %d products can be added to a post", models.MaxProductsPerPost)
+}
@@ -0,0 +1,127 @@
+// internal/product/handler.go
+package product
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/apierror"
+	"github.com/sefazor/comfyn/pkg/pagination"
+)
+
+type ListProductsQuery struct {
+	CategoryID uint     `form:"category"`
+	MinPrice   *float64 `form:"minPrice" binding:"omitempty,min=0"`
+	MaxPrice   *float64 `form:"maxPrice" binding:"omitempty,min=0"`
+	Merchant   string   `form:"merchant"` // Ürün linkinin alan adı (örn. trendyol.com)
+	PartnerID  uint     `form:"partner"`  // Kayıtlı affiliate partner
+	Sort       string   `form:"sort,default=recent" binding:"oneof=recent popular price_asc price_desc"`
+	Page       int      `form:"page,default=1" binding:"min=1"`
+	Limit      int      `form:"limit,default=20" binding:"min=1"`
+}
+
+type Handler struct {
+	store Store
+}
+
+func NewHandler(store Store) *Handler {
+	return &Handler{store: store}
+}
+
+// Ürünlere filtre ve sıralama ile göz atma
+func (h *Handler) ListProductsHandler(c *gin.Context) {
+	var query ListProductsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Validation(c, err)
+		return
+	}
+
+	if query.Limit > 50 {
+		query.Limit = 50
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	store := h.store.WithContext(c.Request.Context())
+
+	rows, total, err := store.ListProducts(currentUser.ID, query)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch products")
+		return
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	products, err := store.LoadProducts(currentUser.ID, ids)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch products")
+		return
+	}
+
+	byID := make(map[uint]models.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	// Sıralamayı ilk sorgudaki gibi koru
+	response := make([]ProductListItem, 0, len(rows))
+	for _, row := range rows {
+		p, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		response = append(response, productResponse(p, row.Popularity))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"products":   response,
+		"pagination": pagination.NumberedPage(query.Page, query.Limit, total),
+	})
+}
+
+// Ürün listesindeki öğe; ürünü paylaşan gönderiler ve toplam tıklanma sayısıyla
+type ProductListItem struct {
+	models.ProductResponse
+	Categories []models.CategoryResponse `json:"categories"`
+	ClickCount int64                     `json:"clickCount"`
+	Posts      []ProductPost             `json:"posts"`
+	CreatedAt  time.Time                 `json:"createdAt"`
+}
+
+type ProductPost struct {
+	ID        uint                `json:"id"`
+	ImageURL  string              `json:"imageUrl"`
+	User      models.UserResponse `json:"user"`
+	CreatedAt time.Time           `json:"createdAt"`
+}
+
+func productResponse(p models.Product, popularity int64) ProductListItem {
+	posts := make([]ProductPost, len(p.Posts))
+	for i, post := range p.Posts {
+		posts[i] = ProductPost{
+			ID:        post.ID,
+			ImageURL:  post.ImageURL,
+			User:      post.User.SafeResponse(),
+			CreatedAt: post.CreatedAt,
+		}
+	}
+
+	categories := make([]models.CategoryResponse, len(p.Categories))
+	for i := range p.Categories {
+		categories[i] = p.Categories[i].Response()
+	}
+
+	return ProductListItem{
+		ProductResponse: p.Response(),
+		Categories:      categories,
+		ClickCount:      popularity,
+		Posts:           posts,
+		CreatedAt:       p.CreatedAt,
+	}
+}
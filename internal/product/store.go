@@ -0,0 +1,110 @@
+// internal/product/store.go
+package product
+
+import (
+	"context"
+
+	"github.com/sefazor/comfyn/internal/models"
+	usersvc "github.com/sefazor/comfyn/internal/user"
+	"gorm.io/gorm"
+)
+
+// Ürünün toplam affiliate tıklanma sayısı
+const popularityExpr = `COALESCE((
+	SELECT SUM(al.click_count) FROM affiliate_links al
+	WHERE al.product_id = products.id AND al.deleted_at IS NULL), 0)`
+
+// Linkteki alan adı (www. hariç)
+const merchantExpr = `lower(substring(products.link from '^https?://(?:www\.)?([^/:?#]+)'))`
+
+var sortOrders = map[string]string{
+	"recent":     "products.created_at DESC, products.id DESC",
+	"popular":    "popularity DESC, products.id DESC",
+	"price_asc":  "products.price ASC, products.id ASC",
+	"price_desc": "products.price DESC, products.id DESC",
+}
+
+type productRow struct {
+	ID         uint
+	Popularity int64
+}
+
+// Ürün listesinin sorgulandığı yer
+type Store interface {
+	// Sorguları ctx'e bağlı çalıştıran kopya
+	WithContext(ctx context.Context) Store
+
+	// Filtre ve sıralamaya uyan, izleyicinin görebildiği ürünlerin sayfası ve toplam sayısı
+	ListProducts(viewerID uint, query ListProductsQuery) ([]productRow, int64, error)
+	// Ürünleri kategorileri ve izleyicinin görebildiği postlarıyla yükler
+	LoadProducts(viewerID uint, ids []uint) ([]models.Product, error)
+}
+
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) WithContext(ctx context.Context) Store {
+	return &PostgresStore{db: s.db.WithContext(ctx)}
+}
+
+func (s *PostgresStore) ListProducts(viewerID uint, query ListProductsQuery) ([]productRow, int64, error) {
+	// Sadece kullanıcının görebildiği bir postta yer alan ürünler
+	visiblePosts := s.db.Table("post_products pp").Select("1").
+		Joins("JOIN posts ON posts.id = pp.post_id AND posts.deleted_at IS NULL").
+		Where("pp.product_id = products.id").
+		Scopes(usersvc.VisiblePosts(viewerID))
+	db := s.db.Model(&models.Product{}).Where("EXISTS (?)", visiblePosts)
+
+	db = applyFilters(db, query)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []productRow
+	err := db.Select("products.id, " + popularityExpr + " AS popularity").
+		Order(sortOrders[query.Sort]).
+		Limit(query.Limit).
+		Offset((query.Page - 1) * query.Limit).
+		Scan(&rows).Error
+	return rows, total, err
+}
+
+func (s *PostgresStore) LoadProducts(viewerID uint, ids []uint) ([]models.Product, error) {
+	var products []models.Product
+	if len(ids) == 0 {
+		return products, nil
+	}
+	err := s.db.Preload("Categories").
+		Preload("Posts", func(db *gorm.DB) *gorm.DB {
+			return db.Scopes(usersvc.VisiblePosts(viewerID))
+		}).
+		Preload("Posts.User").
+		Find(&products, ids).Error
+	return products, err
+}
+
+func applyFilters(db *gorm.DB, query ListProductsQuery) *gorm.DB {
+	if query.CategoryID != 0 {
+		db = db.Where("EXISTS (SELECT 1 FROM product_categories pc WHERE pc.product_id = products.id AND pc.category_id = ?)", query.CategoryID)
+	}
+	if query.MinPrice != nil {
+		db = db.Where("products.price >= ?", *query.MinPrice)
+	}
+	if query.MaxPrice != nil {
+		db = db.Where("products.price <= ?", *query.MaxPrice)
+	}
+	if query.Merchant != "" {
+		db = db.Where(merchantExpr+" = lower(?)", query.Merchant)
+	}
+	if query.PartnerID != 0 {
+		db = db.Where("EXISTS (SELECT 1 FROM affiliate_partners ap WHERE ap.id = ? AND ap.is_active AND ap.deleted_at IS NULL AND products.link LIKE ap.base_url || '%')", query.PartnerID)
+	}
+	return db
+}
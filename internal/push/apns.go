@@ -0,0 +1,167 @@
+// internal/push/apns.go
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sefazor/comfyn/pkg/tracing"
+)
+
+const (
+	apnsProductionHost = "https://api.push.apple.com"
+	apnsSandboxHost    = "https://api.sandbox.push.apple.com"
+
+	// Apple provider token'ları 20-60 dakika arasında yenilenmeli
+	apnsTokenTTL = 50 * time.Minute
+)
+
+// Token tabanlı kimlik doğrulama kullanan APNs HTTP/2 sağlayıcısı
+type APNsProvider struct {
+	keyID  string
+	teamID string
+	topic  string
+	host   string
+	key    *ecdsa.PrivateKey
+	client *http.Client
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+func NewAPNsProvider(keyFile, keyID, teamID, topic string, sandbox bool) (*APNsProvider, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read apns key: %w", err)
+	}
+
+	key, err := jwt.ParseECPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse apns key: %w", err)
+	}
+
+	host := apnsProductionHost
+	if sandbox {
+		host = apnsSandboxHost
+	}
+
+	return &APNsProvider{
+		keyID:  keyID,
+		teamID: teamID,
+		topic:  topic,
+		host:   host,
+		key:    key,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: tracing.Transport(&http.Transport{
+				ForceAttemptHTTP2: true,
+				TLSClientConfig:   &tls.Config{MinVersion: tls.VersionTLS12},
+			}),
+		},
+	}, nil
+}
+
+func (p *APNsProvider) providerToken() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Since(p.issuedAt) < apnsTokenTTL {
+		return p.token, nil
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": p.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = p.keyID
+
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		return "", err
+	}
+
+	p.token = signed
+	p.issuedAt = now
+	return signed, nil
+}
+
+func (p *APNsProvider) resetToken() {
+	p.mu.Lock()
+	p.token = ""
+	p.mu.Unlock()
+}
+
+func (p *APNsProvider) Send(ctx context.Context, msg Message) error {
+	token, err := p.providerToken()
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"sound": "default",
+		},
+	}
+	for k, v := range msg.Data {
+		payload[k] = v
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.host+"/3/device/"+msg.Token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("authorization", "bearer "+token)
+	req.Header.Set("apns-topic", p.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var result struct {
+		Reason string `json:"reason"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		result.Reason == "BadDeviceToken",
+		result.Reason == "Unregistered",
+		result.Reason == "DeviceTokenNotForTopic":
+		return ErrInvalidToken
+	case result.Reason == "ExpiredProviderToken":
+		p.resetToken()
+		return fmt.Errorf("apns provider token expired")
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("apns temporary failure (%d): %s", resp.StatusCode, result.Reason)
+	default:
+		return fmt.Errorf("%w: apns %d: %s", ErrRejected, resp.StatusCode, result.Reason)
+	}
+}
@@ -0,0 +1,56 @@
+// internal/push/fake.go
+package push
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// FakeProvider'ın Failures ile istenen geçici hatası
+var ErrFakeUnavailable = errors.New("push: fake provider unavailable")
+
+// Testler için gönderilen mesajları hafızada tutan sağlayıcı
+type FakeProvider struct {
+	mu       sync.Mutex
+	Sent     []Message
+	Errors   map[string]error // Token'a göre döndürülecek hata
+	Failures map[string]int   // Token'a göre ErrFakeUnavailable dönecek ilk deneme sayısı
+	Attempts map[string]int   // Token'a göre toplam Send çağrısı
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		Errors:   make(map[string]error),
+		Failures: make(map[string]int),
+		Attempts: make(map[string]int),
+	}
+}
+
+func (f *FakeProvider) Send(ctx context.Context, msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Attempts[msg.Token]++
+
+	if err, ok := f.Errors[msg.Token]; ok {
+		return err
+	}
+	if f.Failures[msg.Token] > 0 {
+		f.Failures[msg.Token]--
+		return ErrFakeUnavailable
+	}
+
+	f.Sent = append(f.Sent, msg)
+	return nil
+}
+
+// Gönderilen mesajların kopyasını döner
+func (f *FakeProvider) Messages() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	messages := make([]Message, len(f.Sent))
+	copy(messages, f.Sent)
+	return messages
+}
@@ -0,0 +1,102 @@
+// internal/push/handler.go
+package push
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/apierror"
+)
+
+type RegisterDeviceInput struct {
+	Token    string `json:"token" binding:"required"`
+	Platform string `json:"platform" binding:"required,oneof=ios android"`
+	Locale   string `json:"locale"`
+}
+
+type Handler struct {
+	store Store
+}
+
+func NewHandler(store Store) *Handler {
+	return &Handler{store: store}
+}
+
+// İsteğin context'ine bağlı store
+func (h *Handler) storeFor(c *gin.Context) Store {
+	return h.store.WithContext(c.Request.Context())
+}
+
+// Cihaz token'ı kaydetme
+func (h *Handler) RegisterDeviceHandler(c *gin.Context) {
+	var input RegisterDeviceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierror.Validation(c, err)
+		return
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	locale := normalizeLocale(input.Locale)
+	store := h.storeFor(c)
+
+	// Token başka bir kullanıcıya kayıtlıysa (cihazda hesap değişmiş) yeni kullanıcıya taşı
+	device, err := store.FindDevice(input.Token)
+	switch {
+	case err == nil:
+		updates := map[string]interface{}{
+			"user_id":      currentUser.ID,
+			"platform":     input.Platform,
+			"locale":       locale,
+			"last_seen_at": time.Now(),
+		}
+		if err := store.UpdateDevice(device, updates); err != nil {
+			apierror.Internal(c, "Failed to register device")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Device registered successfully"})
+		return
+	case !errors.Is(err, ErrDeviceNotFound):
+		apierror.Internal(c, "Failed to register device")
+		return
+	}
+
+	device = &models.Device{
+		UserID:     currentUser.ID,
+		Token:      input.Token,
+		Platform:   models.DevicePlatform(input.Platform),
+		Locale:     locale,
+		LastSeenAt: time.Now(),
+	}
+
+	if err := store.CreateDevice(device); err != nil {
+		apierror.Internal(c, "Failed to register device")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Device registered successfully"})
+}
+
+// Cihaz token'ını silme (çıkış yaparken)
+func (h *Handler) UnregisterDeviceHandler(c *gin.Context) {
+	token := c.Param("token")
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	removed, err := h.storeFor(c).UnregisterDevice(token, currentUser.ID)
+	if err != nil {
+		apierror.Internal(c, "Failed to unregister device")
+		return
+	}
+	if !removed {
+		apierror.NotFound(c, "Device not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Device unregistered successfully"})
+}
@@ -0,0 +1,87 @@
+// internal/push/message.go
+package push
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sefazor/comfyn/internal/models"
+)
+
+const defaultLocale = "tr"
+
+// Bildirim tipine göre yerelleştirilmiş metinler
+var messageTemplates = map[string]map[models.NotificationType]string{
+	"tr": {
+		models.NotificationNewFollower:   "%s seni takip etmeye başladı",
+		models.NotificationPostLike:      "%s gönderini beğendi",
+		models.NotificationComment:       "%s gönderine yorum yaptı: %s",
+		models.NotificationFollowRequest: "%s seni takip etmek istiyor",
+		models.NotificationDataExport:    "Verilerin indirilmeye hazır",
+	},
+	"en": {
+		models.NotificationNewFollower:   "%s started following you",
+		models.NotificationPostLike:      "%s liked your post",
+		models.NotificationComment:       "%s commented on your post: %s",
+		models.NotificationFollowRequest: "%s requested to follow you",
+		models.NotificationDataExport:    "Your data export is ready to download",
+	},
+}
+
+const maxCommentPreview = 80
+
+// "tr-TR", "en_US" gibi değerleri desteklenen dile indirger
+func normalizeLocale(locale string) string {
+	locale = strings.ToLower(locale)
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	if _, ok := messageTemplates[locale]; !ok {
+		return defaultLocale
+	}
+	return locale
+}
+
+// Bildirimi cihazın diline göre push mesajına çevirir
+func FormatMessage(n *models.Notification, locale string) (Message, bool) {
+	template, ok := messageTemplates[normalizeLocale(locale)][n.Type]
+	if !ok {
+		return Message{}, false
+	}
+
+	var body string
+	switch n.Type {
+	case models.NotificationComment:
+		content := ""
+		if n.Comment != nil {
+			content = n.Comment.Content
+		}
+		if runes := []rune(content); len(runes) > maxCommentPreview {
+			content = string(runes[:maxCommentPreview]) + "…"
+		}
+		body = fmt.Sprintf(template, n.Actor.Username, content)
+	case models.NotificationDataExport:
+		body = template
+	default:
+		body = fmt.Sprintf(template, n.Actor.Username)
+	}
+
+	data := map[string]string{
+		"notificationId": strconv.FormatUint(uint64(n.ID), 10),
+		"type":           string(n.Type),
+		"actorId":        strconv.FormatUint(uint64(n.ActorID), 10),
+	}
+	if n.PostID != nil {
+		data["postId"] = strconv.FormatUint(uint64(*n.PostID), 10)
+	}
+	if n.CommentID != nil {
+		data["commentId"] = strconv.FormatUint(uint64(*n.CommentID), 10)
+	}
+
+	return Message{
+		Title: "Comfyn",
+		Body:  body,
+		Data:  data,
+	}, true
+}
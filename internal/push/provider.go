@@ -0,0 +1,27 @@
+// internal/push/provider.go
+package push
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// Cihaz token'ı artık geçerli değil, kayıt silinmeli
+	ErrInvalidToken = errors.New("push: invalid device token")
+	// Sağlayıcı mesajı reddetti, tekrar denemenin anlamı yok
+	ErrRejected = errors.New("push: message rejected")
+)
+
+// Tek bir cihaza gönderilecek mesaj
+type Message struct {
+	Token string
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// FCM, APNs ve testlerdeki sahte sağlayıcının ortak arayüzü
+type Provider interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,179 @@
+// internal/push/service.go
+package push
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/sefazor/comfyn/configs"
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/jobs"
+	"github.com/sefazor/comfyn/pkg/metrics"
+)
+
+const (
+	maxAttempts     = 3
+	initialBackoff  = 500 * time.Millisecond
+	deliveryTimeout = 30 * time.Second
+)
+
+// Push gönderimleri bildirimi oluşturan işten ayrı denenir
+var deliverJob = jobs.NewKind[uint]("push.deliver")
+
+// Bildirimleri kullanıcıların cihazlarına platformun sağlayıcısıyla gönderir
+type Service struct {
+	store Store
+	// Geçici hatadan sonraki ilk bekleme; her denemede iki katına çıkar
+	backoff time.Duration
+
+	providersMu sync.RWMutex
+	providers   map[models.DevicePlatform]Provider
+}
+
+// Ayarlara göre FCM ve APNs sağlayıcılarını kurar
+func NewService(store Store, cfg configs.PushConfig) *Service {
+	s := &Service{store: store, backoff: initialBackoff, providers: make(map[models.DevicePlatform]Provider)}
+
+	if cfg.FCMCredentialsFile != "" {
+		provider, err := NewFCMProvider(cfg.FCMCredentialsFile)
+		if err != nil {
+			log.Printf("Warning: FCM push disabled: %v", err)
+		} else {
+			s.RegisterProvider(models.DevicePlatformAndroid, provider)
+			log.Println("FCM push provider configured")
+		}
+	}
+
+	if cfg.APNSKeyFile != "" {
+		provider, err := NewAPNsProvider(
+			cfg.APNSKeyFile,
+			cfg.APNSKeyID,
+			cfg.APNSTeamID,
+			cfg.APNSTopic,
+			cfg.APNSSandbox,
+		)
+		if err != nil {
+			log.Printf("Warning: APNs push disabled: %v", err)
+		} else {
+			s.RegisterProvider(models.DevicePlatformIOS, provider)
+			log.Println("APNs push provider configured")
+		}
+	}
+
+	return s
+}
+
+// Platform için sağlayıcı tanımlar (testlerde FakeProvider ile kullanılır)
+func (s *Service) RegisterProvider(platform models.DevicePlatform, provider Provider) {
+	s.providersMu.Lock()
+	defer s.providersMu.Unlock()
+	s.providers[platform] = provider
+}
+
+func (s *Service) providerFor(platform models.DevicePlatform) Provider {
+	s.providersMu.RLock()
+	defer s.providersMu.RUnlock()
+	return s.providers[platform]
+}
+
+// Kuyruktaki push gönderimlerini işleyen handler'ı worker'a ekler
+func (s *Service) RegisterJobs(w *jobs.Worker) {
+	jobs.Handle(w, deliverJob, func(ctx context.Context, notificationID uint) error {
+		err := s.Deliver(ctx, notificationID)
+		// Bildirim bu arada silinmiş olabilir
+		if errors.Is(err, ErrNotificationNotFound) {
+			return jobs.Permanent(err)
+		}
+		return err
+	})
+}
+
+// Bildirimi kullanıcının cihazlarına gönderilmek üzere q kuyruğuna ekler
+func (s *Service) DeliverAsync(ctx context.Context, q *jobs.Queue, notificationID uint) error {
+	return deliverJob.Enqueue(ctx, q, notificationID)
+}
+
+// Bildirimi kullanıcının kayıtlı tüm cihazlarına gönderir
+func (s *Service) Deliver(ctx context.Context, notificationID uint) error {
+	store := s.store.WithContext(ctx)
+
+	notification, err := store.LoadNotification(notificationID)
+	if err != nil {
+		return err
+	}
+
+	// Push tercihlerini kontrol et
+	pref, err := store.Preferences(notification.UserID)
+	if err != nil {
+		return err
+	}
+	if !pref.PushEnabled(notification.Type) {
+		return nil
+	}
+
+	devices, err := store.Devices(notification.UserID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	for _, device := range devices {
+		provider := s.providerFor(device.Platform)
+		if provider == nil {
+			continue
+		}
+
+		msg, ok := FormatMessage(notification, device.Locale)
+		if !ok {
+			continue
+		}
+		msg.Token = device.Token
+
+		err := sendWithRetry(ctx, provider, msg, s.backoff)
+		switch {
+		case err == nil:
+			metrics.PushDeliveries.WithLabelValues(metrics.ResultSuccess).Inc()
+		case errors.Is(err, ErrInvalidToken):
+			metrics.PushDeliveries.WithLabelValues("invalid_token").Inc()
+			// Geçersiz token'ı temizle
+			if err := store.RemoveDevice(&device); err != nil {
+				slog.ErrorContext(ctx, "Failed to remove invalid device token", "device_id", device.ID, "error", err)
+			}
+		default:
+			metrics.PushDeliveries.WithLabelValues(metrics.ResultFailure).Inc()
+			slog.ErrorContext(ctx, "Failed to send push", "device_id", device.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// Geçici hatalarda üstel bekleme ile tekrar dener
+func sendWithRetry(ctx context.Context, provider Provider, msg Message, backoff time.Duration) error {
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err = provider.Send(ctx, msg)
+		if err == nil || errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrRejected) {
+			return err
+		}
+
+		if attempt == maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	return err
+}
@@ -0,0 +1,189 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sefazor/comfyn/configs"
+	"github.com/sefazor/comfyn/internal/models"
+)
+
+// Bildirimleri, tercihleri ve cihazları bellekte tutar; silinen geçersiz
+// token'lar removed'a yazılır
+type fakeStore struct {
+	Store
+
+	notifications map[uint]*models.Notification
+	preferences   map[uint]*models.NotificationPreference
+	devices       []models.Device
+	removed       []string
+}
+
+func (s *fakeStore) WithContext(ctx context.Context) Store { return s }
+
+func (s *fakeStore) LoadNotification(id uint) (*models.Notification, error) {
+	notification, ok := s.notifications[id]
+	if !ok {
+		return nil, ErrNotificationNotFound
+	}
+	return notification, nil
+}
+
+func (s *fakeStore) Preferences(userID uint) (*models.NotificationPreference, error) {
+	if pref, ok := s.preferences[userID]; ok {
+		return pref, nil
+	}
+	return &models.NotificationPreference{UserID: userID, PushNewFollower: true, PushPostLike: true, PushComment: true}, nil
+}
+
+func (s *fakeStore) Devices(userID uint) ([]models.Device, error) {
+	var devices []models.Device
+	for _, device := range s.devices {
+		if device.UserID == userID {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+func (s *fakeStore) RemoveDevice(device *models.Device) error {
+	s.removed = append(s.removed, device.Token)
+	return nil
+}
+
+const (
+	recipientID    = 1
+	actorID        = 2
+	notificationID = 7
+
+	androidToken = "android-token"
+	iosToken     = "ios-token"
+)
+
+// Alıcının bir Android ve bir iOS cihazı olan beğeni bildirimi
+func newTestService() (*Service, *fakeStore, *FakeProvider, *FakeProvider) {
+	store := &fakeStore{
+		notifications: map[uint]*models.Notification{
+			notificationID: {
+				ID:      notificationID,
+				UserID:  recipientID,
+				ActorID: actorID,
+				Type:    models.NotificationPostLike,
+				Actor:   models.User{ID: actorID, Username: "ayse"},
+			},
+		},
+		preferences: make(map[uint]*models.NotificationPreference),
+		devices: []models.Device{
+			{ID: 1, UserID: recipientID, Token: androidToken, Platform: models.DevicePlatformAndroid, Locale: "en"},
+			{ID: 2, UserID: recipientID, Token: iosToken, Platform: models.DevicePlatformIOS, Locale: "tr"},
+		},
+	}
+
+	service := NewService(store, configs.PushConfig{})
+	service.backoff = time.Millisecond
+
+	android, ios := NewFakeProvider(), NewFakeProvider()
+	service.RegisterProvider(models.DevicePlatformAndroid, android)
+	service.RegisterProvider(models.DevicePlatformIOS, ios)
+	return service, store, android, ios
+}
+
+func TestDeliverSendsToEveryDevice(t *testing.T) {
+	service, _, android, ios := newTestService()
+
+	if err := service.Deliver(context.Background(), notificationID); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := android.Messages()
+	if len(sent) != 1 || sent[0].Token != androidToken || sent[0].Body != "ayse liked your post" {
+		t.Errorf("android messages = %+v, want one English like message", sent)
+	}
+	sent = ios.Messages()
+	if len(sent) != 1 || sent[0].Token != iosToken || sent[0].Body != "ayse gönderini beğendi" {
+		t.Errorf("ios messages = %+v, want one Turkish like message", sent)
+	}
+}
+
+func TestDeliverRespectsPreferences(t *testing.T) {
+	service, store, android, ios := newTestService()
+	store.preferences[recipientID] = &models.NotificationPreference{UserID: recipientID, PushPostLike: false}
+
+	if err := service.Deliver(context.Background(), notificationID); err != nil {
+		t.Fatal(err)
+	}
+	if len(android.Messages())+len(ios.Messages()) != 0 {
+		t.Error("push sent although the recipient disabled like pushes")
+	}
+}
+
+func TestDeliverRetriesTransientErrors(t *testing.T) {
+	service, store, android, _ := newTestService()
+	android.Failures[androidToken] = maxAttempts - 1
+
+	if err := service.Deliver(context.Background(), notificationID); err != nil {
+		t.Fatal(err)
+	}
+	if got := android.Attempts[androidToken]; got != maxAttempts {
+		t.Errorf("attempts = %d, want %d", got, maxAttempts)
+	}
+	if len(android.Messages()) != 1 {
+		t.Errorf("messages = %+v, want delivery after retries", android.Messages())
+	}
+	if len(store.removed) != 0 {
+		t.Errorf("removed devices = %v, want none", store.removed)
+	}
+}
+
+func TestDeliverGivesUpAfterMaxAttempts(t *testing.T) {
+	service, store, android, ios := newTestService()
+	android.Failures[androidToken] = maxAttempts
+
+	// Bir cihazdaki hata diğer cihazlara gönderimi engellemez
+	if err := service.Deliver(context.Background(), notificationID); err != nil {
+		t.Fatal(err)
+	}
+	if got := android.Attempts[androidToken]; got != maxAttempts {
+		t.Errorf("attempts = %d, want %d", got, maxAttempts)
+	}
+	if len(android.Messages()) != 0 {
+		t.Errorf("messages = %+v, want none", android.Messages())
+	}
+	if len(ios.Messages()) != 1 {
+		t.Errorf("ios messages = %+v, want one", ios.Messages())
+	}
+	if len(store.removed) != 0 {
+		t.Errorf("removed devices = %v, want none", store.removed)
+	}
+}
+
+func TestDeliverRemovesInvalidTokens(t *testing.T) {
+	service, store, android, ios := newTestService()
+	android.Errors[androidToken] = ErrInvalidToken
+	ios.Errors[iosToken] = ErrRejected
+
+	if err := service.Deliver(context.Background(), notificationID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Kalıcı hatalar tekrar denenmez
+	if android.Attempts[androidToken] != 1 || ios.Attempts[iosToken] != 1 {
+		t.Errorf("attempts = %d, %d; want 1, 1", android.Attempts[androidToken], ios.Attempts[iosToken])
+	}
+	if len(store.removed) != 1 || store.removed[0] != androidToken {
+		t.Errorf("removed devices = %v, want only the invalid token", store.removed)
+	}
+}
+
+func TestDeliverMissingNotification(t *testing.T) {
+	service, _, android, _ := newTestService()
+
+	if err := service.Deliver(context.Background(), notificationID+1); !errors.Is(err, ErrNotificationNotFound) {
+		t.Fatalf("Deliver error = %v, want ErrNotificationNotFound", err)
+	}
+	if len(android.Messages()) != 0 {
+		t.Error("push sent for a missing notification")
+	}
+}
@@ -0,0 +1,101 @@
+// internal/push/store.go
+package push
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDeviceNotFound       = errors.New("device not found")
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+// Cihaz token'larının ve gönderilecek bildirimlerin okunduğu yer
+type Store interface {
+	// Sorguları ctx'e bağlı çalıştıran kopya
+	WithContext(ctx context.Context) Store
+
+	FindDevice(token string) (*models.Device, error)
+	CreateDevice(device *models.Device) error
+	UpdateDevice(device *models.Device, updates map[string]interface{}) error
+	// Token kullanıcıya kayıtlı değilse false döner
+	UnregisterDevice(token string, userID uint) (bool, error)
+	RemoveDevice(device *models.Device) error
+	Devices(userID uint) ([]models.Device, error)
+
+	// Aktörü ve yorumuyla birlikte yükler
+	LoadNotification(id uint) (*models.Notification, error)
+	// Tercihler yoksa varsayılanlarla oluşturulur
+	Preferences(userID uint) (*models.NotificationPreference, error)
+}
+
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) WithContext(ctx context.Context) Store {
+	return &PostgresStore{db: s.db.WithContext(ctx)}
+}
+
+func (s *PostgresStore) FindDevice(token string) (*models.Device, error) {
+	var device models.Device
+	if err := s.db.Where("token = ?", token).First(&device).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, err
+	}
+	return &device, nil
+}
+
+func (s *PostgresStore) CreateDevice(device *models.Device) error {
+	return s.db.Create(device).Error
+}
+
+func (s *PostgresStore) UpdateDevice(device *models.Device, updates map[string]interface{}) error {
+	return s.db.Model(device).Updates(updates).Error
+}
+
+func (s *PostgresStore) UnregisterDevice(token string, userID uint) (bool, error) {
+	result := s.db.Where("token = ? AND user_id = ?", token, userID).Delete(&models.Device{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (s *PostgresStore) RemoveDevice(device *models.Device) error {
+	return s.db.Delete(device).Error
+}
+
+func (s *PostgresStore) Devices(userID uint) ([]models.Device, error) {
+	var devices []models.Device
+	err := s.db.Where("user_id = ?", userID).Find(&devices).Error
+	return devices, err
+}
+
+func (s *PostgresStore) LoadNotification(id uint) (*models.Notification, error) {
+	var notification models.Notification
+	if err := s.db.Preload("Actor").
+		Preload("Comment").
+		First(&notification, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotificationNotFound
+		}
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (s *PostgresStore) Preferences(userID uint) (*models.NotificationPreference, error) {
+	var pref models.NotificationPreference
+	if err := s.db.FirstOrCreate(&pref, models.NotificationPreference{UserID: userID}).Error; err != nil {
+		return nil, err
+	}
+	return &pref, nil
+}
@@ -0,0 +1,57 @@
+// internal/ranking/cursor.go
+package ranking
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Sayfalar arası kararlılık için sıralamanın yapıldığı an ve son
+// gösterilen postun konumu saklanır
+type Cursor struct {
+	AsOf   time.Time `json:"t"`
+	Score  float64   `json:"s"`
+	PostID uint      `json:"p"`
+}
+
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.AsOf.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// Sıralı listeden cursor sonrasındaki sayfayı ve bir sonraki cursor'ı döner
+func Page(ranked []Candidate, asOf time.Time, cursor *Cursor, limit int) ([]Candidate, *Cursor) {
+	start := 0
+	if cursor != nil {
+		last := Candidate{PostID: cursor.PostID, Score: cursor.Score}
+		for start < len(ranked) && !last.before(&ranked[start]) {
+			start++
+		}
+	}
+
+	end := start + limit
+	if end >= len(ranked) {
+		return ranked[start:], nil
+	}
+
+	page := ranked[start:end]
+	tail := page[len(page)-1]
+	return page, &Cursor{AsOf: asOf, Score: tail.Score, PostID: tail.PostID}
+}
@@ -0,0 +1,131 @@
+// internal/ranking/score.go
+package ranking
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Sıralamada kullanılan ağırlıklar
+type Weights struct {
+	View    float64
+	Like    float64
+	Comment float64
+	Save    float64
+	Click   float64
+
+	// Zaman azalımı: skor (yaş_saat + 2)^Gravity ile bölünür
+	Gravity float64
+	// Kullanıcının ilgi alanlarıyla eşleşmenin skora etkisi
+	Affinity float64
+
+	// Aynı üreticinin akıştaki en fazla post sayısı ve her tekrar için ceza çarpanı
+	MaxPerCreator    int
+	DiversityPenalty float64
+}
+
+var DefaultWeights = Weights{
+	View:             0.1,
+	Like:             1,
+	Comment:          2,
+	Save:             2.5,
+	Click:            3,
+	Gravity:          1.5,
+	Affinity:         1,
+	MaxPerCreator:    2,
+	DiversityPenalty: 0.6,
+}
+
+// Sıralanacak aday post ve etkileşim sinyalleri
+type Candidate struct {
+	PostID     uint
+	CreatorID  uint
+	CreatedAt  time.Time
+	Views      int64
+	Likes      int64
+	Comments   int64
+	Saves      int64
+	Clicks     int64
+	Categories []uint
+	Hashtags   []uint
+
+	Score float64
+}
+
+// Kullanıcının kategori ve hashtag ilgi düzeyleri (0-1 arası)
+type Affinity struct {
+	Categories map[uint]float64
+	Hashtags   map[uint]float64
+}
+
+func (w Weights) engagement(c *Candidate) float64 {
+	return float64(c.Views)*w.View +
+		float64(c.Likes)*w.Like +
+		float64(c.Comments)*w.Comment +
+		float64(c.Saves)*w.Save +
+		float64(c.Clicks)*w.Click
+}
+
+func (w Weights) decay(age time.Duration) float64 {
+	hours := math.Max(age.Hours(), 0)
+	return 1 / math.Pow(hours+2, w.Gravity)
+}
+
+// Postun kategori ve hashtaglerinden en güçlü eşleşmelerin ortalaması
+func (a Affinity) match(c *Candidate) float64 {
+	best := func(ids []uint, weights map[uint]float64) float64 {
+		var max float64
+		for _, id := range ids {
+			if weights[id] > max {
+				max = weights[id]
+			}
+		}
+		return max
+	}
+	return (best(c.Categories, a.Categories) + best(c.Hashtags, a.Hashtags)) / 2
+}
+
+// Zaman azalımlı etkileşim ve ilgi alanı eşleşmesinden skor hesaplar
+func (w Weights) Score(c *Candidate, affinity Affinity, asOf time.Time) float64 {
+	return (1 + w.engagement(c)) *
+		w.decay(asOf.Sub(c.CreatedAt)) *
+		(1 + w.Affinity*affinity.match(c))
+}
+
+// Adayları skorlar, üretici çeşitliliği kurallarını uygular ve sıralar.
+// Aynı girdiler için sonuç her zaman aynıdır; cursor sayfalaması buna dayanır.
+func Rank(candidates []Candidate, affinity Affinity, asOf time.Time, w Weights) []Candidate {
+	for i := range candidates {
+		candidates[i].Score = w.Score(&candidates[i], affinity, asOf)
+	}
+	sortCandidates(candidates)
+
+	perCreator := make(map[uint]int)
+	ranked := make([]Candidate, 0, len(candidates))
+	for _, c := range candidates {
+		seen := perCreator[c.CreatorID]
+		if w.MaxPerCreator > 0 && seen >= w.MaxPerCreator {
+			continue
+		}
+		perCreator[c.CreatorID] = seen + 1
+		c.Score *= math.Pow(w.DiversityPenalty, float64(seen))
+		ranked = append(ranked, c)
+	}
+	sortCandidates(ranked)
+
+	return ranked
+}
+
+func sortCandidates(candidates []Candidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].before(&candidates[j])
+	})
+}
+
+func (c *Candidate) before(other *Candidate) bool {
+	if c.Score != other.Score {
+		return c.Score > other.Score
+	}
+	return c.PostID > other.PostID
+}
@@ -0,0 +1,184 @@
+// internal/ranking/service.go
+package ranking
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/sefazor/comfyn/internal/models"
+	usersvc "github.com/sefazor/comfyn/internal/user"
+	"gorm.io/gorm"
+)
+
+const (
+	// Aday postların yaş sınırı ve havuz büyüklüğü
+	candidateWindow = 7 * 24 * time.Hour
+	maxCandidates   = 500
+
+	// İlgi alanları için geriye bakılan süre
+	affinityWindow = 90 * 24 * time.Hour
+)
+
+// Önerilen akışın bir sayfasındaki post ID'lerini sıralı döner
+func Suggested(db *gorm.DB, viewerID uint, cursor *Cursor, limit int) ([]uint, *Cursor, error) {
+	asOf := time.Now()
+	if cursor != nil {
+		asOf = cursor.AsOf
+	}
+
+	candidates, err := loadCandidates(db, viewerID, asOf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	affinity, err := loadAffinity(db, viewerID, asOf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ranked := Rank(candidates, affinity, asOf, DefaultWeights)
+	page, next := Page(ranked, asOf, cursor, limit)
+
+	ids := make([]uint, len(page))
+	for i, c := range page {
+		ids[i] = c.PostID
+	}
+	return ids, next, nil
+}
+
+// Takip edilmeyen, daha önce görülmemiş ve görüntülenebilir postlar
+func loadCandidates(db *gorm.DB, viewerID uint, asOf time.Time) ([]Candidate, error) {
+	var rows []struct {
+		PostID    uint
+		CreatorID uint
+		CreatedAt time.Time
+		Views     int64
+		Likes     int64
+		Comments  int64
+		Saves     int64
+		Clicks    int64
+	}
+	// Sayaç kolonları sayfalar arasında değişir; sinyaller asOf anına kadar
+	// olan kayıtlardan sayılır ki aynı cursor hep aynı sıralamayı üretsin
+	err := db.Model(&models.Post{}).
+		Select(`posts.id AS post_id, posts.user_id AS creator_id, posts.created_at,
+			(SELECT COUNT(*) FROM post_views WHERE post_views.post_id = posts.id AND post_views.created_at <= @as_of) AS views,
+			(SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id AND likes.created_at <= @as_of) AS likes,
+			(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id AND comments.created_at <= @as_of
+				AND comments.deleted_at IS NULL AND comments.hidden_at IS NULL) AS comments,
+			(SELECT COUNT(*) FROM saved_posts WHERE saved_posts.post_id = posts.id AND saved_posts.created_at <= @as_of) AS saves,
+			(SELECT COUNT(*) FROM click_logs JOIN affiliate_links ON affiliate_links.id = click_logs.affiliate_link_id
+				WHERE affiliate_links.post_id = posts.id AND affiliate_links.deleted_at IS NULL
+				AND click_logs.created_at <= @as_of) AS clicks`, sql.Named("as_of", asOf)).
+		Where("posts.created_at BETWEEN ? AND ?", asOf.Add(-candidateWindow), asOf).
+		Where("posts.user_id != ?", viewerID).
+		Where("posts.user_id NOT IN (SELECT following_id FROM user_followers WHERE follower_id = ?)", viewerID).
+		Where("posts.user_id NOT IN (?)", usersvc.MutedUserIDs(db, viewerID)).
+		Where("posts.user_id NOT IN (?)", usersvc.BlockedUserIDs(db, viewerID)).
+		Where("posts.id NOT IN (SELECT post_id FROM post_views WHERE user_id = ? AND created_at <= ?)", viewerID, asOf).
+		Scopes(usersvc.VisiblePosts(viewerID)).
+		Order("posts.created_at DESC").
+		Limit(maxCandidates).
+		Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	candidates := make([]Candidate, len(rows))
+	for i, r := range rows {
+		candidates[i] = Candidate{
+			PostID:    r.PostID,
+			CreatorID: r.CreatorID,
+			CreatedAt: r.CreatedAt,
+			Views:     r.Views,
+			Likes:     r.Likes,
+			Comments:  r.Comments,
+			Saves:     r.Saves,
+			Clicks:    r.Clicks,
+		}
+	}
+
+	index := make(map[uint]*Candidate, len(candidates))
+	postIDs := make([]uint, len(candidates))
+	for i := range candidates {
+		index[candidates[i].PostID] = &candidates[i]
+		postIDs[i] = candidates[i].PostID
+	}
+
+	var links []struct {
+		PostID uint
+		RefID  uint
+	}
+	if err := db.Table("post_categories").Select("post_id, category_id AS ref_id").
+		Where("post_id IN ?", postIDs).Scan(&links).Error; err != nil {
+		return nil, err
+	}
+	for _, l := range links {
+		index[l.PostID].Categories = append(index[l.PostID].Categories, l.RefID)
+	}
+
+	links = links[:0]
+	if err := db.Table("post_hashtags").Select("post_id, hashtag_id AS ref_id").
+		Where("post_id IN ?", postIDs).Scan(&links).Error; err != nil {
+		return nil, err
+	}
+	for _, l := range links {
+		index[l.PostID].Hashtags = append(index[l.PostID].Hashtags, l.RefID)
+	}
+
+	return candidates, nil
+}
+
+// Kullanıcının yorum, kaydetme, beğeni ve görüntülemelerinden kategori/hashtag ilgisi çıkarır
+func loadAffinity(db *gorm.DB, viewerID uint, asOf time.Time) (Affinity, error) {
+	since := asOf.Add(-affinityWindow)
+	engagements := db.Raw(`
+		SELECT post_id, 2.0 AS weight FROM comments
+			WHERE user_id = ? AND created_at BETWEEN ? AND ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT post_id, 1.5 FROM saved_posts WHERE user_id = ? AND created_at BETWEEN ? AND ?
+		UNION ALL
+		SELECT post_id, 1.0 FROM likes WHERE user_id = ? AND created_at BETWEEN ? AND ?
+		UNION ALL
+		SELECT post_id, 0.25 FROM post_views WHERE user_id = ? AND created_at BETWEEN ? AND ?`,
+		viewerID, since, asOf, viewerID, since, asOf, viewerID, since, asOf, viewerID, since, asOf)
+
+	categories, err := affinityWeights(db, engagements, "post_categories", "category_id")
+	if err != nil {
+		return Affinity{}, err
+	}
+	hashtags, err := affinityWeights(db, engagements, "post_hashtags", "hashtag_id")
+	if err != nil {
+		return Affinity{}, err
+	}
+
+	return Affinity{Categories: categories, Hashtags: hashtags}, nil
+}
+
+func affinityWeights(db, engagements *gorm.DB, joinTable, column string) (map[uint]float64, error) {
+	var rows []struct {
+		RefID  uint
+		Weight float64
+	}
+	if err := db.Table("(?) AS e", engagements).
+		Select(joinTable + "." + column + " AS ref_id, SUM(e.weight) AS weight").
+		Joins("JOIN " + joinTable + " ON " + joinTable + ".post_id = e.post_id").
+		Group(joinTable + "." + column).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	// En güçlü ilgi 1 olacak şekilde normalize et
+	var max float64
+	for _, r := range rows {
+		if r.Weight > max {
+			max = r.Weight
+		}
+	}
+
+	weights := make(map[uint]float64, len(rows))
+	for _, r := range rows {
+		weights[r.RefID] = r.Weight / max
+	}
+	return weights, nil
+}
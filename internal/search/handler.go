@@ -0,0 +1,73 @@
+// internal/search/handler.go
+package search
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/apierror"
+	"github.com/sefazor/comfyn/pkg/pagination"
+)
+
+type SearchPostsQuery struct {
+	Query      string   `form:"q" binding:"required"`
+	CategoryID uint     `form:"category"`
+	MinPrice   *float64 `form:"minPrice" binding:"omitempty,min=0"`
+	MaxPrice   *float64 `form:"maxPrice" binding:"omitempty,min=0"`
+	CreatorID  uint     `form:"creator"`
+	Page       int      `form:"page,default=1" binding:"min=1"`
+	Limit      int      `form:"limit,default=20" binding:"min=1"`
+}
+
+type Handler struct {
+	store Store
+}
+
+func NewHandler(store Store) *Handler {
+	return &Handler{store: store}
+}
+
+// Açıklama, ürün, kategori ve hashtag'lerde tam metin arama
+func (h *Handler) SearchPostsHandler(c *gin.Context) {
+	var query SearchPostsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Validation(c, err)
+		return
+	}
+
+	query.Query = strings.TrimSpace(query.Query)
+	if query.Query == "" {
+		apierror.BadRequest(c, "Search query is required")
+		return
+	}
+
+	if query.Limit > 50 {
+		query.Limit = 50
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	store := h.store.WithContext(c.Request.Context())
+
+	posts, total, err := store.SearchPosts(currentUser.ID, query)
+	if err != nil {
+		apierror.Internal(c, "Failed to search posts")
+		return
+	}
+
+	// İzleyiciye özel durumları sayfa için toplu yükle
+	response, err := store.PostResponses(currentUser.ID, posts)
+	if err != nil {
+		apierror.Internal(c, "Failed to search posts")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"posts":      response,
+		"query":      query.Query,
+		"pagination": pagination.NumberedPage(query.Page, query.Limit, total),
+	})
+}
@@ -0,0 +1,79 @@
+// internal/search/service.go
+package search
+
+import "gorm.io/gorm"
+
+const reindexBatchSize = 500
+
+// İçerik hem Türkçe hem İngilizce olduğu için her metin iki dil
+// konfigürasyonuyla da indekslenir. Hashtag'ler kök bulmadan ('simple') eklenir.
+//
+// Ağırlıklar: A = açıklama, B = ürünler ve hashtag'ler, C = kategoriler.
+// 0003_backfill_search_vectors migrasyonu bu ifadenin bir kopyasını içerir.
+const searchVectorExpr = `
+	setweight(to_tsvector('turkish', coalesce(posts.description, '')), 'A') ||
+	setweight(to_tsvector('english', coalesce(posts.description, '')), 'A') ||
+	setweight(to_tsvector('turkish', coalesce(doc.products, '')), 'B') ||
+	setweight(to_tsvector('english', coalesce(doc.products, '')), 'B') ||
+	setweight(to_tsvector('simple', coalesce(doc.hashtags, '')), 'B') ||
+	setweight(to_tsvector('turkish', coalesce(doc.categories, '')), 'C') ||
+	setweight(to_tsvector('english', coalesce(doc.categories, '')), 'C')`
+
+const searchDocumentQuery = `
+	SELECT p.id,
+		(SELECT string_agg(pr.name || ' ' || coalesce(pr.description, ''), ' ')
+			FROM products pr
+			JOIN post_products pp ON pp.product_id = pr.id
+			WHERE pp.post_id = p.id AND pr.deleted_at IS NULL) AS products,
+		(SELECT string_agg(h.name, ' ')
+			FROM hashtags h
+			JOIN post_hashtags ph ON ph.hashtag_id = h.id
+			WHERE ph.post_id = p.id) AS hashtags,
+		(SELECT string_agg(c.name, ' ')
+			FROM categories c
+			JOIN post_categories pc ON pc.category_id = c.id
+			WHERE pc.post_id = p.id) AS categories
+	FROM posts p`
+
+// Kullanıcı sorgusunu iki dilde de eşleyen tsquery ifadesi (iki parametre alır)
+const tsQueryExpr = "(websearch_to_tsquery('turkish', ?) || websearch_to_tsquery('english', ?))"
+
+// Post'un arama vektörünü yeniden hesaplar. Post oluşturma/güncelleme
+// transaction'ı içinde, ürün ve hashtag'ler kaydedildikten sonra çağrılmalı.
+func RefreshPostIndex(tx *gorm.DB, postID uint) error {
+	return tx.Exec(`
+		UPDATE posts SET search_vector = `+searchVectorExpr+`
+		FROM (`+searchDocumentQuery+` WHERE p.id = ?) doc
+		WHERE posts.id = doc.id`, postID).Error
+}
+
+// Tüm postların arama vektörlerini parça parça yeniden oluşturur
+func ReindexPosts(db *gorm.DB) (int64, error) {
+	var total int64
+	var lastID uint
+
+	for {
+		var ids []uint
+		if err := db.Table("posts").
+			Where("id > ? AND deleted_at IS NULL", lastID).
+			Order("id").
+			Limit(reindexBatchSize).
+			Pluck("id", &ids).Error; err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		result := db.Exec(`
+			UPDATE posts SET search_vector = `+searchVectorExpr+`
+			FROM (`+searchDocumentQuery+` WHERE p.id IN ?) doc
+			WHERE posts.id = doc.id`, ids)
+		if result.Error != nil {
+			return total, result.Error
+		}
+
+		total += result.RowsAffected
+		lastID = ids[len(ids)-1]
+	}
+}
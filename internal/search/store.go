@@ -0,0 +1,87 @@
+// internal/search/store.go
+package search
+
+import (
+	"context"
+
+	"github.com/sefazor/comfyn/internal/models"
+	usersvc "github.com/sefazor/comfyn/internal/user"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Arama sorgularının çalıştığı yer
+type Store interface {
+	// Sorguları ctx'e bağlı çalıştıran kopya
+	WithContext(ctx context.Context) Store
+
+	// İzleyicinin görebildiği eşleşen postlar, alaka sırasıyla, ve toplam sayıları
+	SearchPosts(viewerID uint, query SearchPostsQuery) ([]models.Post, int64, error)
+	// İzleyiciye özel durumlarla birlikte yanıtlar
+	PostResponses(viewerID uint, posts []models.Post) ([]models.PostResponse, error)
+}
+
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) WithContext(ctx context.Context) Store {
+	return &PostgresStore{db: s.db.WithContext(ctx)}
+}
+
+func (s *PostgresStore) SearchPosts(viewerID uint, query SearchPostsQuery) ([]models.Post, int64, error) {
+	db := s.db.Model(&models.Post{}).
+		Where("posts.search_vector @@ "+tsQueryExpr, query.Query, query.Query).
+		Scopes(usersvc.VisiblePosts(viewerID))
+
+	if query.CategoryID != 0 {
+		db = db.Where("EXISTS (SELECT 1 FROM post_categories pc WHERE pc.post_id = posts.id AND pc.category_id = ?)", query.CategoryID)
+	}
+
+	// Fiyat aralığındaki en az bir ürünü olan postlar
+	if query.MinPrice != nil || query.MaxPrice != nil {
+		priceQuery := s.db.Table("products pr").
+			Select("1").
+			Joins("JOIN post_products pp ON pp.product_id = pr.id").
+			Where("pp.post_id = posts.id AND pr.deleted_at IS NULL")
+		if query.MinPrice != nil {
+			priceQuery = priceQuery.Where("pr.price >= ?", *query.MinPrice)
+		}
+		if query.MaxPrice != nil {
+			priceQuery = priceQuery.Where("pr.price <= ?", *query.MaxPrice)
+		}
+		db = db.Where("EXISTS (?)", priceQuery)
+	}
+
+	if query.CreatorID != 0 {
+		db = db.Where("posts.user_id = ?", query.CreatorID)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var posts []models.Post
+	err := db.Preload("User").
+		Preload("Products").
+		Preload("Categories").
+		Preload("Hashtags").
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "ts_rank(posts.search_vector, " + tsQueryExpr + ") DESC, posts.created_at DESC",
+			Vars:               []interface{}{query.Query, query.Query},
+			WithoutParentheses: true,
+		}}).
+		Limit(query.Limit).
+		Offset((query.Page - 1) * query.Limit).
+		Find(&posts).Error
+	return posts, total, err
+}
+
+func (s *PostgresStore) PostResponses(viewerID uint, posts []models.Post) ([]models.PostResponse, error) {
+	return usersvc.PostResponses(s.db, viewerID, posts)
+}
@@ -0,0 +1,171 @@
+// internal/seed/seed.go
+package seed
+
+import (
+	"fmt"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/internal/post"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Demo hesaplarının ortak şifresi; sadece geliştirme ortamları içindir
+const DemoPassword = "demo1234"
+
+var categories = []models.Category{
+	{Name: "Giyim", Slug: "giyim", Description: "Üst giyim, alt giyim ve dış giyim"},
+	{Name: "Ayakkabı", Slug: "ayakkabi", Description: "Sneaker, bot ve klasik ayakkabılar"},
+	{Name: "Çanta", Slug: "canta", Description: "El, omuz ve sırt çantaları"},
+	{Name: "Aksesuar", Slug: "aksesuar", Description: "Takı, saat, gözlük ve şapkalar"},
+	{Name: "Güzellik", Slug: "guzellik", Description: "Makyaj, cilt ve saç bakımı"},
+	{Name: "Ev & Yaşam", Slug: "ev-yasam", Description: "Dekorasyon ve ev tekstili"},
+	{Name: "Elektronik", Slug: "elektronik", Description: "Telefon, kulaklık ve aksesuarları"},
+	{Name: "Spor", Slug: "spor", Description: "Spor giyim ve ekipmanları"},
+}
+
+type demoUser struct {
+	FullName  string
+	Username  string
+	Biography string
+}
+
+var demoUsers = []demoUser{
+	{FullName: "Ayşe Demir", Username: "demo_ayse", Biography: "Minimal stil ve kapsül gardırop"},
+	{FullName: "Mehmet Kaya", Username: "demo_mehmet", Biography: "Sneaker koleksiyoncusu"},
+	{FullName: "Zeynep Arslan", Username: "demo_zeynep", Biography: "Cilt bakımı ve ev dekorasyonu"},
+}
+
+type demoPost struct {
+	Author      string
+	Description string
+	Category    string
+	Hashtags    []string
+	Products    []post.ProductInput
+}
+
+// Ürün kategorileri post kategorisiyle aynıdır; CategoryIDs Demo içinde doldurulur
+var demoPosts = []demoPost{
+	{
+		Author:      "demo_ayse",
+		Description: "Sonbahar için katmanlı kombin",
+		Category:    "giyim",
+		Hashtags:    []string{"sonbahar", "kombin"},
+		Products: []post.ProductInput{
+			{Name: "Oversize Trençkot", Price: 2499.90, Link: "https://example.com/products/trenckot"},
+			{Name: "Triko Kazak", Price: 899.90, Link: "https://example.com/products/triko-kazak"},
+		},
+	},
+	{
+		Author:      "demo_mehmet",
+		Description: "Haftanın favori sneaker'ı",
+		Category:    "ayakkabi",
+		Hashtags:    []string{"sneaker", "streetstyle"},
+		Products: []post.ProductInput{
+			{Name: "Retro Koşu Ayakkabısı", Price: 3299.00, Link: "https://example.com/products/retro-kosu"},
+		},
+	},
+	{
+		Author:      "demo_zeynep",
+		Description: "Akşam cilt bakım rutinim",
+		Category:    "guzellik",
+		Hashtags:    []string{"ciltbakimi", "rutin"},
+		Products: []post.ProductInput{
+			{Name: "Nemlendirici Krem", Price: 449.50, Link: "https://example.com/products/nemlendirici"},
+			{Name: "C Vitamini Serumu", Price: 599.00, Link: "https://example.com/products/c-vitamini"},
+		},
+	},
+}
+
+// Demo hesaplarının oluşturulması için gereken kullanıcı işlemleri (user.Store karşılar)
+type UserStore interface {
+	UsernameTaken(username string) (bool, error)
+	Create(user *models.User) error
+	Follow(followerID, followingID uint) error
+}
+
+// Eksik kategorileri ekler, mevcutlara dokunmaz. Eklenen kategori sayısını döner.
+func Categories(db *gorm.DB) (int64, error) {
+	rows := make([]models.Category, len(categories))
+	copy(rows, categories)
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows)
+	return result.RowsAffected, result.Error
+}
+
+// Demo kullanıcıları, aralarındaki takip ilişkilerini ve örnek postları
+// oluşturur. Demo hesapları zaten varsa hiçbir şey yapmaz ve false döner.
+func Demo(db *gorm.DB, users UserStore, posts *post.Service) (bool, error) {
+	if taken, err := users.UsernameTaken(demoUsers[0].Username); err != nil {
+		return false, err
+	} else if taken {
+		return false, nil
+	}
+
+	var categoryRows []models.Category
+	if err := db.Find(&categoryRows).Error; err != nil {
+		return false, err
+	}
+	categoryIDs := make(map[string]uint, len(categoryRows))
+	for _, c := range categoryRows {
+		categoryIDs[c.Slug] = c.ID
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(DemoPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return false, err
+	}
+
+	userIDs := make(map[string]uint, len(demoUsers))
+	for _, u := range demoUsers {
+		user := models.User{
+			FullName:     u.FullName,
+			Email:        u.Username + "@example.com",
+			Username:     u.Username,
+			Password:     string(hashedPassword),
+			ProfileImage: "https://example.com/default-profile.jpg",
+			Biography:    u.Biography,
+		}
+		if err := users.Create(&user); err != nil {
+			return false, fmt.Errorf("create user %s: %w", u.Username, err)
+		}
+		userIDs[u.Username] = user.ID
+	}
+
+	// Herkes herkesi takip eder; postlardan önce kurulur ki postlar akışlara yazılsın
+	for _, follower := range demoUsers {
+		for _, following := range demoUsers {
+			if follower.Username == following.Username {
+				continue
+			}
+			if err := users.Follow(userIDs[follower.Username], userIDs[following.Username]); err != nil {
+				return false, fmt.Errorf("follow %s -> %s: %w", follower.Username, following.Username, err)
+			}
+		}
+	}
+
+	for _, p := range demoPosts {
+		categoryID, ok := categoryIDs[p.Category]
+		if !ok {
+			return false, fmt.Errorf("category %q not found; seed categories first", p.Category)
+		}
+
+		products := make([]post.ProductInput, len(p.Products))
+		for i, product := range p.Products {
+			product.CategoryIDs = []uint{categoryID}
+			products[i] = product
+		}
+
+		if _, err := posts.CreatePost(userIDs[p.Author], post.CreatePostInput{
+			ImageURL:    "https://example.com/demo/" + p.Category + ".jpg",
+			Description: p.Description,
+			Products:    products,
+			CategoryIDs: []uint{categoryID},
+			Hashtags:    p.Hashtags,
+		}); err != nil {
+			return false, fmt.Errorf("create post for %s: %w", p.Author, err)
+		}
+	}
+	return true, nil
+}
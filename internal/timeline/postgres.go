@@ -0,0 +1,92 @@
+// internal/timeline/postgres.go
+package timeline
+
+import (
+	"context"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const insertBatchSize = 1000
+
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) insert(ctx context.Context, rows []models.TimelineEntry) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(rows, insertBatchSize).Error
+}
+
+func (s *PostgresStore) Add(ctx context.Context, ownerIDs []uint, entry Entry) error {
+	rows := make([]models.TimelineEntry, len(ownerIDs))
+	for i, ownerID := range ownerIDs {
+		rows[i] = models.TimelineEntry{
+			OwnerID:       ownerID,
+			PostID:        entry.PostID,
+			AuthorID:      entry.AuthorID,
+			PostCreatedAt: entry.CreatedAt,
+		}
+	}
+	return s.insert(ctx, rows)
+}
+
+func (s *PostgresStore) Backfill(ctx context.Context, ownerID uint, entries []Entry) error {
+	rows := make([]models.TimelineEntry, len(entries))
+	for i, entry := range entries {
+		rows[i] = models.TimelineEntry{
+			OwnerID:       ownerID,
+			PostID:        entry.PostID,
+			AuthorID:      entry.AuthorID,
+			PostCreatedAt: entry.CreatedAt,
+		}
+	}
+	return s.insert(ctx, rows)
+}
+
+func (s *PostgresStore) RemovePost(ctx context.Context, postID uint) error {
+	return s.db.WithContext(ctx).Where("post_id = ?", postID).Delete(&models.TimelineEntry{}).Error
+}
+
+func (s *PostgresStore) RemoveAuthor(ctx context.Context, ownerID, authorID uint) error {
+	return s.db.WithContext(ctx).Where("owner_id = ? AND author_id = ?", ownerID, authorID).
+		Delete(&models.TimelineEntry{}).Error
+}
+
+func (s *PostgresStore) Reset(ctx context.Context, ownerID uint) error {
+	return s.db.WithContext(ctx).Where("owner_id = ?", ownerID).Delete(&models.TimelineEntry{}).Error
+}
+
+func (s *PostgresStore) PurgeUser(ctx context.Context, userID uint) error {
+	return s.db.WithContext(ctx).Where("owner_id = ? OR author_id = ?", userID, userID).
+		Delete(&models.TimelineEntry{}).Error
+}
+
+func (s *PostgresStore) Page(ctx context.Context, ownerID uint, after *pagination.Cursor, limit int) ([]Entry, error) {
+	query := s.db.WithContext(ctx).Model(&models.TimelineEntry{}).Where("owner_id = ?", ownerID)
+	if after != nil && after.Time != nil {
+		query = query.Where("(post_created_at, post_id) < (?, ?)", *after.Time, after.ID)
+	}
+
+	var rows []models.TimelineEntry
+	if err := query.Order("post_created_at DESC, post_id DESC").Limit(limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, len(rows))
+	for i, row := range rows {
+		entries[i] = Entry{PostID: row.PostID, AuthorID: row.AuthorID, CreatedAt: row.PostCreatedAt}
+	}
+	return entries, nil
+}
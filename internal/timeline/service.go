@@ -0,0 +1,245 @@
+// internal/timeline/service.go
+package timeline
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/jobs"
+	"github.com/sefazor/comfyn/pkg/pagination"
+	"gorm.io/gorm"
+)
+
+// Yeni takipte akışa eklenen en fazla post sayısı
+const backfillLimit = 100
+
+// Akış kayıtlarını store'a yazar; takip ilişkileri ve postlar db'den okunur
+type Service struct {
+	store Store
+	db    *gorm.DB
+	// Bu sayıdan fazla takipçisi olan hesapların postları akışlara yazılmaz,
+	// okuma sırasında çekilir. Hangi yolun kullanıldığı posta işaretlenir;
+	// hesap eşiğin altına inince eski postları kaybolmaz.
+	fanOutMaxFollowers int
+}
+
+// Akış yazımları isteği bekletmemek için kuyruk üzerinden yapılır. İşler
+// çağıranın verdiği kuyruğa eklenir; kuyruk postu ya da takibi yazan
+// transaction'a bağlıysa iş ancak commit ile birlikte görünür olur.
+var (
+	publishJob      = jobs.NewKind[Entry]("timeline.publish")
+	removePostJob   = jobs.NewKind[uint]("timeline.remove_post")
+	backfillJob     = jobs.NewKind[followPayload]("timeline.backfill")
+	removeAuthorJob = jobs.NewKind[followPayload]("timeline.remove_author")
+)
+
+type followPayload struct {
+	FollowerID uint `json:"followerId"`
+	AuthorID   uint `json:"authorId"`
+}
+
+func NewService(store Store, db *gorm.DB, fanOutMaxFollowers int) *Service {
+	return &Service{store: store, db: db, fanOutMaxFollowers: fanOutMaxFollowers}
+}
+
+// Kuyruktaki akış işlerini işleyen handler'ları worker'a ekler
+func (s *Service) RegisterJobs(w *jobs.Worker) {
+	jobs.Handle(w, publishJob, func(ctx context.Context, entry Entry) error {
+		return s.Publish(ctx, models.Post{ID: entry.PostID, UserID: entry.AuthorID, CreatedAt: entry.CreatedAt})
+	})
+	jobs.Handle(w, removePostJob, func(ctx context.Context, postID uint) error {
+		return s.store.RemovePost(ctx, postID)
+	})
+	jobs.Handle(w, backfillJob, func(ctx context.Context, p followPayload) error {
+		// İş beklerken takip bırakılmış olabilir
+		var following int64
+		if err := s.db.WithContext(ctx).Table("user_followers").
+			Where("follower_id = ? AND following_id = ?", p.FollowerID, p.AuthorID).
+			Count(&following).Error; err != nil || following == 0 {
+			return err
+		}
+		return s.backfill(ctx, p.FollowerID, p.AuthorID)
+	})
+	jobs.Handle(w, removeAuthorJob, func(ctx context.Context, p followPayload) error {
+		return s.store.RemoveAuthor(ctx, p.FollowerID, p.AuthorID)
+	})
+}
+
+func (s *Service) isLargeAccount(authorID uint) (bool, error) {
+	var followerCount int
+	if err := s.db.Model(&models.User{}).Where("id = ?", authorID).
+		Pluck("follower_count", &followerCount).Error; err != nil {
+		return false, err
+	}
+	return followerCount > s.fanOutMaxFollowers, nil
+}
+
+// Yeni postu takipçilerin akışlarına arka planda yazar
+func (s *Service) PublishAsync(ctx context.Context, q *jobs.Queue, post models.Post) error {
+	entry := Entry{PostID: post.ID, AuthorID: post.UserID, CreatedAt: post.CreatedAt}
+	return publishJob.Enqueue(ctx, q, entry)
+}
+
+func (s *Service) Publish(ctx context.Context, post models.Post) error {
+	large, err := s.isLargeAccount(post.UserID)
+	if err != nil || large {
+		return err
+	}
+
+	var followerIDs []uint
+	if err := s.db.WithContext(ctx).Table("user_followers").
+		Where("following_id = ?", post.UserID).
+		Pluck("follower_id", &followerIDs).Error; err != nil {
+		return err
+	}
+
+	if err := s.store.Add(ctx, followerIDs, Entry{PostID: post.ID, AuthorID: post.UserID, CreatedAt: post.CreatedAt}); err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Model(&models.Post{}).Where("id = ?", post.ID).
+		Update("fanned_out", true).Error
+}
+
+// Silinen postu akışlardan kaldırır
+func (s *Service) Remove(ctx context.Context, q *jobs.Queue, postID uint) error {
+	return removePostJob.Enqueue(ctx, q, postID)
+}
+
+// Yeni takipte üreticinin son postlarını takipçinin akışına ekler
+func (s *Service) Followed(ctx context.Context, q *jobs.Queue, followerID, authorID uint) error {
+	return backfillJob.Enqueue(ctx, q, followPayload{FollowerID: followerID, AuthorID: authorID})
+}
+
+// Sadece akışlara yazılmış postlar eklenir; diğerleri zaten okuma
+// sırasında çekilir
+func (s *Service) backfill(ctx context.Context, followerID, authorID uint) error {
+	var posts []models.Post
+	if err := s.db.WithContext(ctx).Select("id, user_id, created_at").
+		Where("user_id = ? AND fanned_out", authorID).
+		Order("created_at DESC").
+		Limit(backfillLimit).
+		Find(&posts).Error; err != nil {
+		return err
+	}
+
+	entries := make([]Entry, len(posts))
+	for i, post := range posts {
+		entries[i] = Entry{PostID: post.ID, AuthorID: post.UserID, CreatedAt: post.CreatedAt}
+	}
+	return s.store.Backfill(ctx, followerID, entries)
+}
+
+// Takip bırakıldığında üreticinin postlarını akıştan kaldırır
+func (s *Service) Unfollowed(ctx context.Context, q *jobs.Queue, followerID, authorID uint) error {
+	return removeAuthorJob.Enqueue(ctx, q, followPayload{FollowerID: followerID, AuthorID: authorID})
+}
+
+// Silinen kullanıcının akışını ve postlarını temizler
+func (s *Service) PurgeUser(ctx context.Context, userID uint) error {
+	return s.store.PurgeUser(ctx, userID)
+}
+
+// Tüm kullanıcıların akışlarını baştan oluşturur (ilk kurulum veya veri onarımı)
+func (s *Service) RebuildAll(ctx context.Context) (int, error) {
+	var userIDs []uint
+	if err := s.db.WithContext(ctx).Model(&models.User{}).
+		Where("deactivated_at IS NULL").
+		Pluck("id", &userIDs).Error; err != nil {
+		return 0, err
+	}
+
+	for i, userID := range userIDs {
+		if err := s.Rebuild(ctx, userID); err != nil {
+			return i, err
+		}
+	}
+	return len(userIDs), nil
+}
+
+// Kullanıcının akışını takip ettiği hesaplardan baştan oluşturur
+func (s *Service) Rebuild(ctx context.Context, userID uint) error {
+	if err := s.store.Reset(ctx, userID); err != nil {
+		return err
+	}
+
+	var authorIDs []uint
+	if err := s.db.WithContext(ctx).Table("user_followers").
+		Where("follower_id = ?", userID).
+		Pluck("following_id", &authorIDs).Error; err != nil {
+		return err
+	}
+
+	for _, authorID := range authorIDs {
+		if err := s.backfill(ctx, userID, authorID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Akışın bir sayfasındaki post ID'lerini döner. Yazılmış kayıtlar ile takip
+// edilen hesapların akışlara yazılmamış postları okuma sırasında birleştirilir.
+func (s *Service) Read(ctx context.Context, viewerID uint, params pagination.Params) ([]uint, pagination.Page, error) {
+	entries, err := s.store.Page(ctx, viewerID, params.After, params.Limit+1)
+	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	pulled, err := s.pullUnfannedPosts(ctx, viewerID, params)
+	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	merged := mergeEntries(entries, pulled)
+	if len(merged) > params.Limit+1 {
+		merged = merged[:params.Limit+1]
+	}
+
+	merged, page := pagination.Paginate(merged, params, Entry.cursor)
+
+	postIDs := make([]uint, len(merged))
+	for i, entry := range merged {
+		postIDs[i] = entry.PostID
+	}
+	return postIDs, page, nil
+}
+
+func (s *Service) pullUnfannedPosts(ctx context.Context, viewerID uint, params pagination.Params) ([]Entry, error) {
+	query := s.db.WithContext(ctx).Model(&models.Post{}).
+		Select("posts.id, posts.user_id, posts.created_at").
+		Joins("JOIN user_followers uf ON uf.following_id = posts.user_id AND uf.follower_id = ?", viewerID).
+		Where("NOT posts.fanned_out")
+
+	var posts []models.Post
+	if err := params.Apply(query, pagination.Order{Column: "posts.created_at", IDColumn: "posts.id"}).
+		Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, len(posts))
+	for i, post := range posts {
+		entries[i] = Entry{PostID: post.ID, AuthorID: post.UserID, CreatedAt: post.CreatedAt}
+	}
+	return entries, nil
+}
+
+// İki sıralı listeyi en yeniden eskiye birleştirir, tekrar eden postları atar
+func mergeEntries(a, b []Entry) []Entry {
+	seen := make(map[uint]bool, len(a)+len(b))
+	merged := make([]Entry, 0, len(a)+len(b))
+	for _, entry := range append(a, b...) {
+		if !seen[entry.PostID] {
+			seen[entry.PostID] = true
+			merged = append(merged, entry)
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		if !merged[i].CreatedAt.Equal(merged[j].CreatedAt) {
+			return merged[i].CreatedAt.After(merged[j].CreatedAt)
+		}
+		return merged[i].PostID > merged[j].PostID
+	})
+	return merged
+}
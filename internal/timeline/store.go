@@ -0,0 +1,39 @@
+// internal/timeline/store.go
+package timeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/sefazor/comfyn/pkg/pagination"
+)
+
+// Akıştaki tek bir post
+type Entry struct {
+	PostID    uint
+	AuthorID  uint
+	CreatedAt time.Time
+}
+
+func (e Entry) cursor() pagination.Cursor {
+	return pagination.TimeKey(e.CreatedAt, e.PostID)
+}
+
+// Akış depolama arayüzü. Şu an Postgres tablosu kullanılıyor; sıralı küme
+// destekleyen başka bir depo (ör. Redis) aynı arayüzle eklenebilir.
+type Store interface {
+	// Postu verilen kullanıcıların akışlarına ekler
+	Add(ctx context.Context, ownerIDs []uint, entry Entry) error
+	// Kullanıcının akışına birden fazla post ekler (takipte geriye dönük doldurma)
+	Backfill(ctx context.Context, ownerID uint, entries []Entry) error
+	// Postu tüm akışlardan kaldırır
+	RemovePost(ctx context.Context, postID uint) error
+	// Üreticinin postlarını kullanıcının akışından kaldırır
+	RemoveAuthor(ctx context.Context, ownerID, authorID uint) error
+	// Kullanıcının akışını boşaltır
+	Reset(ctx context.Context, ownerID uint) error
+	// Kullanıcının akışını ve diğer akışlardaki postlarını siler
+	PurgeUser(ctx context.Context, userID uint) error
+	// Cursor sonrasındaki en fazla limit kaydı en yeniden eskiye döner
+	Page(ctx context.Context, ownerID uint, after *pagination.Cursor, limit int) ([]Entry, error)
+}
@@ -0,0 +1,40 @@
+// internal/user/counters.go
+package user
+
+import (
+	"gorm.io/gorm"
+)
+
+// Kullanıcı üzerindeki sayaç sütunları
+type Counter string
+
+const (
+	FollowerCounter  Counter = "follower_count"
+	FollowingCounter Counter = "following_count"
+	TotalViewCounter Counter = "total_views"
+)
+
+var Counters = []Counter{FollowerCounter, FollowingCounter, TotalViewCounter}
+
+// Sayaçların kaynak tablolardan hesaplanma şekli
+var counterSources = map[Counter]string{
+	FollowerCounter:  "SELECT COUNT(*) FROM user_followers WHERE user_followers.following_id = users.id",
+	FollowingCounter: "SELECT COUNT(*) FROM user_followers WHERE user_followers.follower_id = users.id",
+	TotalViewCounter: "SELECT COUNT(*) FROM post_views JOIN posts ON posts.id = post_views.post_id WHERE posts.user_id = users.id",
+}
+
+// Sayaçları kaynak tablolardan yeniden hesaplar ve düzeltilen kullanıcı sayısını
+// döner. Anonimleştirilmiş hesapların sayaçları sıfırda kalır.
+func ReconcileCounters(db *gorm.DB) (map[Counter]int64, error) {
+	fixed := make(map[Counter]int64, len(counterSources))
+	for _, counter := range Counters {
+		column, source := string(counter), counterSources[counter]
+		result := db.Exec("UPDATE users SET " + column + " = (" + source + ") WHERE anonymized_at IS NULL AND " +
+			column + " IS DISTINCT FROM (" + source + ")")
+		if result.Error != nil {
+			return fixed, result.Error
+		}
+		fixed[counter] = result.RowsAffected
+	}
+	return fixed, nil
+}
@@ -1,14 +1,19 @@
 package user
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sefazor/comfyn/internal/models"
-	"github.com/sefazor/comfyn/pkg/database"
+	"github.com/sefazor/comfyn/internal/notification"
+	"github.com/sefazor/comfyn/pkg/apierror"
+	"github.com/sefazor/comfyn/pkg/jobs"
+	"github.com/sefazor/comfyn/pkg/pagination"
 	"golang.org/x/crypto/bcrypt"
-	"gorm.io/gorm"
 )
 
 type UpdateProfileInput struct {
@@ -16,6 +21,7 @@ type UpdateProfileInput struct {
 	InstagramUsername string `json:"instagramUsername"`
 	Username          string `json:"username"`
 	ProfileImage      string `json:"profileImage"`
+	IsPrivate         *bool  `json:"isPrivate"`
 }
 
 type UpdateSecurityInput struct {
@@ -26,58 +32,123 @@ type UpdateSecurityInput struct {
 
 type SearchUsersQuery struct {
 	Query string `form:"q"`
-	Page  int    `form:"page,default=1"`
-	Limit int    `form:"limit,default=20"`
+}
+
+const maxMutualFollowers = 3
+
+// Başka kullanıcının profili; izleyiciyle ilişkisini de içerir
+type ProfileResponse struct {
+	models.UserResponse
+	IsFollowing bool            `json:"isFollowing"`
+	IsBlocked   bool            `json:"isBlocked"`
+	IsMuted     bool            `json:"isMuted"`
+	IsRequested bool            `json:"isRequested"`
+	FollowedBy  MutualFollowers `json:"followedBy"`
+}
+
+type MutualFollowers struct {
+	Users []models.UserResponse `json:"users"`
+	Total int64                 `json:"total"`
+}
+
+type FollowRequestResponse struct {
+	ID        uint                `json:"id"`
+	Requester models.UserResponse `json:"requester"`
+	CreatedAt time.Time           `json:"createdAt"`
+}
+
+// Takip değişikliklerini ana sayfa akışlarına yansıtır; işler takibi
+// değiştiren transaction'ın kuyruğuna eklenir
+type Timeline interface {
+	Followed(ctx context.Context, q *jobs.Queue, followerID, authorID uint) error
+	Unfollowed(ctx context.Context, q *jobs.Queue, followerID, authorID uint) error
+}
+
+type Handler struct {
+	store         Store
+	notifications notification.Notifier
+	timeline      Timeline
+	queue         *jobs.Queue
+}
+
+func NewHandler(store Store, notifications notification.Notifier, timeline Timeline, queue *jobs.Queue) *Handler {
+	return &Handler{store: store, notifications: notifications, timeline: timeline, queue: queue}
+}
+
+// İsteğin context'ine bağlı store
+func (h *Handler) storeFor(c *gin.Context) Store {
+	return h.store.WithContext(c.Request.Context())
 }
 
 // Kendi profilini görüntüleme
-func GetProfileHandler(c *gin.Context) {
+func (h *Handler) GetProfileHandler(c *gin.Context) {
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	if err := database.DB.First(&currentUser, currentUser.ID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	profile, err := h.storeFor(c).FindByID(currentUser.ID)
+	if err != nil {
+		apierror.NotFound(c, "User not found")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"user": currentUser.SafeResponse()})
+	c.JSON(http.StatusOK, gin.H{"user": profile.SafeResponse()})
 }
 
 // Başka kullanıcının profilini görüntüleme
-func GetUserProfileHandler(c *gin.Context) {
+func (h *Handler) GetUserProfileHandler(c *gin.Context) {
 	userIDStr := c.Param("id")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		apierror.BadRequest(c, "Invalid user ID")
 		return
 	}
 
-	var targetUser models.User
-	if err := database.DB.First(&targetUser, userID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	targetUser, err := h.storeFor(c).FindActiveByID(uint(userID))
+	if err != nil {
+		apierror.NotFound(c, "User not found")
 		return
 	}
 
-	// Takip durumunu kontrol et
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	var count int64
-	database.DB.Table("user_followers").
-		Where("follower_id = ? AND following_id = ?", currentUser.ID, targetUser.ID).
-		Count(&count)
+	relation, err := h.storeFor(c).Relation(currentUser.ID, targetUser.ID)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch user")
+		return
+	}
+
+	// Bizi engelleyen kullanıcının profili görünmez
+	if relation.BlockedBy {
+		apierror.NotFound(c, "User not found")
+		return
+	}
 
-	response := targetUser.SafeResponse()
-	response["isFollowing"] = count > 0
+	// Takip ettiğin kişilerden bu kullanıcıyı takip edenler
+	followedBy, total, err := h.storeFor(c).MutualFollowers(currentUser.ID, targetUser.ID, maxMutualFollowers)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch mutual followers")
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"user": response})
+	c.JSON(http.StatusOK, gin.H{"user": ProfileResponse{
+		UserResponse: targetUser.SafeResponse(),
+		IsFollowing:  relation.IsFollowing,
+		IsBlocked:    relation.IsBlocked,
+		IsMuted:      relation.IsMuted,
+		IsRequested:  relation.IsRequested,
+		FollowedBy: MutualFollowers{
+			Users: models.SafeResponses(followedBy),
+			Total: total,
+		},
+	}})
 }
 
 // Profil güncelleme
-func UpdateProfileHandler(c *gin.Context) {
+func (h *Handler) UpdateProfileHandler(c *gin.Context) {
 	var input UpdateProfileInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Validation(c, err)
 		return
 	}
 
@@ -86,9 +157,8 @@ func UpdateProfileHandler(c *gin.Context) {
 
 	// Username kontrolü
 	if input.Username != "" && input.Username != currentUser.Username {
-		var existingUser models.User
-		if err := database.DB.Where("username = ?", input.Username).First(&existingUser).Error; err == nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Username already exists"})
+		if taken, err := h.storeFor(c).UsernameTaken(input.Username); err != nil || taken {
+			apierror.BadRequest(c, "Username already exists")
 			return
 		}
 		currentUser.Username = input.Username
@@ -100,8 +170,53 @@ func UpdateProfileHandler(c *gin.Context) {
 		currentUser.ProfileImage = input.ProfileImage
 	}
 
-	if err := database.DB.Save(&currentUser).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+	becamePublic := false
+	if input.IsPrivate != nil {
+		becamePublic = currentUser.IsPrivate && !*input.IsPrivate
+		currentUser.IsPrivate = *input.IsPrivate
+	}
+
+	tx, err := h.storeFor(c).Begin()
+	if err != nil {
+		apierror.Internal(c, "Failed to update profile")
+		return
+	}
+	defer tx.Rollback()
+
+	if err := tx.Save(&currentUser); err != nil {
+		apierror.Internal(c, "Failed to update profile")
+		return
+	}
+
+	// Hesap herkese açık hale geldiyse bekleyen istekleri onayla
+	if becamePublic {
+		requests, err := tx.IncomingFollowRequests(currentUser.ID)
+		if err != nil {
+			apierror.Internal(c, "Failed to fetch follow requests")
+			return
+		}
+
+		for _, request := range requests {
+			if err := tx.ApproveFollowRequest(request); err != nil {
+				apierror.Internal(c, "Failed to approve follow requests")
+				return
+			}
+			if err := h.timeline.Followed(c.Request.Context(), tx.Jobs(h.queue), request.RequesterID, request.TargetID); err != nil {
+				apierror.Internal(c, "Failed to approve follow requests")
+				return
+			}
+		}
+
+		updated, err := tx.FindByID(currentUser.ID)
+		if err != nil {
+			apierror.Internal(c, "Failed to load profile")
+			return
+		}
+		currentUser = *updated
+	}
+
+	if err := tx.Commit(); err != nil {
+		apierror.Internal(c, "Failed to update profile")
 		return
 	}
 
@@ -109,10 +224,10 @@ func UpdateProfileHandler(c *gin.Context) {
 }
 
 // Güvenlik ayarlarını güncelleme
-func UpdateSecurityHandler(c *gin.Context) {
+func (h *Handler) UpdateSecurityHandler(c *gin.Context) {
 	var input UpdateSecurityInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Validation(c, err)
 		return
 	}
 
@@ -121,15 +236,14 @@ func UpdateSecurityHandler(c *gin.Context) {
 
 	// Mevcut şifreyi kontrol et
 	if err := bcrypt.CompareHashAndPassword([]byte(currentUser.Password), []byte(input.CurrentPassword)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+		apierror.Unauthorized(c, "Current password is incorrect")
 		return
 	}
 
 	// Email kontrolü
 	if input.Email != "" && input.Email != currentUser.Email {
-		var existingUser models.User
-		if err := database.DB.Where("email = ?", input.Email).First(&existingUser).Error; err == nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Email already exists"})
+		if taken, err := h.storeFor(c).EmailTaken(input.Email); err != nil || taken {
+			apierror.BadRequest(c, "Email already exists")
 			return
 		}
 		currentUser.Email = input.Email
@@ -138,13 +252,13 @@ func UpdateSecurityHandler(c *gin.Context) {
 	// Yeni şifreyi hashle
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		apierror.Internal(c, "Failed to hash password")
 		return
 	}
 	currentUser.Password = string(hashedPassword)
 
-	if err := database.DB.Save(&currentUser).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update security settings"})
+	if err := h.storeFor(c).Save(&currentUser); err != nil {
+		apierror.Internal(c, "Failed to update security settings")
 		return
 	}
 
@@ -155,11 +269,11 @@ func UpdateSecurityHandler(c *gin.Context) {
 }
 
 // Follow/Unfollow işlemi
-func FollowUserHandler(c *gin.Context) {
+func (h *Handler) FollowUserHandler(c *gin.Context) {
 	targetUserIDStr := c.Param("id")
 	targetUserID, err := strconv.ParseUint(targetUserIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		apierror.BadRequest(c, "Invalid user ID")
 		return
 	}
 
@@ -167,47 +281,56 @@ func FollowUserHandler(c *gin.Context) {
 	currentUser := user.(models.User)
 
 	if currentUser.ID == uint(targetUserID) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot follow yourself"})
+		apierror.BadRequest(c, "You cannot follow yourself")
+		return
+	}
+
+	tx, err := h.storeFor(c).Begin()
+	if err != nil {
+		apierror.Internal(c, "Failed to follow user")
 		return
 	}
+	defer tx.Rollback()
 
-	tx := database.DB.Begin()
+	targetUser, err := tx.FindByID(uint(targetUserID))
+	if err != nil {
+		apierror.NotFound(c, "User not found")
+		return
+	}
 
-	var targetUser models.User
-	if err := tx.First(&targetUser, targetUserID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	// Engelleme varsa takip edilemez
+	blocked, err := tx.IsBlockedBetween(currentUser.ID, targetUser.ID)
+	if err != nil {
+		apierror.Internal(c, "Failed to check block status")
+		return
+	}
+	if blocked {
+		apierror.Forbidden(c, "You cannot follow this user")
 		return
 	}
 
-	var count int64
-	tx.Table("user_followers").
-		Where("follower_id = ? AND following_id = ?", currentUser.ID, targetUser.ID).
-		Count(&count)
+	following, err := tx.IsFollowing(currentUser.ID, targetUser.ID)
+	if err != nil {
+		apierror.Internal(c, "Failed to check follow status")
+		return
+	}
 
-	if count > 0 {
+	if following {
 		// Unfollow
-		if err := tx.Exec("DELETE FROM user_followers WHERE follower_id = ? AND following_id = ?",
-			currentUser.ID, targetUser.ID).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfollow user"})
+		if err := tx.Unfollow(currentUser.ID, targetUser.ID); err != nil {
+			apierror.Internal(c, "Failed to unfollow user")
 			return
 		}
-
-		// Sayaçları güncelle
-		if err := tx.Model(&targetUser).Update("follower_count", gorm.Expr("follower_count - ?", 1)).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update follower count"})
+		if err := h.timeline.Unfollowed(c.Request.Context(), tx.Jobs(h.queue), currentUser.ID, targetUser.ID); err != nil {
+			apierror.Internal(c, "Failed to unfollow user")
 			return
 		}
 
-		if err := tx.Model(&currentUser).Update("following_count", gorm.Expr("following_count - ?", 1)).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update following count"})
+		if err := tx.Commit(); err != nil {
+			apierror.Internal(c, "Failed to unfollow user")
 			return
 		}
 
-		tx.Commit()
 		c.JSON(http.StatusOK, gin.H{
 			"message":     "Successfully unfollowed user",
 			"isFollowing": false,
@@ -215,28 +338,79 @@ func FollowUserHandler(c *gin.Context) {
 		return
 	}
 
-	// Follow
-	if err := tx.Exec("INSERT INTO user_followers (follower_id, following_id) VALUES (?, ?)",
-		currentUser.ID, targetUser.ID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow user"})
+	// Gizli hesaplar için takip isteği gönder/geri çek
+	if targetUser.IsPrivate {
+		existingRequest, err := tx.FindFollowRequest(currentUser.ID, targetUser.ID)
+		if err == nil {
+			if err := tx.CancelFollowRequest(existingRequest); err != nil {
+				apierror.Internal(c, "Failed to cancel follow request")
+				return
+			}
+
+			if err := tx.Commit(); err != nil {
+				apierror.Internal(c, "Failed to cancel follow request")
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{
+				"message":     "Follow request cancelled",
+				"isFollowing": false,
+				"isRequested": false,
+			})
+			return
+		}
+		if !errors.Is(err, ErrNotFound) {
+			apierror.Internal(c, "Failed to send follow request")
+			return
+		}
+
+		request := models.FollowRequest{
+			RequesterID: currentUser.ID,
+			TargetID:    targetUser.ID,
+		}
+		if err := tx.CreateFollowRequest(&request); err != nil {
+			apierror.Internal(c, "Failed to send follow request")
+			return
+		}
+		if err := h.notifications.Notify(
+			c.Request.Context(),
+			tx.Jobs(h.queue),
+			targetUser.ID,
+			currentUser.ID,
+			models.NotificationFollowRequest,
+			nil,
+			nil,
+		); err != nil {
+			apierror.Internal(c, "Failed to send follow request")
+			return
+		}
+
+		if err := tx.Commit(); err != nil {
+			apierror.Internal(c, "Failed to send follow request")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":     "Follow request sent",
+			"isFollowing": false,
+			"isRequested": true,
+		})
 		return
 	}
 
-	// Sayaçları güncelle
-	if err := tx.Model(&targetUser).Update("follower_count", gorm.Expr("follower_count + ?", 1)).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update follower count"})
+	// Follow
+	if err := tx.Follow(currentUser.ID, targetUser.ID); err != nil {
+		apierror.Internal(c, "Failed to follow user")
 		return
 	}
-
-	if err := tx.Model(&currentUser).Update("following_count", gorm.Expr("following_count + ?", 1)).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update following count"})
+	if err := h.timeline.Followed(c.Request.Context(), tx.Jobs(h.queue), currentUser.ID, targetUser.ID); err != nil {
+		apierror.Internal(c, "Failed to follow user")
 		return
 	}
 
-	tx.Commit()
+	if err := tx.Commit(); err != nil {
+		apierror.Internal(c, "Failed to follow user")
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message":     "Successfully followed user",
@@ -245,69 +419,328 @@ func FollowUserHandler(c *gin.Context) {
 }
 
 // Kullanıcı arama
-func SearchUsersHandler(c *gin.Context) {
+func (h *Handler) SearchUsersHandler(c *gin.Context) {
 	var query SearchUsersQuery
 	if err := c.ShouldBindQuery(&query); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Validation(c, err)
 		return
 	}
 
-	if query.Limit > 50 {
-		query.Limit = 50
+	params, err := pagination.Parse(c)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid cursor")
+		return
 	}
 
 	user, _ := c.Get("user")
 	currentUser := user.(models.User)
 
-	db := database.DB.Model(&models.User{})
+	users, err := h.storeFor(c).Search(currentUser.ID, query.Query, params)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch users")
+		return
+	}
+	users, page := pagination.Paginate(users, params, searchKey)
+
+	response := make([]RelatedUserResponse, len(users))
+	for i, u := range users {
+		response[i] = u.response()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":      response,
+		"pagination": page,
+	})
+}
 
-	if query.Query != "" {
-		searchQuery := "%" + query.Query + "%"
-		db = db.Where("username ILIKE ? OR full_name ILIKE ?", searchQuery, searchQuery)
+// Engelle/Engeli kaldır işlemi
+func (h *Handler) BlockUserHandler(c *gin.Context) {
+	targetUserID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid user ID")
+		return
 	}
 
-	db = db.Where("id != ?", currentUser.ID)
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
 
-	offset := (query.Page - 1) * query.Limit
+	if currentUser.ID == uint(targetUserID) {
+		apierror.BadRequest(c, "You cannot block yourself")
+		return
+	}
 
-	var total int64
-	db.Count(&total)
+	tx, err := h.storeFor(c).Begin()
+	if err != nil {
+		apierror.Internal(c, "Failed to block user")
+		return
+	}
+	defer tx.Rollback()
 
-	var users []models.User
-	if err := db.Select("id, username, full_name, biography, instagram_username, follower_count, following_count, total_views").
-		Limit(query.Limit).
-		Offset(offset).
-		Order("follower_count DESC").
-		Find(&users).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+	targetUser, err := tx.FindByID(uint(targetUserID))
+	if err != nil {
+		apierror.NotFound(c, "User not found")
 		return
 	}
 
-	type UserResponse struct {
-		models.User
-		IsFollowing bool `json:"isFollowing"`
+	unblocked, err := tx.Unblock(currentUser.ID, targetUser.ID)
+	if err != nil {
+		apierror.Internal(c, "Failed to unblock user")
+		return
+	}
+	if unblocked {
+		if err := tx.Commit(); err != nil {
+			apierror.Internal(c, "Failed to unblock user")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message":   "Successfully unblocked user",
+			"isBlocked": false,
+		})
+		return
 	}
 
-	response := make([]UserResponse, len(users))
-	for i, u := range users {
-		var count int64
-		database.DB.Table("user_followers").
-			Where("follower_id = ? AND following_id = ?", currentUser.ID, u.ID).
-			Count(&count)
-
-		response[i] = UserResponse{
-			User:        u,
-			IsFollowing: count > 0,
+	// Block
+	if err := tx.Block(currentUser.ID, targetUser.ID); err != nil {
+		apierror.Internal(c, "Failed to block user")
+		return
+	}
+	// Engelleme iki yöndeki takibi de kaldırır
+	for _, pair := range [][2]uint{{currentUser.ID, targetUser.ID}, {targetUser.ID, currentUser.ID}} {
+		if err := h.timeline.Unfollowed(c.Request.Context(), tx.Jobs(h.queue), pair[0], pair[1]); err != nil {
+			apierror.Internal(c, "Failed to block user")
+			return
 		}
 	}
 
+	if err := tx.Commit(); err != nil {
+		apierror.Internal(c, "Failed to block user")
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"users": response,
-		"pagination": gin.H{
-			"current": query.Page,
-			"limit":   query.Limit,
-			"total":   total,
-			"pages":   (total + int64(query.Limit) - 1) / int64(query.Limit),
-		},
+		"message":   "Successfully blocked user",
+		"isBlocked": true,
+	})
+}
+
+// Sessize al/Sesini aç işlemi
+func (h *Handler) MuteUserHandler(c *gin.Context) {
+	targetUserID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	if currentUser.ID == uint(targetUserID) {
+		apierror.BadRequest(c, "You cannot mute yourself")
+		return
+	}
+
+	targetUser, err := h.storeFor(c).FindByID(uint(targetUserID))
+	if err != nil {
+		apierror.NotFound(c, "User not found")
+		return
+	}
+
+	unmuted, err := h.storeFor(c).Unmute(currentUser.ID, targetUser.ID)
+	if err != nil {
+		apierror.Internal(c, "Failed to unmute user")
+		return
+	}
+	if unmuted {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Successfully unmuted user",
+			"isMuted": false,
+		})
+		return
+	}
+
+	if err := h.storeFor(c).Mute(currentUser.ID, targetUser.ID); err != nil {
+		apierror.Internal(c, "Failed to mute user")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Successfully muted user",
+		"isMuted": true,
+	})
+}
+
+// Engellenen kullanıcıları listeleme
+func (h *Handler) GetBlockedUsersHandler(c *gin.Context) {
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	users, err := h.storeFor(c).BlockedUsers(currentUser.ID)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch blocked users")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": models.SafeResponses(users)})
+}
+
+// Sessize alınan kullanıcıları listeleme
+func (h *Handler) GetMutedUsersHandler(c *gin.Context) {
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	users, err := h.storeFor(c).MutedUsers(currentUser.ID)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch muted users")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": models.SafeResponses(users)})
+}
+
+// Gelen takip isteklerini listeleme
+func (h *Handler) GetFollowRequestsHandler(c *gin.Context) {
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	requests, err := h.storeFor(c).IncomingFollowRequests(currentUser.ID)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch follow requests")
+		return
+	}
+
+	response := make([]FollowRequestResponse, len(requests))
+	for i, request := range requests {
+		response[i] = FollowRequestResponse{
+			ID:        request.ID,
+			Requester: request.Requester.SafeResponse(),
+			CreatedAt: request.CreatedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"requests": response})
+}
+
+// Takip isteğini onaylama
+func (h *Handler) ApproveFollowRequestHandler(c *gin.Context) {
+	requestID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.NotFound(c, "Follow request not found")
+		return
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	tx, err := h.storeFor(c).Begin()
+	if err != nil {
+		apierror.Internal(c, "Failed to approve follow request")
+		return
+	}
+	defer tx.Rollback()
+
+	request, err := tx.FindIncomingFollowRequest(uint(requestID), currentUser.ID)
+	if err != nil {
+		apierror.NotFound(c, "Follow request not found")
+		return
+	}
+
+	if err := tx.ApproveFollowRequest(*request); err != nil {
+		apierror.Internal(c, "Failed to approve follow request")
+		return
+	}
+	if err := h.timeline.Followed(c.Request.Context(), tx.Jobs(h.queue), request.RequesterID, request.TargetID); err != nil {
+		apierror.Internal(c, "Failed to approve follow request")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		apierror.Internal(c, "Failed to approve follow request")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Follow request approved"})
+}
+
+// Takip isteğini reddetme
+func (h *Handler) RejectFollowRequestHandler(c *gin.Context) {
+	requestID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.NotFound(c, "Follow request not found")
+		return
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	if err := h.storeFor(c).RejectFollowRequest(uint(requestID), currentUser.ID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			apierror.NotFound(c, "Follow request not found")
+			return
+		}
+		apierror.Internal(c, "Failed to reject follow request")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Follow request rejected"})
+}
+
+// Kullanıcının takipçilerini listeleme
+func (h *Handler) GetFollowersHandler(c *gin.Context) {
+	h.listFollows(c, h.storeFor(c).Followers)
+}
+
+// Kullanıcının takip ettiklerini listeleme
+func (h *Handler) GetFollowingHandler(c *gin.Context) {
+	h.listFollows(c, h.storeFor(c).Following)
+}
+
+func (h *Handler) listFollows(c *gin.Context, list func(viewerID, userID uint, params pagination.Params) ([]RelatedUser, error)) {
+	targetUserID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	params, err := pagination.Parse(c)
+	if err != nil {
+		apierror.BadRequest(c, "Invalid cursor")
+		return
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(models.User)
+
+	targetUser, err := h.storeFor(c).FindByID(uint(targetUserID))
+	if err != nil {
+		apierror.NotFound(c, "User not found")
+		return
+	}
+
+	if blocked, err := h.storeFor(c).IsBlockedBetween(currentUser.ID, targetUser.ID); err != nil || blocked {
+		apierror.NotFound(c, "User not found")
+		return
+	}
+
+	// Gizli hesabın listeleri sadece takipçilere açık
+	if canView, err := h.storeFor(c).CanViewPosts(currentUser.ID, *targetUser); err != nil || !canView {
+		apierror.PrivateAccount(c)
+		return
+	}
+
+	users, err := list(currentUser.ID, targetUser.ID, params)
+	if err != nil {
+		apierror.Internal(c, "Failed to fetch users")
+		return
+	}
+	users, page := pagination.Paginate(users, params, followKey)
+
+	response := make([]RelatedUserResponse, len(users))
+	for i, u := range users {
+		response[i] = u.response()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":      response,
+		"pagination": page,
 	})
 }
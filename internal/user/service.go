@@ -0,0 +1,59 @@
+package user
+
+import (
+	"github.com/sefazor/comfyn/internal/models"
+	"gorm.io/gorm"
+)
+
+// İki kullanıcı arasında herhangi bir yönde engelleme olup olmadığını kontrol eder
+func IsBlockedBetween(db *gorm.DB, userID, otherID uint) (bool, error) {
+	var count int64
+	err := db.Table("user_blocks").
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)",
+			userID, otherID, otherID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Kullanıcının engellediği ve onu engelleyen kullanıcıların id'lerini dönen alt sorgu
+func BlockedUserIDs(db *gorm.DB, userID uint) *gorm.DB {
+	return db.Raw(`SELECT blocked_id FROM user_blocks WHERE blocker_id = ?
+		UNION SELECT blocker_id FROM user_blocks WHERE blocked_id = ?`, userID, userID)
+}
+
+// Kullanıcının sessize aldığı kullanıcıların id'lerini dönen alt sorgu
+func MutedUserIDs(db *gorm.DB, userID uint) *gorm.DB {
+	return db.Table("user_mutes").Select("muted_id").Where("muter_id = ?", userID)
+}
+
+// followerID kullanıcısının followingID kullanıcısını takip edip etmediğini döner
+func IsFollowing(db *gorm.DB, followerID, followingID uint) (bool, error) {
+	var count int64
+	err := db.Table("user_followers").
+		Where("follower_id = ? AND following_id = ?", followerID, followingID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Gizli hesapların postlarını takipçi olmayanlardan, moderasyonla gizlenen
+// postları sahibi dışındaki herkesten, devre dışı hesapların postlarını da
+// tamamen gizleyen scope.
+// Sorgunun posts tablosu üzerinde olması gerekir.
+func VisiblePosts(viewerID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(`(posts.user_id = ?
+			OR posts.user_id IN (SELECT id FROM users WHERE is_private = false)
+			OR posts.user_id IN (SELECT following_id FROM user_followers WHERE follower_id = ?))`,
+			viewerID, viewerID).
+			Where("(posts.hidden_at IS NULL OR posts.user_id = ?)", viewerID).
+			Where("posts.user_id NOT IN (SELECT id FROM users WHERE deactivated_at IS NOT NULL)")
+	}
+}
+
+// Kullanıcının owner'ın postlarını görüp göremeyeceğini döner
+func CanViewPosts(db *gorm.DB, viewerID uint, owner models.User) (bool, error) {
+	if !owner.IsPrivate || owner.ID == viewerID {
+		return true, nil
+	}
+	return IsFollowing(db, viewerID, owner.ID)
+}
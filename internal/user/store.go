@@ -0,0 +1,507 @@
+// internal/user/store.go
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/pkg/jobs"
+	"github.com/sefazor/comfyn/pkg/pagination"
+	"gorm.io/gorm"
+)
+
+var ErrNotFound = errors.New("user not found")
+
+// Kullanıcıların ve aralarındaki takip, engelleme ve sessize alma
+// ilişkilerinin saklandığı yer
+type Store interface {
+	// Transaction başlatır; Tx üzerindeki işlemler Commit'e kadar kalıcı olmaz
+	Begin() (Tx, error)
+	// Sorguları ctx'e bağlı çalıştıran kopya
+	WithContext(ctx context.Context) Store
+
+	FindByID(id uint) (*models.User, error)
+	// Devre dışı bırakılmış hesapları bulmaz
+	FindActiveByID(id uint) (*models.User, error)
+	// Kullanıcı adı veya e-posta ile arar
+	FindByLogin(login string) (*models.User, error)
+	UsernameTaken(username string) (bool, error)
+	EmailTaken(email string) (bool, error)
+	Create(user *models.User) error
+	Save(user *models.User) error
+	// Devre dışı hesabı ve bekleyen silme talebini geri alır
+	Reactivate(userID uint) error
+
+	Relation(viewerID, targetID uint) (*Relation, error)
+	// viewerID'nin takip ettiği ve targetID'yi takip eden kullanıcılar (ilk limit kadarı ve toplam sayı)
+	MutualFollowers(viewerID, targetID uint, limit int) ([]models.User, int64, error)
+	IsBlockedBetween(userID, otherID uint) (bool, error)
+	IsFollowing(followerID, followingID uint) (bool, error)
+	CanViewPosts(viewerID uint, owner models.User) (bool, error)
+
+	// Takip ilişkisini oluşturur veya kaldırır ve sayaçları günceller
+	Follow(followerID, followingID uint) error
+	Unfollow(followerID, followingID uint) error
+
+	FindFollowRequest(requesterID, targetID uint) (*models.FollowRequest, error)
+	CreateFollowRequest(request *models.FollowRequest) error
+	// İsteği ve ilgili bildirimi siler
+	CancelFollowRequest(request *models.FollowRequest) error
+	FindIncomingFollowRequest(id, targetID uint) (*models.FollowRequest, error)
+	IncomingFollowRequests(targetID uint) ([]models.FollowRequest, error)
+	// İsteği siler ve takip ilişkisine çevirir
+	ApproveFollowRequest(request models.FollowRequest) error
+	RejectFollowRequest(id, targetID uint) error
+
+	// Engeller ve iki yöndeki takip ilişkilerini ve istekleri kaldırır
+	Block(blockerID, blockedID uint) error
+	// Engel yoksa false döner
+	Unblock(blockerID, blockedID uint) (bool, error)
+	Mute(muterID, mutedID uint) error
+	// Sessize alma yoksa false döner
+	Unmute(muterID, mutedID uint) (bool, error)
+	BlockedUsers(userID uint) ([]models.User, error)
+	MutedUsers(userID uint) ([]models.User, error)
+
+	// Arama kayıt tarihine, takip listeleri takip zamanına göre yeniden
+	// eskiye sıralanır ve sayfa boyutundan bir fazla kayıt döner
+	Search(viewerID uint, query string, params pagination.Params) ([]RelatedUser, error)
+	Followers(viewerID, userID uint, params pagination.Params) ([]RelatedUser, error)
+	Following(viewerID, userID uint, params pagination.Params) ([]RelatedUser, error)
+}
+
+type Tx interface {
+	Store
+	// İşleri bu transaction'la birlikte kaydeden kuyruk
+	Jobs(queue *jobs.Queue) *jobs.Queue
+	Commit() error
+	Rollback() error
+}
+
+// İzleyicinin başka bir kullanıcıyla ilişkisi
+type Relation struct {
+	BlockedBy   bool // kullanıcı izleyiciyi engellemiş
+	IsFollowing bool
+	IsBlocked   bool
+	IsMuted     bool
+	IsRequested bool
+}
+
+// Takip durumu bilgisiyle birlikte kullanıcı satırı
+type RelatedUser struct {
+	models.User `gorm:"embedded"`
+	IsFollowing bool
+	FollowsYou  bool
+
+	// Sadece takip listelerinde dolu; sayfalama anahtarı
+	FollowedAt time.Time
+	FollowID   uint
+}
+
+type RelatedUserResponse struct {
+	models.UserResponse
+	IsFollowing bool `json:"isFollowing"`
+	FollowsYou  bool `json:"followsYou"`
+}
+
+func (u RelatedUser) response() RelatedUserResponse {
+	return RelatedUserResponse{
+		UserResponse: u.User.SafeResponse(),
+		IsFollowing:  u.IsFollowing,
+		FollowsYou:   u.FollowsYou,
+	}
+}
+
+// Sayfalama anahtarları değişmeyen kolonlardır; takipçi sayısı gibi
+// sayaçlara göre sıralama sayfalar arasında kayıt atlatır
+var (
+	searchOrder = pagination.Order{Column: "users.created_at", IDColumn: "users.id"}
+	followOrder = pagination.Order{Column: "uf.created_at", IDColumn: "uf.id"}
+)
+
+func searchKey(u RelatedUser) pagination.Cursor {
+	return pagination.TimeKey(u.CreatedAt, u.ID)
+}
+
+func followKey(u RelatedUser) pagination.Cursor {
+	return pagination.TimeKey(u.FollowedAt, u.FollowID)
+}
+
+// Listelenen kullanıcı ile mevcut kullanıcı arasındaki takip durumunu
+// aynı sorguda hesaplayan kolonlar (iki parametre: viewerID, viewerID)
+const relationColumns = `
+	EXISTS (SELECT 1 FROM user_followers f WHERE f.follower_id = ? AND f.following_id = users.id) AS is_following,
+	EXISTS (SELECT 1 FROM user_followers f WHERE f.follower_id = users.id AND f.following_id = ?) AS follows_you`
+
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) WithContext(ctx context.Context) Store {
+	return &PostgresStore{db: s.db.WithContext(ctx)}
+}
+
+type postgresTx struct {
+	PostgresStore
+}
+
+func (s *PostgresStore) Begin() (Tx, error) {
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return &postgresTx{PostgresStore{db: tx}}, nil
+}
+
+func (t *postgresTx) Jobs(queue *jobs.Queue) *jobs.Queue {
+	return queue.WithTx(t.db)
+}
+
+func (t *postgresTx) Commit() error {
+	return t.db.Commit().Error
+}
+
+func (t *postgresTx) Rollback() error {
+	return t.db.Rollback().Error
+}
+
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (s *PostgresStore) FindByID(id uint) (*models.User, error) {
+	var user models.User
+	if err := s.db.First(&user, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (s *PostgresStore) FindActiveByID(id uint) (*models.User, error) {
+	var user models.User
+	if err := s.db.Where("deactivated_at IS NULL").First(&user, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (s *PostgresStore) FindByLogin(login string) (*models.User, error) {
+	var user models.User
+	if err := s.db.Where("username = ? OR email = ?", login, login).First(&user).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (s *PostgresStore) exists(query string, args ...interface{}) (bool, error) {
+	var count int64
+	err := s.db.Model(&models.User{}).Where(query, args...).Count(&count).Error
+	return count > 0, err
+}
+
+func (s *PostgresStore) UsernameTaken(username string) (bool, error) {
+	return s.exists("username = ?", username)
+}
+
+func (s *PostgresStore) EmailTaken(email string) (bool, error) {
+	return s.exists("email = ?", email)
+}
+
+func (s *PostgresStore) Create(user *models.User) error {
+	return s.db.Create(user).Error
+}
+
+func (s *PostgresStore) Save(user *models.User) error {
+	return s.db.Save(user).Error
+}
+
+func (s *PostgresStore) Reactivate(userID uint) error {
+	return s.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"deactivated_at":        nil,
+		"deletion_scheduled_at": nil,
+	}).Error
+}
+
+func (s *PostgresStore) Relation(viewerID, targetID uint) (*Relation, error) {
+	relation := &Relation{}
+	counts := []struct {
+		query  *gorm.DB
+		target *bool
+	}{
+		{s.db.Model(&models.UserBlock{}).Where("blocker_id = ? AND blocked_id = ?", targetID, viewerID), &relation.BlockedBy},
+		{s.db.Table("user_followers").Where("follower_id = ? AND following_id = ?", viewerID, targetID), &relation.IsFollowing},
+		{s.db.Model(&models.UserBlock{}).Where("blocker_id = ? AND blocked_id = ?", viewerID, targetID), &relation.IsBlocked},
+		{s.db.Model(&models.UserMute{}).Where("muter_id = ? AND muted_id = ?", viewerID, targetID), &relation.IsMuted},
+		{s.db.Model(&models.FollowRequest{}).Where("requester_id = ? AND target_id = ?", viewerID, targetID), &relation.IsRequested},
+	}
+	for _, c := range counts {
+		var count int64
+		if err := c.query.Count(&count).Error; err != nil {
+			return nil, err
+		}
+		*c.target = count > 0
+	}
+	return relation, nil
+}
+
+func (s *PostgresStore) MutualFollowers(viewerID, targetID uint, limit int) ([]models.User, int64, error) {
+	db := s.db.Model(&models.User{}).
+		Joins("JOIN user_followers a ON a.follower_id = users.id AND a.following_id = ?", targetID).
+		Joins("JOIN user_followers b ON b.following_id = users.id AND b.follower_id = ?", viewerID)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	if err := db.Order("users.follower_count DESC").Limit(limit).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (s *PostgresStore) IsBlockedBetween(userID, otherID uint) (bool, error) {
+	return IsBlockedBetween(s.db, userID, otherID)
+}
+
+func (s *PostgresStore) IsFollowing(followerID, followingID uint) (bool, error) {
+	return IsFollowing(s.db, followerID, followingID)
+}
+
+func (s *PostgresStore) CanViewPosts(viewerID uint, owner models.User) (bool, error) {
+	return CanViewPosts(s.db, viewerID, owner)
+}
+
+func (s *PostgresStore) Follow(followerID, followingID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return addFollowEdge(tx, followerID, followingID)
+	})
+}
+
+func (s *PostgresStore) Unfollow(followerID, followingID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM user_followers WHERE follower_id = ? AND following_id = ?",
+			followerID, followingID).Error; err != nil {
+			return err
+		}
+
+		// Sayaçları güncelle
+		if err := tx.Table("users").Where("id = ?", followingID).
+			Update("follower_count", gorm.Expr("follower_count - ?", 1)).Error; err != nil {
+			return err
+		}
+
+		return tx.Table("users").Where("id = ?", followerID).
+			Update("following_count", gorm.Expr("following_count - ?", 1)).Error
+	})
+}
+
+func (s *PostgresStore) FindFollowRequest(requesterID, targetID uint) (*models.FollowRequest, error) {
+	var request models.FollowRequest
+	if err := s.db.Where("requester_id = ? AND target_id = ?", requesterID, targetID).
+		First(&request).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &request, nil
+}
+
+func (s *PostgresStore) CreateFollowRequest(request *models.FollowRequest) error {
+	return s.db.Create(request).Error
+}
+
+func (s *PostgresStore) CancelFollowRequest(request *models.FollowRequest) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(request).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("actor_id = ? AND user_id = ? AND type = ?",
+			request.RequesterID, request.TargetID, models.NotificationFollowRequest).
+			Delete(&models.Notification{}).Error
+	})
+}
+
+func (s *PostgresStore) FindIncomingFollowRequest(id, targetID uint) (*models.FollowRequest, error) {
+	var request models.FollowRequest
+	if err := s.db.Where("id = ? AND target_id = ?", id, targetID).First(&request).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &request, nil
+}
+
+func (s *PostgresStore) IncomingFollowRequests(targetID uint) ([]models.FollowRequest, error) {
+	var requests []models.FollowRequest
+	err := s.db.Where("target_id = ?", targetID).
+		Preload("Requester").
+		Order("created_at DESC").
+		Find(&requests).Error
+	return requests, err
+}
+
+func (s *PostgresStore) ApproveFollowRequest(request models.FollowRequest) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&request).Error; err != nil {
+			return err
+		}
+
+		following, err := IsFollowing(tx, request.RequesterID, request.TargetID)
+		if err != nil || following {
+			return err
+		}
+
+		return addFollowEdge(tx, request.RequesterID, request.TargetID)
+	})
+}
+
+func (s *PostgresStore) RejectFollowRequest(id, targetID uint) error {
+	result := s.db.Where("id = ? AND target_id = ?", id, targetID).Delete(&models.FollowRequest{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *PostgresStore) Block(blockerID, blockedID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&models.UserBlock{BlockerID: blockerID, BlockedID: blockedID}).Error; err != nil {
+			return err
+		}
+
+		// Mevcut takip ilişkilerini kaldır
+		return removeFollowEdges(tx, blockerID, blockedID)
+	})
+}
+
+func (s *PostgresStore) Unblock(blockerID, blockedID uint) (bool, error) {
+	result := s.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&models.UserBlock{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (s *PostgresStore) Mute(muterID, mutedID uint) error {
+	return s.db.Create(&models.UserMute{MuterID: muterID, MutedID: mutedID}).Error
+}
+
+func (s *PostgresStore) Unmute(muterID, mutedID uint) (bool, error) {
+	result := s.db.Where("muter_id = ? AND muted_id = ?", muterID, mutedID).Delete(&models.UserMute{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (s *PostgresStore) BlockedUsers(userID uint) ([]models.User, error) {
+	var users []models.User
+	err := s.db.
+		Joins("JOIN user_blocks ub ON ub.blocked_id = users.id").
+		Where("ub.blocker_id = ?", userID).
+		Order("ub.created_at DESC").
+		Find(&users).Error
+	return users, err
+}
+
+func (s *PostgresStore) MutedUsers(userID uint) ([]models.User, error) {
+	var users []models.User
+	err := s.db.
+		Joins("JOIN user_mutes um ON um.muted_id = users.id").
+		Where("um.muter_id = ?", userID).
+		Order("um.created_at DESC").
+		Find(&users).Error
+	return users, err
+}
+
+func (s *PostgresStore) Search(viewerID uint, query string, params pagination.Params) ([]RelatedUser, error) {
+	db := s.db.Model(&models.User{})
+
+	if query != "" {
+		searchQuery := "%" + query + "%"
+		db = db.Where("username ILIKE ? OR full_name ILIKE ?", searchQuery, searchQuery)
+	}
+
+	db = db.Where("id != ? AND deactivated_at IS NULL", viewerID).
+		Where("id NOT IN (?)", BlockedUserIDs(s.db, viewerID))
+
+	// Takip durumu her satır için ayrı sorgu yerine aynı sorguda hesaplanır
+	var users []RelatedUser
+	err := params.Apply(db.Select("users.*,"+relationColumns,
+		viewerID, viewerID), searchOrder).
+		Scan(&users).Error
+	return users, err
+}
+
+func (s *PostgresStore) Followers(viewerID, userID uint, params pagination.Params) ([]RelatedUser, error) {
+	return s.listFollows(viewerID, params, "uf.follower_id = users.id", "uf.following_id = ?", userID)
+}
+
+func (s *PostgresStore) Following(viewerID, userID uint, params pagination.Params) ([]RelatedUser, error) {
+	return s.listFollows(viewerID, params, "uf.following_id = users.id", "uf.follower_id = ?", userID)
+}
+
+func (s *PostgresStore) listFollows(viewerID uint, params pagination.Params, joinCondition, targetCondition string, userID uint) ([]RelatedUser, error) {
+	db := s.db.Model(&models.User{}).
+		Joins("JOIN user_followers uf ON "+joinCondition).
+		Where(targetCondition, userID).
+		Where("users.id NOT IN (?)", BlockedUserIDs(s.db, viewerID)).
+		Where("users.deactivated_at IS NULL")
+
+	var users []RelatedUser
+	err := params.Apply(db.Select("users.*, uf.created_at AS followed_at, uf.id AS follow_id,"+relationColumns,
+		viewerID, viewerID), followOrder).
+		Scan(&users).Error
+	return users, err
+}
+
+// Takip ilişkisini oluşturur ve sayaçları günceller
+func addFollowEdge(tx *gorm.DB, followerID, followingID uint) error {
+	if err := tx.Exec("INSERT INTO user_followers (follower_id, following_id) VALUES (?, ?)",
+		followerID, followingID).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Table("users").Where("id = ?", followingID).
+		Update("follower_count", gorm.Expr("follower_count + ?", 1)).Error; err != nil {
+		return err
+	}
+
+	return tx.Table("users").Where("id = ?", followerID).
+		Update("following_count", gorm.Expr("following_count + ?", 1)).Error
+}
+
+// Engellemede iki yöndeki takip ilişkisini kaldırır ve sayaçları düzeltir
+func removeFollowEdges(tx *gorm.DB, userID, otherID uint) error {
+	pairs := [][2]uint{{userID, otherID}, {otherID, userID}}
+	for _, pair := range pairs {
+		follower, following := pair[0], pair[1]
+
+		result := tx.Exec("DELETE FROM user_followers WHERE follower_id = ? AND following_id = ?", follower, following)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		if err := tx.Table("users").Where("id = ?", following).
+			Update("follower_count", gorm.Expr("GREATEST(follower_count - ?, 0)", 1)).Error; err != nil {
+			return err
+		}
+		if err := tx.Table("users").Where("id = ?", follower).
+			Update("following_count", gorm.Expr("GREATEST(following_count - ?, 0)", 1)).Error; err != nil {
+			return err
+		}
+	}
+
+	// Bekleyen takip isteklerini de kaldır
+	return tx.Where("(requester_id = ? AND target_id = ?) OR (requester_id = ? AND target_id = ?)",
+		userID, otherID, otherID, userID).
+		Delete(&models.FollowRequest{}).Error
+}
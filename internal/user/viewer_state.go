@@ -0,0 +1,81 @@
+// internal/user/viewer_state.go
+package user
+
+import (
+	"github.com/sefazor/comfyn/internal/models"
+	"gorm.io/gorm"
+)
+
+// Bir sayfadaki postlar için izleyiciye özel durumlar
+type ViewerState struct {
+	liked     map[uint]bool
+	saved     map[uint]bool
+	following map[uint]bool
+}
+
+// Beğeni, kaydetme ve takip durumlarını sayfa başına üç sorguda yükler
+func LoadViewerState(db *gorm.DB, viewerID uint, posts []models.Post) (*ViewerState, error) {
+	state := &ViewerState{
+		liked:     make(map[uint]bool),
+		saved:     make(map[uint]bool),
+		following: make(map[uint]bool),
+	}
+	if len(posts) == 0 {
+		return state, nil
+	}
+
+	postIDs := make([]uint, len(posts))
+	authorIDs := make([]uint, 0, len(posts))
+	seenAuthors := make(map[uint]bool)
+	for i, post := range posts {
+		postIDs[i] = post.ID
+		if !seenAuthors[post.UserID] {
+			seenAuthors[post.UserID] = true
+			authorIDs = append(authorIDs, post.UserID)
+		}
+	}
+
+	lookups := []struct {
+		query  *gorm.DB
+		column string
+		target map[uint]bool
+	}{
+		{db.Table("likes").Where("user_id = ? AND post_id IN ?", viewerID, postIDs), "post_id", state.liked},
+		{db.Table("saved_posts").Where("user_id = ? AND post_id IN ?", viewerID, postIDs), "post_id", state.saved},
+		{db.Table("user_followers").Where("follower_id = ? AND following_id IN ?", viewerID, authorIDs), "following_id", state.following},
+	}
+	for _, l := range lookups {
+		var ids []uint
+		if err := l.query.Pluck(l.column, &ids).Error; err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			l.target[id] = true
+		}
+	}
+
+	return state, nil
+}
+
+// Post yanıtına izleyici durumlarını ekler
+func (s *ViewerState) Apply(response models.PostResponse, post *models.Post) models.PostResponse {
+	liked, saved, following := s.liked[post.ID], s.saved[post.ID], s.following[post.UserID]
+	response.LikedByMe = &liked
+	response.SavedByMe = &saved
+	response.FollowingAuthor = &following
+	return response
+}
+
+// Postları izleyici durumlarıyla birlikte yanıta çevirir
+func PostResponses(db *gorm.DB, viewerID uint, posts []models.Post) ([]models.PostResponse, error) {
+	state, err := LoadViewerState(db, viewerID, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]models.PostResponse, len(posts))
+	for i := range posts {
+		response[i] = state.Apply(posts[i].Response(), &posts[i])
+	}
+	return response, nil
+}
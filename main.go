@@ -1,70 +1,240 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/sefazor/comfyn/configs"
+	"github.com/sefazor/comfyn/internal/account"
 	"github.com/sefazor/comfyn/internal/affiliate/link"
 	"github.com/sefazor/comfyn/internal/auth"
+	"github.com/sefazor/comfyn/internal/digest"
+	"github.com/sefazor/comfyn/internal/export"
+	"github.com/sefazor/comfyn/internal/health"
+	"github.com/sefazor/comfyn/internal/moderation"
 	"github.com/sefazor/comfyn/internal/notification"
 	"github.com/sefazor/comfyn/internal/post"
-	"github.com/sefazor/comfyn/internal/user"
+	"github.com/sefazor/comfyn/internal/product"
+	"github.com/sefazor/comfyn/internal/push"
+	"github.com/sefazor/comfyn/internal/search"
+	"github.com/sefazor/comfyn/internal/timeline"
+	usersvc "github.com/sefazor/comfyn/internal/user"
+	"github.com/sefazor/comfyn/pkg/background"
 	"github.com/sefazor/comfyn/pkg/database"
-	"github.com/sefazor/comfyn/pkg/middleware"
+	"github.com/sefazor/comfyn/pkg/jobs"
+	"github.com/sefazor/comfyn/pkg/jwt"
+	"github.com/sefazor/comfyn/pkg/logging"
+	"github.com/sefazor/comfyn/pkg/mail"
+	"github.com/sefazor/comfyn/pkg/metrics"
+	"github.com/sefazor/comfyn/pkg/tracing"
 )
 
 func main() {
-	configs.LoadEnv()
-	database.InitDB()
-
-	r := gin.Default()
-
-	// Public routes
-	r.POST("/api/auth/register", auth.RegisterHandler)
-	r.POST("/api/auth/login", auth.LoginHandler)
-	r.GET("/go/:tracking_id", link.RedirectHandler)
-
-	// Protected routes
-	protected := r.Group("/api")
-	protected.Use(middleware.AuthMiddleware())
-	{
-		// User routes
-		protected.GET("/users/me", user.GetProfileHandler)
-		protected.GET("/users/:id", user.GetUserProfileHandler)
-		protected.PUT("/users/profile", user.UpdateProfileHandler)
-		protected.PUT("/users/security", user.UpdateSecurityHandler)
-		protected.POST("/users/:id/follow", user.FollowUserHandler)
-		protected.GET("/users/search", user.SearchUsersHandler)
-
-		// Post routes
-		protected.POST("/posts", post.CreatePostHandler)
-		protected.GET("/posts", post.ListPostsHandler)
-		protected.GET("/posts/:id", post.GetPostHandler)
-		protected.PUT("/posts/:id", post.UpdatePostHandler)
-		protected.DELETE("/posts/:id", post.DeletePostHandler)
-		protected.POST("/posts/:id/like", post.LikePostHandler)
-		protected.POST("/posts/:id/comment", post.CreateCommentHandler)
-		protected.POST("/posts/:id/view", post.IncrementViewHandler)
-
-		// Feed routes
-		protected.GET("/feed", post.GetPersonalFeedHandler)
-		protected.GET("/feed/suggested", post.GetSuggestedPostsHandler)
-
-		// Hashtag routes
-		protected.GET("/posts/hashtag/:tag", post.SearchPostsByHashtagHandler)
-		protected.GET("/hashtags/trending", post.GetTrendingHashtagsHandler)
-
-		// Notification routes
-		protected.GET("/notifications", notification.GetNotificationsHandler)
-		protected.PUT("/notifications/:id/read", notification.MarkNotificationReadHandler)
-		protected.PUT("/notifications/preferences", notification.UpdateNotificationPreferencesHandler)
-		protected.GET("/analytics/links", link.GetLinkAnalyticsHandler)
-
-		protected.GET("/analytics/clicks", post.GetClickStatsHandler)
-
-	}
-
-	log.Printf("Server starting on :8080")
-	r.Run(":8080")
+	// Flag'lerden önce gelen argümanlar alt komut ve parametreleridir
+	// (örn. "migrate down 2 -config prod.env")
+	args := os.Args[1:]
+	var positional []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		positional, args = append(positional, args[0]), args[1:]
+	}
+	command := ""
+	if len(positional) > 0 {
+		command, positional = positional[0], positional[1:]
+	}
+	if command == "help" {
+		fmt.Println(usage)
+		return
+	}
+	if command != "" && !commands[command] {
+		log.Fatalf("Unknown command %q\n\n%s", command, usage)
+	}
+	// Belge yalnızca koddan üretilir; yapılandırma ve veritabanı gerekmez
+	if command == "openapi" {
+		openapiCommand(positional)
+		return
+	}
+
+	cfg, err := configs.Load(args)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if err := logging.Setup(cfg.Log); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Bekleyen span'ler çıkışta gönderilir
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("Failed to flush traces: %v", err)
+		}
+	}()
+
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if command == "migrate" {
+		migrate(db, positional)
+		return
+	}
+
+	// Şeması güncel olmayan veritabanıyla çalışmayı reddet
+	if err := database.CheckMigrations(db); err != nil {
+		log.Fatal(err)
+	}
+
+	jwt.Init(cfg.JWT.Secret)
+
+	// Yan etkiler (bildirim, push, tıklama logu, akış yazımı) kuyruk üzerinden işlenir
+	queue := jobs.NewQueue(db)
+	timelines := timeline.NewService(timeline.NewPostgresStore(db), db, cfg.Timeline.FanOutMaxFollowers)
+
+	mail.Init(cfg.Mail)
+
+	pushStore := push.NewPostgresStore(db)
+	pushes := push.NewService(pushStore, cfg.Push)
+
+	userStore := usersvc.NewPostgresStore(db)
+	notifications := notification.NewService(notification.NewPostgresStore(db), queue, pushes.DeliverAsync)
+	links := link.NewService(link.NewPostgresStore(db), queue)
+
+	accounts := account.NewService(account.NewPostgresStore(db), cfg.Account, timelines)
+	moderationStore := moderation.NewPostgresStore(db)
+	moderations := moderation.NewService(moderationStore, cfg.Moderation, timelines, queue)
+	digestStore := digest.NewPostgresStore(db)
+	digests := digest.NewService(digestStore, cfg.Digest, cfg.App.BaseURL)
+	exportStore := export.NewPostgresStore(db)
+	exports := export.NewService(exportStore, queue, notifications, cfg.Export, cfg.App.BaseURL)
+
+	worker := jobs.NewWorker(db, jobs.WorkerConfig{
+		Concurrency:  cfg.Jobs.Concurrency,
+		PollInterval: cfg.Jobs.PollInterval,
+		JobTimeout:   cfg.Jobs.JobTimeout,
+	})
+	timelines.RegisterJobs(worker)
+	notifications.RegisterJobs(worker)
+	links.RegisterJobs(worker)
+	pushes.RegisterJobs(worker)
+	exports.RegisterJobs(worker)
+	accounts.RegisterJobs(worker)
+	digests.RegisterJobs(worker)
+
+	if command != "" {
+		runCommand(command, positional, db, timelines, notifications, queue, worker)
+		return
+	}
+
+	authHandler := auth.NewHandler(auth.NewService(userStore))
+	userHandler := usersvc.NewHandler(userStore, notifications, timelines, queue)
+	postStore := post.NewPostgresStore(db)
+	postHandler := post.NewHandler(post.NewService(postStore, notifications, timelines, queue), postStore, timelines)
+	notificationHandler := notification.NewHandler(notification.NewPostgresStore(db))
+	linkHandler := link.NewHandler(links)
+	productHandler := product.NewHandler(product.NewPostgresStore(db))
+	searchHandler := search.NewHandler(search.NewPostgresStore(db))
+	pushHandler := push.NewHandler(pushStore)
+	accountHandler := account.NewHandler(accounts)
+	exportHandler := export.NewHandler(exports, exportStore)
+	digestHandler := digest.NewHandler(digestStore)
+	moderationHandler := moderation.NewHandler(moderations, moderationStore)
+	healthHandler := health.NewHandler(db)
+
+	// Sunucu içinde çalışan worker; kapanışta süren işleri bitirir
+	workers := background.New()
+	if cfg.Jobs.RunInServer {
+		workers.Go(func() {
+			if err := worker.Run(workers.Context()); err != nil {
+				log.Printf("Job worker failed: %v", err)
+			}
+		})
+	}
+
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.RegisterDB(sqlDB)
+	}
+
+	r, err := newRouter(handlers{
+		users:        userStore,
+		auth:         authHandler,
+		user:         userHandler,
+		post:         postHandler,
+		notification: notificationHandler,
+		link:         linkHandler,
+		product:      productHandler,
+		search:       searchHandler,
+		push:         pushHandler,
+		account:      accountHandler,
+		export:       exportHandler,
+		digest:       digestHandler,
+		moderation:   moderationHandler,
+		health:       healthHandler,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	serve(cfg.HTTP, r, healthHandler, workers)
+
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
+
+// Sunucuyu başlatır; SIGINT/SIGTERM gelince yeni bağlantıları reddeder,
+// süren istekleri ve arka plan işlerini ShutdownTimeout kadar bekler
+func serve(cfg configs.HTTPConfig, handler http.Handler, healthHandler *health.Handler, workers *background.Group) {
+	srv := &http.Server{
+		Addr:         cfg.Addr,
+		Handler:      handler,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Server starting on %s", cfg.Addr)
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		log.Fatal(err)
+	case <-ctx.Done():
+	}
+	// İkinci sinyal süreci beklemeden sonlandırır
+	stop()
+
+	log.Printf("Shutting down, waiting up to %s for in-flight work", cfg.ShutdownTimeout)
+	healthHandler.SetDraining()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP server did not drain cleanly: %v", err)
+	}
+	// İstekler bittikten sonra gömülü worker'ı durdur; süren işler tamamlanır,
+	// yetişmeyenler kuyrukta kalır ve başka worker tarafından tekrar denenir
+	if err := workers.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Background work did not finish before timeout: %v", err)
+	}
+	log.Printf("Server stopped")
 }
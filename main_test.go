@@ -0,0 +1,20 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sefazor/comfyn/pkg/openapi"
+)
+
+// Kayıtlı her route OpenAPI belgesinde olmalı, belgede de fazladan route olmamalı
+func TestRoutesAreDocumented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r, err := newRouter(handlers{})
+	if err != nil {
+		t.Fatalf("newRouter: %v", err)
+	}
+	if err := openapi.CheckRoutes(apiRoutes, r.Routes()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sefazor/comfyn/internal/account"
+	"github.com/sefazor/comfyn/internal/affiliate/link"
+	"github.com/sefazor/comfyn/internal/auth"
+	"github.com/sefazor/comfyn/internal/export"
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/internal/moderation"
+	"github.com/sefazor/comfyn/internal/notification"
+	"github.com/sefazor/comfyn/internal/post"
+	"github.com/sefazor/comfyn/internal/product"
+	"github.com/sefazor/comfyn/internal/push"
+	"github.com/sefazor/comfyn/internal/search"
+	usersvc "github.com/sefazor/comfyn/internal/user"
+	"github.com/sefazor/comfyn/pkg/openapi"
+	"github.com/sefazor/comfyn/pkg/pagination"
+)
+
+var apiInfo = openapi.Info{
+	Title:   "Comfyn API",
+	Version: "1.0.0",
+	Description: "Hatalar application/problem+json olarak döner; hata türü code alanındadır. " +
+		"Listeler ?limit= ve ?cursor= ile sayfalanır, sonraki sayfa pagination.nextCursor ile istenir.",
+}
+
+var message = openapi.Fields{"message": ""}
+
+// newRouter'daki her route burada belgelenir; "comfyn openapi check"
+// eksik ya da fazla route olduğunda hata verir
+var apiRoutes = []openapi.Route{
+	// Health ve metrik
+	{Method: http.MethodGet, Path: "/healthz", Tag: "health", Summary: "Liveness probe", Public: true,
+		Response: openapi.Fields{"status": "", "database": ""}},
+	{Method: http.MethodGet, Path: "/readyz", Tag: "health", Summary: "Readiness probe; 503 while draining", Public: true,
+		Response: openapi.Fields{"status": "", "database?": ""}},
+	{Method: http.MethodGet, Path: "/metrics", Tag: "health", Summary: "Prometheus metrics", Public: true,
+		Produces: "text/plain"},
+
+	// API belgesi
+	{Method: http.MethodGet, Path: "/openapi.json", Tag: "docs", Summary: "This document", Public: true,
+		Response: map[string]interface{}{}},
+	{Method: http.MethodGet, Path: "/docs", Tag: "docs", Summary: "Swagger UI", Public: true,
+		Produces: "text/html"},
+
+	// Auth
+	{Method: http.MethodPost, Path: "/api/auth/register", Tag: "auth", Summary: "Create an account", Public: true,
+		Body: auth.RegisterInput{}, Status: http.StatusCreated, Response: auth.AuthResponse{}},
+	{Method: http.MethodPost, Path: "/api/auth/login", Tag: "auth", Summary: "Log in; reactivates a deactivated account", Public: true,
+		Body: auth.LoginInput{}, Response: auth.AuthResponse{}},
+
+	// Public linkler
+	{Method: http.MethodGet, Path: "/go/:tracking_id", Tag: "links", Summary: "Redirect an affiliate tracking link", Public: true,
+		Status: http.StatusTemporaryRedirect},
+	{Method: http.MethodGet, Path: "/api/email/unsubscribe", Tag: "email", Summary: "Unsubscribe from an email link", Public: true,
+		Query: unsubscribeQuery{}, Response: message},
+	{Method: http.MethodPost, Path: "/api/email/unsubscribe", Tag: "email", Summary: "One-click unsubscribe (RFC 8058)", Public: true,
+		Query: unsubscribeQuery{}, Response: message},
+	{Method: http.MethodGet, Path: "/api/exports/:id/download", Tag: "account", Summary: "Download a data export with a signed link", Public: true,
+		Query: downloadQuery{}, Produces: "application/zip"},
+
+	// Kullanıcı
+	{Method: http.MethodGet, Path: "/api/users/me", Tag: "users", Summary: "Current user",
+		Response: openapi.Fields{"user": models.UserResponse{}}},
+	{Method: http.MethodPost, Path: "/api/users/me/deactivate", Tag: "account", Summary: "Deactivate the account until next login",
+		Body: account.ConfirmPasswordInput{}, Response: message},
+	{Method: http.MethodDelete, Path: "/api/users/me", Tag: "account", Summary: "Schedule account deletion",
+		Body: account.ConfirmPasswordInput{}, Response: openapi.Fields{"message": "", "deleteAt": time.Time{}}},
+	{Method: http.MethodGet, Path: "/api/users/me/saved", Tag: "posts", Summary: "Saved posts", Cursor: true,
+		Response: postPage},
+	{Method: http.MethodPost, Path: "/api/users/me/export", Tag: "account", Summary: "Request a personal data export",
+		Status: http.StatusAccepted, Response: openapi.Fields{"message": "", "export": export.ExportResponse{}}},
+	{Method: http.MethodGet, Path: "/api/users/me/exports", Tag: "account", Summary: "Recent data exports",
+		Response: openapi.Fields{"exports": []export.ExportResponse{}}},
+	{Method: http.MethodGet, Path: "/api/users/:id", Tag: "users", Summary: "User profile",
+		Response: openapi.Fields{"user": usersvc.ProfileResponse{}}},
+	{Method: http.MethodPut, Path: "/api/users/profile", Tag: "users", Summary: "Update profile",
+		Body: usersvc.UpdateProfileInput{}, Response: openapi.Fields{"user": models.UserResponse{}}},
+	{Method: http.MethodPut, Path: "/api/users/security", Tag: "users", Summary: "Change password or email",
+		Body: usersvc.UpdateSecurityInput{}, Response: openapi.Fields{"message": "", "user": models.UserResponse{}}},
+	{Method: http.MethodPost, Path: "/api/users/:id/follow", Tag: "users", Summary: "Toggle follow; private accounts get a follow request",
+		Response: openapi.Fields{"message": "", "isFollowing": false, "isRequested?": false}},
+	{Method: http.MethodGet, Path: "/api/users/:id/followers", Tag: "users", Summary: "Followers", Cursor: true,
+		Response: userPage},
+	{Method: http.MethodGet, Path: "/api/users/:id/following", Tag: "users", Summary: "Followed users", Cursor: true,
+		Response: userPage},
+	{Method: http.MethodGet, Path: "/api/users/search", Tag: "users", Summary: "Search users", Query: usersvc.SearchUsersQuery{}, Cursor: true,
+		Response: userPage},
+	{Method: http.MethodPost, Path: "/api/users/:id/block", Tag: "users", Summary: "Toggle block",
+		Response: openapi.Fields{"message": "", "isBlocked": false}},
+	{Method: http.MethodPost, Path: "/api/users/:id/mute", Tag: "users", Summary: "Toggle mute",
+		Response: openapi.Fields{"message": "", "isMuted": false}},
+	{Method: http.MethodGet, Path: "/api/users/blocked", Tag: "users", Summary: "Blocked users",
+		Response: openapi.Fields{"users": []models.UserResponse{}}},
+	{Method: http.MethodGet, Path: "/api/users/muted", Tag: "users", Summary: "Muted users",
+		Response: openapi.Fields{"users": []models.UserResponse{}}},
+
+	// Takip istekleri
+	{Method: http.MethodGet, Path: "/api/follow-requests", Tag: "users", Summary: "Incoming follow requests",
+		Response: openapi.Fields{"requests": []usersvc.FollowRequestResponse{}}},
+	{Method: http.MethodPost, Path: "/api/follow-requests/:id/approve", Tag: "users", Summary: "Approve a follow request",
+		Response: message},
+	{Method: http.MethodPost, Path: "/api/follow-requests/:id/reject", Tag: "users", Summary: "Reject a follow request",
+		Response: message},
+
+	// Post
+	{Method: http.MethodPost, Path: "/api/posts", Tag: "posts", Summary: "Create a post",
+		Body: post.CreatePostInput{}, Status: http.StatusCreated, Response: openapi.Fields{"message": "", "post": models.PostResponse{}}},
+	{Method: http.MethodGet, Path: "/api/posts", Tag: "posts", Summary: "Latest posts", Cursor: true,
+		Response: postPage},
+	{Method: http.MethodGet, Path: "/api/posts/:id", Tag: "posts", Summary: "Post with its first page of comments",
+		Response: openapi.Fields{"post": post.PostDetailResponse{}}},
+	{Method: http.MethodPut, Path: "/api/posts/:id", Tag: "posts", Summary: "Update a post",
+		Body: post.UpdatePostInput{}, Response: openapi.Fields{"message": "", "post": models.PostResponse{}}},
+	{Method: http.MethodDelete, Path: "/api/posts/:id", Tag: "posts", Summary: "Delete a post",
+		Response: message},
+	{Method: http.MethodPost, Path: "/api/posts/:id/like", Tag: "posts", Summary: "Toggle like",
+		Response: openapi.Fields{"message": "", "liked": false}},
+	{Method: http.MethodPost, Path: "/api/posts/:id/save", Tag: "posts", Summary: "Toggle save",
+		Response: openapi.Fields{"message": "", "saved": false}},
+	{Method: http.MethodGet, Path: "/api/posts/:id/comments", Tag: "posts", Summary: "Comments", Cursor: true,
+		Response: openapi.Fields{"comments": []models.CommentResponse{}, "pagination": pagination.Page{}}},
+	{Method: http.MethodPost, Path: "/api/posts/:id/comment", Tag: "posts", Summary: "Add a comment",
+		Body: post.CreateCommentInput{}, Status: http.StatusCreated, Response: openapi.Fields{"message": "", "comment": models.CommentResponse{}}},
+	{Method: http.MethodPost, Path: "/api/posts/:id/view", Tag: "posts", Summary: "Record a view",
+		Response: openapi.Fields{"message": "", "isNewView": false, "viewCount": 0}},
+
+	// Akış
+	{Method: http.MethodGet, Path: "/api/feed", Tag: "feed", Summary: "Home timeline", Cursor: true,
+		Response: postPage},
+	{Method: http.MethodGet, Path: "/api/feed/suggested", Tag: "feed", Summary: "Ranked suggestions", Cursor: true,
+		Response: postPage},
+
+	// Hashtag
+	{Method: http.MethodGet, Path: "/api/posts/hashtag/:tag", Tag: "posts", Summary: "Posts with a hashtag", Cursor: true,
+		Response: openapi.Fields{"posts": []models.PostResponse{}, "hashtag": "", "pagination": pagination.Page{}}},
+	{Method: http.MethodGet, Path: "/api/hashtags/trending", Tag: "posts", Summary: "Trending hashtags",
+		Response: openapi.Fields{"trendingHashtags": []post.HashtagCount{}}},
+
+	// Ürün ve arama
+	{Method: http.MethodGet, Path: "/api/products", Tag: "products", Summary: "Browse products", Query: product.ListProductsQuery{},
+		Response: openapi.Fields{"products": []product.ProductListItem{}, "pagination": pagination.Numbered{}}},
+	{Method: http.MethodGet, Path: "/api/search/posts", Tag: "search", Summary: "Full-text post search", Query: search.SearchPostsQuery{},
+		Response: openapi.Fields{"posts": []models.PostResponse{}, "query": "", "pagination": pagination.Numbered{}}},
+
+	// Bildirim
+	{Method: http.MethodGet, Path: "/api/notifications", Tag: "notifications", Summary: "Notifications", Cursor: true,
+		Response: openapi.Fields{"notifications": []models.NotificationResponse{}, "pagination": pagination.Page{}}},
+	{Method: http.MethodPut, Path: "/api/notifications/:id/read", Tag: "notifications", Summary: "Mark a notification as read",
+		Response: message},
+	{Method: http.MethodPut, Path: "/api/notifications/preferences", Tag: "notifications", Summary: "Update notification preferences",
+		Body: notification.UpdatePreferencesInput{}, Response: openapi.Fields{"message": "", "preferences": models.NotificationPreferenceResponse{}}},
+
+	// Push cihazları
+	{Method: http.MethodPost, Path: "/api/devices", Tag: "notifications", Summary: "Register a push token; 200 if already registered",
+		Body: push.RegisterDeviceInput{}, Status: http.StatusCreated, Response: message},
+	{Method: http.MethodDelete, Path: "/api/devices/:token", Tag: "notifications", Summary: "Unregister a push token",
+		Response: message},
+
+	// Link analitiği
+	{Method: http.MethodGet, Path: "/api/analytics/links", Tag: "links", Summary: "Own affiliate links",
+		Response: openapi.Fields{"links": []link.LinkResponse{}}},
+	{Method: http.MethodGet, Path: "/api/analytics/clicks", Tag: "links", Summary: "Click stats with recent clicks",
+		Response: openapi.Fields{"stats": []link.ClickStatsResponse{}, "totalLinks": 0}},
+
+	// Şikayet
+	{Method: http.MethodPost, Path: "/api/posts/:id/report", Tag: "moderation", Summary: "Report a post",
+		Body: moderation.ReportInput{}, Status: http.StatusCreated, Response: reportCreated},
+	{Method: http.MethodPost, Path: "/api/comments/:id/report", Tag: "moderation", Summary: "Report a comment",
+		Body: moderation.ReportInput{}, Status: http.StatusCreated, Response: reportCreated},
+	{Method: http.MethodPost, Path: "/api/users/:id/report", Tag: "moderation", Summary: "Report a user",
+		Body: moderation.ReportInput{}, Status: http.StatusCreated, Response: reportCreated},
+
+	// Admin (moderator veya admin rolü)
+	{Method: http.MethodGet, Path: "/api/admin/reports", Tag: "admin", Summary: "Moderation queue grouped by target", Query: moderation.QueueQuery{},
+		Response: openapi.Fields{"items": []moderation.QueueItem{}, "pagination": pagination.Numbered{}}},
+	{Method: http.MethodGet, Path: "/api/admin/reports/:type/:id", Tag: "admin", Summary: "Reports and actions for a target",
+		Response: openapi.Fields{"reports": []moderation.ReportResponse{}, "actions": []moderation.ActionResponse{}}},
+	{Method: http.MethodPost, Path: "/api/admin/reports/:type/:id/action", Tag: "admin", Summary: "Resolve reports with an action",
+		Body: moderation.ModerationActionInput{}, Response: message},
+	{Method: http.MethodGet, Path: "/api/admin/moderation-actions", Tag: "admin", Summary: "Moderation audit log", Query: moderation.ActionsQuery{},
+		Response: openapi.Fields{"actions": []moderation.ActionResponse{}, "pagination": pagination.Numbered{}}},
+	{Method: http.MethodPost, Path: "/api/admin/users/:id/suspend", Tag: "admin", Summary: "Suspend a user",
+		Body: account.SuspendUserInput{}, Response: message},
+	{Method: http.MethodPost, Path: "/api/admin/users/:id/unsuspend", Tag: "admin", Summary: "Lift a suspension",
+		Response: message},
+}
+
+var (
+	postPage      = openapi.Fields{"posts": []models.PostResponse{}, "pagination": pagination.Page{}}
+	userPage      = openapi.Fields{"users": []usersvc.RelatedUserResponse{}, "pagination": pagination.Page{}}
+	reportCreated = openapi.Fields{"message": "", "reportId": uint(0)}
+)
+
+// Handler'ların c.Query ile okuduğu parametreler
+type unsubscribeQuery struct {
+	Type  string `form:"type" binding:"required,oneof=digest report"`
+	Token string `form:"token" binding:"required"`
+}
+
+type downloadQuery struct {
+	Token string `form:"token" binding:"required"`
+}
+
+// Belgeyi yazdırır ya da ("check") router'daki route'larla karşılaştırır.
+// Veritabanı gerektirmez; CI'da çalıştırılabilir.
+func openapiCommand(args []string) {
+	gin.SetMode(gin.ReleaseMode)
+
+	switch {
+	case len(args) == 0:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(openapi.Build(apiInfo, apiRoutes)); err != nil {
+			log.Fatal(err)
+		}
+	case args[0] == "check":
+		r, err := newRouter(handlers{})
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := openapi.CheckRoutes(apiRoutes, r.Routes()); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("All %d routes are documented\n", len(apiRoutes))
+	default:
+		log.Fatalf("Unknown openapi command %q (use check or nothing)", args[0])
+	}
+}
@@ -0,0 +1,140 @@
+// pkg/apierror/problem.go
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sefazor/comfyn/pkg/logging"
+)
+
+const ContentType = "application/problem+json"
+
+// İstemcilerin mesaj metnine bakmadan ayırt edebildiği hata kodu
+type Code string
+
+const (
+	CodeBadRequest   Code = "bad_request"
+	CodeValidation   Code = "validation_failed"
+	CodeUnauthorized Code = "unauthorized"
+	CodeForbidden    Code = "forbidden"
+	CodeNotFound     Code = "not_found"
+	CodeConflict     Code = "conflict"
+	CodeGone         Code = "gone"
+	CodeRateLimited  Code = "rate_limited"
+	CodeInternal     Code = "internal_error"
+
+	CodeInvalidCredentials Code = "invalid_credentials"
+	CodeAccountSuspended   Code = "account_suspended"
+	CodeAccountDeactivated Code = "account_deactivated"
+	CodeAccountExists      Code = "account_exists"
+	CodePrivateAccount     Code = "private_account"
+	CodeTooManyProducts    Code = "too_many_products"
+)
+
+// RFC 7807 hata yanıtı. Type her zaman about:blank olduğu için hatanın
+// türü Code ile belirtilir; Extensions üst seviye alanlar olarak yazılır.
+type Problem struct {
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Status     int                    `json:"status"`
+	Code       Code                   `json:"code"`
+	Detail     string                 `json:"detail,omitempty"`
+	Instance   string                 `json:"instance,omitempty"`
+	RequestID  string                 `json:"requestId,omitempty"`
+	Errors     []FieldError           `json:"errors,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+func New(status int, code Code, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code,
+		Detail: detail,
+	}
+}
+
+// Yanıta ek alan ekler (örn. askıya alma bitiş zamanı)
+func (p *Problem) With(key string, value interface{}) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]interface{})
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	type problem Problem
+	data, err := json.Marshal((*problem)(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return data, err
+	}
+
+	fields := make(map[string]interface{}, len(p.Extensions)+8)
+	for k, v := range p.Extensions {
+		fields[k] = v
+	}
+	// Standart alanlar aynı adlı ek alanları ezer
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// Problemi yazar ve zincirdeki sonraki handler'ları durdurur
+func Abort(c *gin.Context, p *Problem) {
+	p.Instance = c.Request.URL.Path
+	p.RequestID = logging.RequestID(c.Request.Context())
+
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+func BadRequest(c *gin.Context, detail string) {
+	Abort(c, New(http.StatusBadRequest, CodeBadRequest, detail))
+}
+
+func Unauthorized(c *gin.Context, detail string) {
+	Abort(c, New(http.StatusUnauthorized, CodeUnauthorized, detail))
+}
+
+func Forbidden(c *gin.Context, detail string) {
+	Abort(c, New(http.StatusForbidden, CodeForbidden, detail))
+}
+
+func NotFound(c *gin.Context, detail string) {
+	Abort(c, New(http.StatusNotFound, CodeNotFound, detail))
+}
+
+func Conflict(c *gin.Context, detail string) {
+	Abort(c, New(http.StatusConflict, CodeConflict, detail))
+}
+
+func Gone(c *gin.Context, detail string) {
+	Abort(c, New(http.StatusGone, CodeGone, detail))
+}
+
+func TooManyRequests(c *gin.Context, detail string) {
+	Abort(c, New(http.StatusTooManyRequests, CodeRateLimited, detail))
+}
+
+// Askıya alınmış hesap; until boşsa süresizdir
+func AccountSuspended(c *gin.Context, reason string, until *time.Time) {
+	Abort(c, New(http.StatusForbidden, CodeAccountSuspended, "Account suspended").
+		With("reason", reason).
+		With("until", until))
+}
+
+// Gizli hesabın içeriği onaylı takipçi olmayanlara gösterilmez
+func PrivateAccount(c *gin.Context) {
+	Abort(c, New(http.StatusForbidden, CodePrivateAccount, "This account is private"))
+}
+
+// Ayrıntı istemciye gösterilecek genel mesajdır; iç hata bilgisi yazılmaz
+func Internal(c *gin.Context, detail string) {
+	Abort(c, New(http.StatusInternalServerError, CodeInternal, detail))
+}
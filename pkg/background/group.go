@@ -0,0 +1,67 @@
+// pkg/background/group.go
+package background
+
+import (
+	"context"
+	"sync"
+)
+
+// Sunucu süreci içinde istek dışında çalışan işleri (örn. gömülü iş kuyruğu
+// worker'ı) takip eder. Kapanışta Context iptal edilir ve işlerin bitmesi beklenir.
+type Group struct {
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	closed bool
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func New() *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Group{ctx: ctx, cancel: cancel}
+}
+
+// Shutdown başladığında iptal edilir; uzun süren işler bunu dinleyip durmalı
+func (g *Group) Context() context.Context {
+	return g.ctx
+}
+
+// fn'i ayrı bir goroutine'de çalıştırır. Kapanış başladıktan sonra gelen işler
+// kaybolmasın diye çağıranın goroutine'inde çalıştırılır.
+func (g *Group) Go(fn func()) {
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		fn()
+		return
+	}
+	g.wg.Add(1)
+	g.mu.Unlock()
+
+	go func() {
+		defer g.wg.Done()
+		fn()
+	}()
+}
+
+// Context'i iptal eder ve süren işlerin bitmesini bekler. ctx dolarsa
+// işler beklenmeden ctx.Err() döner.
+func (g *Group) Shutdown(ctx context.Context) error {
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
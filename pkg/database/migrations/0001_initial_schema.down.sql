@@ -0,0 +1,30 @@
+DROP TABLE IF EXISTS
+    timeline_entries,
+    saved_posts,
+    data_exports,
+    user_earnings,
+    affiliate_transactions,
+    moderation_actions,
+    reports,
+    follow_requests,
+    user_mutes,
+    user_blocks,
+    affiliate_partners,
+    devices,
+    click_logs,
+    affiliate_links,
+    post_views,
+    notification_preferences,
+    notifications,
+    comments,
+    likes,
+    product_categories,
+    post_hashtags,
+    post_categories,
+    post_products,
+    hashtags,
+    products,
+    posts,
+    categories,
+    user_followers,
+    users;
@@ -0,0 +1,335 @@
+-- Başlangıç şeması. AutoMigrate ile oluşturulmuş mevcut veritabanlarının
+-- da bu sürüme geçebilmesi için tablolar ve indeksler IF NOT EXISTS ile oluşturulur.
+
+CREATE TABLE IF NOT EXISTS users (
+    id                    bigserial PRIMARY KEY,
+    full_name             varchar(100) NOT NULL,
+    email                 varchar(100) NOT NULL UNIQUE,
+    username              varchar(50) NOT NULL UNIQUE,
+    password              text NOT NULL,
+    profile_image         varchar(255),
+    biography             varchar(500),
+    instagram_username    varchar(50),
+    follower_count        bigint DEFAULT 0,
+    following_count       bigint DEFAULT 0,
+    total_views           bigint DEFAULT 0,
+    is_private            boolean DEFAULT false,
+    created_at            timestamptz,
+    updated_at            timestamptz,
+    deleted_at            timestamptz,
+    role                  varchar(20) NOT NULL DEFAULT 'user',
+    suspended_at          timestamptz,
+    suspended_until       timestamptz,
+    suspension_reason     varchar(500),
+    deactivated_at        timestamptz,
+    deletion_scheduled_at timestamptz,
+    anonymized_at         timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS user_followers (
+    follower_id  bigint NOT NULL,
+    following_id bigint NOT NULL,
+    PRIMARY KEY (following_id, follower_id)
+);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id          bigserial PRIMARY KEY,
+    name        varchar(100) NOT NULL UNIQUE,
+    slug        varchar(100) NOT NULL UNIQUE,
+    description text,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories (deleted_at);
+
+CREATE TABLE IF NOT EXISTS posts (
+    id            bigserial PRIMARY KEY,
+    user_id       bigint NOT NULL,
+    image_url     text NOT NULL,
+    description   text,
+    view_count    bigint DEFAULT 0,
+    hidden_at     timestamptz,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    deleted_at    timestamptz,
+    like_count    bigint NOT NULL DEFAULT 0,
+    comment_count bigint NOT NULL DEFAULT 0,
+    save_count    bigint NOT NULL DEFAULT 0,
+    search_vector tsvector
+);
+CREATE INDEX IF NOT EXISTS idx_posts_hidden_at ON posts (hidden_at);
+CREATE INDEX IF NOT EXISTS idx_posts_deleted_at ON posts (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_posts_search_vector ON posts USING GIN (search_vector);
+
+CREATE TABLE IF NOT EXISTS products (
+    id           bigserial PRIMARY KEY,
+    name         text NOT NULL,
+    price        numeric NOT NULL,
+    link         text,
+    tracking_url text,
+    description  text,
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    deleted_at   timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at ON products (deleted_at);
+
+CREATE TABLE IF NOT EXISTS hashtags (
+    id         bigserial PRIMARY KEY,
+    name       varchar(50) NOT NULL UNIQUE,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_hashtags_deleted_at ON hashtags (deleted_at);
+
+CREATE TABLE IF NOT EXISTS post_products (
+    post_id    bigint NOT NULL,
+    product_id bigint NOT NULL,
+    PRIMARY KEY (post_id, product_id)
+);
+
+CREATE TABLE IF NOT EXISTS post_categories (
+    post_id     bigint NOT NULL,
+    category_id bigint NOT NULL,
+    PRIMARY KEY (post_id, category_id)
+);
+
+CREATE TABLE IF NOT EXISTS post_hashtags (
+    post_id    bigint NOT NULL,
+    hashtag_id bigint NOT NULL,
+    PRIMARY KEY (post_id, hashtag_id)
+);
+
+CREATE TABLE IF NOT EXISTS product_categories (
+    product_id  bigint NOT NULL,
+    category_id bigint NOT NULL,
+    PRIMARY KEY (product_id, category_id)
+);
+
+CREATE TABLE IF NOT EXISTS likes (
+    id         bigserial PRIMARY KEY,
+    post_id    bigint NOT NULL,
+    user_id    bigint NOT NULL,
+    created_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id         bigserial PRIMARY KEY,
+    post_id    bigint NOT NULL,
+    user_id    bigint NOT NULL,
+    content    text NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    hidden_at  timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_comments_deleted_at ON comments (deleted_at);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id         bigserial PRIMARY KEY,
+    user_id    bigint NOT NULL,
+    actor_id   bigint NOT NULL,
+    type       text NOT NULL,
+    post_id    bigint,
+    comment_id bigint,
+    is_read    boolean DEFAULT false,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_deleted_at ON notifications (deleted_at);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    id                  bigserial PRIMARY KEY,
+    user_id             bigint NOT NULL,
+    new_follower        boolean DEFAULT true,
+    post_like           boolean DEFAULT true,
+    comment             boolean DEFAULT true,
+    push_new_follower   boolean DEFAULT true,
+    push_post_like      boolean DEFAULT true,
+    push_comment        boolean DEFAULT true,
+    email_digest        varchar(10) DEFAULT 'weekly',
+    weekly_report       boolean DEFAULT true,
+    last_digest_sent_at timestamptz,
+    last_report_sent_at timestamptz,
+    created_at          timestamptz,
+    updated_at          timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_preferences_user_id ON notification_preferences (user_id);
+
+CREATE TABLE IF NOT EXISTS post_views (
+    id         bigserial PRIMARY KEY,
+    post_id    bigint NOT NULL,
+    user_id    bigint NOT NULL,
+    created_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS affiliate_links (
+    id           bigserial PRIMARY KEY,
+    user_id      bigint NOT NULL,
+    post_id      bigint NOT NULL,
+    product_id   bigint NOT NULL,
+    original_url text NOT NULL,
+    tracking_url text NOT NULL,
+    click_count  bigint DEFAULT 0,
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    deleted_at   timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_affiliate_links_deleted_at ON affiliate_links (deleted_at);
+
+CREATE TABLE IF NOT EXISTS click_logs (
+    id                bigserial PRIMARY KEY,
+    affiliate_link_id bigint NOT NULL,
+    user_id           bigint,
+    ip                text NOT NULL,
+    user_agent        text NOT NULL,
+    referer_url       text,
+    created_at        timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS devices (
+    id           bigserial PRIMARY KEY,
+    user_id      bigint NOT NULL,
+    token        varchar(255) NOT NULL,
+    platform     varchar(20) NOT NULL,
+    locale       varchar(10) DEFAULT 'tr',
+    last_seen_at timestamptz,
+    created_at   timestamptz,
+    updated_at   timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_devices_user_id ON devices (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_devices_token ON devices (token);
+
+CREATE TABLE IF NOT EXISTS affiliate_partners (
+    id              bigserial PRIMARY KEY,
+    name            text NOT NULL,
+    base_url        text NOT NULL,
+    commission_rate numeric NOT NULL,
+    webhook_secret  text NOT NULL,
+    api_key         text NOT NULL,
+    is_active       boolean DEFAULT true,
+    created_at      timestamptz,
+    updated_at      timestamptz,
+    deleted_at      timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_affiliate_partners_deleted_at ON affiliate_partners (deleted_at);
+
+CREATE TABLE IF NOT EXISTS user_blocks (
+    id         bigserial PRIMARY KEY,
+    blocker_id bigint NOT NULL,
+    blocked_id bigint NOT NULL,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_blocks_pair ON user_blocks (blocker_id, blocked_id);
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked_id ON user_blocks (blocked_id);
+
+CREATE TABLE IF NOT EXISTS user_mutes (
+    id         bigserial PRIMARY KEY,
+    muter_id   bigint NOT NULL,
+    muted_id   bigint NOT NULL,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_mutes_pair ON user_mutes (muter_id, muted_id);
+
+CREATE TABLE IF NOT EXISTS follow_requests (
+    id           bigserial PRIMARY KEY,
+    requester_id bigint NOT NULL,
+    target_id    bigint NOT NULL,
+    created_at   timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_follow_requests_pair ON follow_requests (requester_id, target_id);
+CREATE INDEX IF NOT EXISTS idx_follow_requests_target_id ON follow_requests (target_id);
+
+CREATE TABLE IF NOT EXISTS reports (
+    id             bigserial PRIMARY KEY,
+    reporter_id    bigint NOT NULL,
+    target_type    varchar(20) NOT NULL,
+    target_id      bigint NOT NULL,
+    reason         varchar(20) NOT NULL,
+    details        varchar(1000),
+    status         varchar(20) NOT NULL DEFAULT 'pending',
+    resolved_by_id bigint,
+    resolved_at    timestamptz,
+    created_at     timestamptz,
+    updated_at     timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_reporter_target ON reports (reporter_id, target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_reports_target ON reports (target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_reports_status ON reports (status);
+
+CREATE TABLE IF NOT EXISTS moderation_actions (
+    id           bigserial PRIMARY KEY,
+    moderator_id bigint,
+    target_type  varchar(20) NOT NULL,
+    target_id    bigint NOT NULL,
+    action       varchar(20) NOT NULL,
+    note         varchar(1000),
+    created_at   timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_moderator_id ON moderation_actions (moderator_id);
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_target ON moderation_actions (target_type, target_id);
+
+CREATE TABLE IF NOT EXISTS affiliate_transactions (
+    id               bigserial PRIMARY KEY,
+    link_id          bigint NOT NULL,
+    user_id          bigint NOT NULL,
+    order_id         text NOT NULL,
+    amount           numeric NOT NULL,
+    commission       numeric NOT NULL,
+    status           text NOT NULL DEFAULT 'pending',
+    transaction_date timestamptz NOT NULL,
+    created_at       timestamptz,
+    updated_at       timestamptz,
+    deleted_at       timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_affiliate_transactions_deleted_at ON affiliate_transactions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS user_earnings (
+    id             bigserial PRIMARY KEY,
+    user_id        bigint NOT NULL,
+    transaction_id bigint NOT NULL,
+    amount         numeric NOT NULL,
+    status         text NOT NULL DEFAULT 'pending',
+    payment_date   timestamptz,
+    created_at     timestamptz,
+    updated_at     timestamptz,
+    deleted_at     timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_user_earnings_deleted_at ON user_earnings (deleted_at);
+
+CREATE TABLE IF NOT EXISTS data_exports (
+    id           bigserial PRIMARY KEY,
+    user_id      bigint NOT NULL,
+    status       varchar(20) NOT NULL DEFAULT 'pending',
+    file_path    varchar(255),
+    error        varchar(500),
+    completed_at timestamptz,
+    expires_at   timestamptz,
+    created_at   timestamptz,
+    updated_at   timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports (user_id);
+
+CREATE TABLE IF NOT EXISTS saved_posts (
+    id         bigserial PRIMARY KEY,
+    user_id    bigint NOT NULL,
+    post_id    bigint NOT NULL,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_posts_user_post ON saved_posts (user_id, post_id);
+CREATE INDEX IF NOT EXISTS idx_saved_posts_post_id ON saved_posts (post_id);
+
+CREATE TABLE IF NOT EXISTS timeline_entries (
+    owner_id        bigint NOT NULL,
+    post_id         bigint NOT NULL,
+    author_id       bigint NOT NULL,
+    post_created_at timestamptz NOT NULL,
+    PRIMARY KEY (owner_id, post_id)
+);
+CREATE INDEX IF NOT EXISTS idx_timeline_entries_post_id ON timeline_entries (post_id);
+CREATE INDEX IF NOT EXISTS idx_timeline_entries_owner_time ON timeline_entries (owner_id, post_created_at DESC, post_id DESC);
+CREATE INDEX IF NOT EXISTS idx_timeline_entries_owner_author ON timeline_entries (owner_id, author_id);
@@ -0,0 +1,108 @@
+-- Silinen tekrar kayıtları geri getirilmez, sadece kısıtlar kaldırılır.
+
+ALTER TABLE timeline_entries
+    DROP CONSTRAINT IF EXISTS fk_timeline_entries_owner,
+    DROP CONSTRAINT IF EXISTS fk_timeline_entries_post,
+    DROP CONSTRAINT IF EXISTS fk_timeline_entries_author;
+
+ALTER TABLE saved_posts
+    DROP CONSTRAINT IF EXISTS fk_saved_posts_user,
+    DROP CONSTRAINT IF EXISTS fk_saved_posts_post;
+
+ALTER TABLE data_exports
+    DROP CONSTRAINT IF EXISTS fk_data_exports_user;
+
+ALTER TABLE user_earnings
+    DROP CONSTRAINT IF EXISTS fk_user_earnings_user,
+    DROP CONSTRAINT IF EXISTS fk_user_earnings_transaction;
+
+ALTER TABLE affiliate_transactions
+    DROP CONSTRAINT IF EXISTS fk_affiliate_transactions_link,
+    DROP CONSTRAINT IF EXISTS fk_affiliate_transactions_user;
+
+ALTER TABLE moderation_actions
+    DROP CONSTRAINT IF EXISTS fk_moderation_actions_moderator;
+
+ALTER TABLE reports
+    DROP CONSTRAINT IF EXISTS fk_reports_reporter,
+    DROP CONSTRAINT IF EXISTS fk_reports_resolved_by;
+
+ALTER TABLE follow_requests
+    DROP CONSTRAINT IF EXISTS fk_follow_requests_requester,
+    DROP CONSTRAINT IF EXISTS fk_follow_requests_target;
+
+ALTER TABLE user_mutes
+    DROP CONSTRAINT IF EXISTS fk_user_mutes_muter,
+    DROP CONSTRAINT IF EXISTS fk_user_mutes_muted;
+
+ALTER TABLE user_blocks
+    DROP CONSTRAINT IF EXISTS fk_user_blocks_blocker,
+    DROP CONSTRAINT IF EXISTS fk_user_blocks_blocked;
+
+ALTER TABLE devices
+    DROP CONSTRAINT IF EXISTS fk_devices_user;
+
+ALTER TABLE click_logs
+    DROP CONSTRAINT IF EXISTS fk_click_logs_affiliate_link,
+    DROP CONSTRAINT IF EXISTS fk_click_logs_user;
+
+ALTER TABLE affiliate_links
+    DROP CONSTRAINT IF EXISTS fk_affiliate_links_user,
+    DROP CONSTRAINT IF EXISTS fk_affiliate_links_post,
+    DROP CONSTRAINT IF EXISTS fk_affiliate_links_product;
+
+ALTER TABLE post_views
+    DROP CONSTRAINT IF EXISTS fk_post_views_post,
+    DROP CONSTRAINT IF EXISTS fk_post_views_user;
+
+ALTER TABLE notification_preferences
+    DROP CONSTRAINT IF EXISTS fk_notification_preferences_user;
+
+ALTER TABLE notifications
+    DROP CONSTRAINT IF EXISTS fk_notifications_user,
+    DROP CONSTRAINT IF EXISTS fk_notifications_actor,
+    DROP CONSTRAINT IF EXISTS fk_notifications_post,
+    DROP CONSTRAINT IF EXISTS fk_notifications_comment;
+
+ALTER TABLE comments
+    DROP CONSTRAINT IF EXISTS fk_comments_post,
+    DROP CONSTRAINT IF EXISTS fk_comments_user;
+
+ALTER TABLE likes
+    DROP CONSTRAINT IF EXISTS fk_likes_post,
+    DROP CONSTRAINT IF EXISTS fk_likes_user;
+
+ALTER TABLE product_categories
+    DROP CONSTRAINT IF EXISTS fk_product_categories_product,
+    DROP CONSTRAINT IF EXISTS fk_product_categories_category;
+
+ALTER TABLE post_hashtags
+    DROP CONSTRAINT IF EXISTS fk_post_hashtags_post,
+    DROP CONSTRAINT IF EXISTS fk_post_hashtags_hashtag;
+
+ALTER TABLE post_categories
+    DROP CONSTRAINT IF EXISTS fk_post_categories_post,
+    DROP CONSTRAINT IF EXISTS fk_post_categories_category;
+
+ALTER TABLE post_products
+    DROP CONSTRAINT IF EXISTS fk_post_products_post,
+    DROP CONSTRAINT IF EXISTS fk_post_products_product;
+
+ALTER TABLE posts
+    DROP CONSTRAINT IF EXISTS fk_posts_user;
+
+ALTER TABLE user_followers
+    DROP CONSTRAINT IF EXISTS chk_user_followers_not_self,
+    DROP CONSTRAINT IF EXISTS fk_user_followers_follower,
+    DROP CONSTRAINT IF EXISTS fk_user_followers_following;
+
+DROP INDEX IF EXISTS idx_click_logs_affiliate_link_id;
+DROP INDEX IF EXISTS idx_affiliate_links_user_id;
+DROP INDEX IF EXISTS idx_notifications_user_created;
+DROP INDEX IF EXISTS idx_comments_post_created;
+DROP INDEX IF EXISTS idx_posts_user_created;
+DROP INDEX IF EXISTS idx_user_followers_follower_id;
+DROP INDEX IF EXISTS idx_post_views_user_id;
+DROP INDEX IF EXISTS idx_post_views_post_user;
+DROP INDEX IF EXISTS idx_likes_user_id;
+DROP INDEX IF EXISTS idx_likes_post_user;
@@ -0,0 +1,118 @@
+-- Tekrarlanan etkileşim kayıtlarını temizleyip tekillik kısıtlarını ve
+-- yabancı anahtarları ekler. Sosyal kayıtlar sahibiyle birlikte silinir;
+-- içerik ve kazanç kayıtları referans verildiği sürece silinemez.
+
+DELETE FROM likes a USING likes b
+WHERE a.post_id = b.post_id AND a.user_id = b.user_id AND a.id > b.id;
+CREATE UNIQUE INDEX idx_likes_post_user ON likes (post_id, user_id);
+CREATE INDEX idx_likes_user_id ON likes (user_id);
+
+DELETE FROM post_views a USING post_views b
+WHERE a.post_id = b.post_id AND a.user_id = b.user_id AND a.id > b.id;
+CREATE UNIQUE INDEX idx_post_views_post_user ON post_views (post_id, user_id);
+CREATE INDEX idx_post_views_user_id ON post_views (user_id);
+
+DELETE FROM user_followers WHERE follower_id = following_id;
+ALTER TABLE user_followers ADD CONSTRAINT chk_user_followers_not_self CHECK (follower_id <> following_id);
+CREATE INDEX idx_user_followers_follower_id ON user_followers (follower_id);
+
+CREATE INDEX idx_posts_user_created ON posts (user_id, created_at DESC, id DESC);
+CREATE INDEX idx_comments_post_created ON comments (post_id, created_at, id);
+CREATE INDEX idx_notifications_user_created ON notifications (user_id, created_at DESC, id DESC);
+CREATE INDEX idx_affiliate_links_user_id ON affiliate_links (user_id);
+CREATE INDEX idx_click_logs_affiliate_link_id ON click_logs (affiliate_link_id, created_at DESC);
+
+ALTER TABLE user_followers
+    ADD CONSTRAINT fk_user_followers_follower FOREIGN KEY (follower_id) REFERENCES users (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_user_followers_following FOREIGN KEY (following_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE posts
+    ADD CONSTRAINT fk_posts_user FOREIGN KEY (user_id) REFERENCES users (id);
+
+ALTER TABLE post_products
+    ADD CONSTRAINT fk_post_products_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_post_products_product FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE;
+
+ALTER TABLE post_categories
+    ADD CONSTRAINT fk_post_categories_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_post_categories_category FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE CASCADE;
+
+ALTER TABLE post_hashtags
+    ADD CONSTRAINT fk_post_hashtags_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_post_hashtags_hashtag FOREIGN KEY (hashtag_id) REFERENCES hashtags (id) ON DELETE CASCADE;
+
+ALTER TABLE product_categories
+    ADD CONSTRAINT fk_product_categories_product FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_product_categories_category FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE CASCADE;
+
+ALTER TABLE likes
+    ADD CONSTRAINT fk_likes_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_likes_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE comments
+    ADD CONSTRAINT fk_comments_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_comments_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE notifications
+    ADD CONSTRAINT fk_notifications_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_notifications_actor FOREIGN KEY (actor_id) REFERENCES users (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_notifications_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_notifications_comment FOREIGN KEY (comment_id) REFERENCES comments (id) ON DELETE CASCADE;
+
+ALTER TABLE notification_preferences
+    ADD CONSTRAINT fk_notification_preferences_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE post_views
+    ADD CONSTRAINT fk_post_views_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_post_views_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE affiliate_links
+    ADD CONSTRAINT fk_affiliate_links_user FOREIGN KEY (user_id) REFERENCES users (id),
+    ADD CONSTRAINT fk_affiliate_links_post FOREIGN KEY (post_id) REFERENCES posts (id),
+    ADD CONSTRAINT fk_affiliate_links_product FOREIGN KEY (product_id) REFERENCES products (id);
+
+ALTER TABLE click_logs
+    ADD CONSTRAINT fk_click_logs_affiliate_link FOREIGN KEY (affiliate_link_id) REFERENCES affiliate_links (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_click_logs_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL;
+
+ALTER TABLE devices
+    ADD CONSTRAINT fk_devices_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE user_blocks
+    ADD CONSTRAINT fk_user_blocks_blocker FOREIGN KEY (blocker_id) REFERENCES users (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_user_blocks_blocked FOREIGN KEY (blocked_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE user_mutes
+    ADD CONSTRAINT fk_user_mutes_muter FOREIGN KEY (muter_id) REFERENCES users (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_user_mutes_muted FOREIGN KEY (muted_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE follow_requests
+    ADD CONSTRAINT fk_follow_requests_requester FOREIGN KEY (requester_id) REFERENCES users (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_follow_requests_target FOREIGN KEY (target_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE reports
+    ADD CONSTRAINT fk_reports_reporter FOREIGN KEY (reporter_id) REFERENCES users (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_reports_resolved_by FOREIGN KEY (resolved_by_id) REFERENCES users (id) ON DELETE SET NULL;
+
+ALTER TABLE moderation_actions
+    ADD CONSTRAINT fk_moderation_actions_moderator FOREIGN KEY (moderator_id) REFERENCES users (id) ON DELETE SET NULL;
+
+ALTER TABLE affiliate_transactions
+    ADD CONSTRAINT fk_affiliate_transactions_link FOREIGN KEY (link_id) REFERENCES affiliate_links (id),
+    ADD CONSTRAINT fk_affiliate_transactions_user FOREIGN KEY (user_id) REFERENCES users (id);
+
+ALTER TABLE user_earnings
+    ADD CONSTRAINT fk_user_earnings_user FOREIGN KEY (user_id) REFERENCES users (id),
+    ADD CONSTRAINT fk_user_earnings_transaction FOREIGN KEY (transaction_id) REFERENCES affiliate_transactions (id);
+
+ALTER TABLE data_exports
+    ADD CONSTRAINT fk_data_exports_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE saved_posts
+    ADD CONSTRAINT fk_saved_posts_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_saved_posts_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE;
+
+ALTER TABLE timeline_entries
+    ADD CONSTRAINT fk_timeline_entries_owner FOREIGN KEY (owner_id) REFERENCES users (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_timeline_entries_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_timeline_entries_author FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+-- Yalnızca veri dolduruldu; geri alınacak şema değişikliği yok
+SELECT 1;
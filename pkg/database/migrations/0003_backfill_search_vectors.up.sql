@@ -0,0 +1,29 @@
+-- Arama eklenmeden önce oluşturulan postların vektörleri boş kalmıştı.
+-- İfade internal/search'teki searchVectorExpr ile aynıdır; sonraki
+-- değişiklikler için `comfyn reindex` kullanılır.
+UPDATE posts SET search_vector =
+    setweight(to_tsvector('turkish', coalesce(posts.description, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(posts.description, '')), 'A') ||
+    setweight(to_tsvector('turkish', coalesce(doc.products, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(doc.products, '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce(doc.hashtags, '')), 'B') ||
+    setweight(to_tsvector('turkish', coalesce(doc.categories, '')), 'C') ||
+    setweight(to_tsvector('english', coalesce(doc.categories, '')), 'C')
+FROM (
+    SELECT p.id,
+        (SELECT string_agg(pr.name || ' ' || coalesce(pr.description, ''), ' ')
+            FROM products pr
+            JOIN post_products pp ON pp.product_id = pr.id
+            WHERE pp.post_id = p.id AND pr.deleted_at IS NULL) AS products,
+        (SELECT string_agg(h.name, ' ')
+            FROM hashtags h
+            JOIN post_hashtags ph ON ph.hashtag_id = h.id
+            WHERE ph.post_id = p.id) AS hashtags,
+        (SELECT string_agg(c.name, ' ')
+            FROM categories c
+            JOIN post_categories pc ON pc.category_id = c.id
+            WHERE pc.post_id = p.id) AS categories
+    FROM posts p
+    WHERE p.search_vector IS NULL AND p.deleted_at IS NULL
+) doc
+WHERE posts.id = doc.id;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_user_followers_follower_order;
+DROP INDEX IF EXISTS idx_user_followers_following_order;
+DROP INDEX IF EXISTS idx_user_followers_id;
+
+ALTER TABLE user_followers
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS id;
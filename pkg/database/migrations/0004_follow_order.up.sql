@@ -0,0 +1,8 @@
+-- Takip listeleri takip zamanına göre sayfalanır; id eşit zamanlarda sırayı belirler
+ALTER TABLE user_followers
+    ADD COLUMN id bigserial,
+    ADD COLUMN created_at timestamptz NOT NULL DEFAULT now();
+
+CREATE UNIQUE INDEX idx_user_followers_id ON user_followers (id);
+CREATE INDEX idx_user_followers_following_order ON user_followers (following_id, created_at, id);
+CREATE INDEX idx_user_followers_follower_order ON user_followers (follower_id, created_at, id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_posts_user_pulled;
+ALTER TABLE posts DROP COLUMN IF EXISTS fanned_out;
@@ -0,0 +1,8 @@
+-- Takipçi akışlarına yazılan postlar işaretlenir; yazılmayanlar hesabın
+-- güncel takipçi sayısından bağımsız olarak okuma sırasında çekilir
+ALTER TABLE posts ADD COLUMN fanned_out boolean NOT NULL DEFAULT false;
+
+UPDATE posts SET fanned_out = true
+WHERE EXISTS (SELECT 1 FROM timeline_entries te WHERE te.post_id = posts.id);
+
+CREATE INDEX idx_posts_user_pulled ON posts (user_id, created_at DESC, id DESC) WHERE NOT fanned_out;
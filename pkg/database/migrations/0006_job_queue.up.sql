@@ -0,0 +1,29 @@
+-- Kalıcı arka plan iş kuyruğu. Başarılı işler silinir; deneme hakkı biten
+-- işler 'dead' durumunda incelenmek üzere kalır.
+
+CREATE TABLE jobs (
+    id           bigserial PRIMARY KEY,
+    kind         text        NOT NULL,
+    payload      jsonb       NOT NULL DEFAULT '{}',
+    status       text        NOT NULL DEFAULT 'pending',
+    attempts     integer     NOT NULL DEFAULT 0,
+    max_attempts integer     NOT NULL DEFAULT 10,
+    run_at       timestamptz NOT NULL DEFAULT now(),
+    locked_at    timestamptz,
+    locked_by    text,
+    last_error   text,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    updated_at   timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT chk_jobs_status CHECK (status IN ('pending', 'running', 'dead'))
+);
+
+CREATE INDEX idx_jobs_ready ON jobs (run_at, id) WHERE status = 'pending';
+CREATE INDEX idx_jobs_running ON jobs (locked_at) WHERE status = 'running';
+CREATE INDEX idx_jobs_dead ON jobs (updated_at DESC) WHERE status = 'dead';
+
+-- Tekrarlayan işlerin bir sonraki çalışma zamanı; birden fazla worker
+-- aynı zamanı kuyruğa eklemesin diye satır kilidiyle ilerletilir
+CREATE TABLE job_schedules (
+    name        text PRIMARY KEY,
+    next_run_at timestamptz NOT NULL
+);
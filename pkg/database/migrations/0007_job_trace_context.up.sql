@@ -0,0 +1,2 @@
+-- İşi kuyruğa ekleyen isteğin W3C traceparent değeri; işin span'i bu trace'e bağlanır
+ALTER TABLE jobs ADD COLUMN trace_parent text NOT NULL DEFAULT '';
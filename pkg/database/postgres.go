@@ -2,79 +2,51 @@ package database
 
 import (
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 
-	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/configs"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
-var DB *gorm.DB
-
-func InitDB() {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=require",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_PORT"),
-	)
-
+// Veritabanına bağlanır ve bağlantıyı test eder
+func Open(cfg configs.DatabaseConfig) (*gorm.DB, error) {
 	// PostgreSQL sürücü ayarlarını yapılandır
 	pgConfig := postgres.Config{
-		DSN:                  dsn,
+		DSN:                  cfg.DSN(),
 		PreferSimpleProtocol: true,
 	}
 
 	config := &gorm.Config{
-		PrepareStmt:                              false,
-		Logger:                                   logger.Default.LogMode(logger.Silent),
-		DisableForeignKeyConstraintWhenMigrating: true,
-		SkipDefaultTransaction:                   true,
+		PrepareStmt:            false,
+		Logger:                 newLogger(cfg.SlowQueryThreshold),
+		SkipDefaultTransaction: true,
 	}
 
-	var err error
-	DB, err = gorm.Open(postgres.New(pgConfig), config)
+	db, err := gorm.Open(postgres.New(pgConfig), config)
 	if err != nil {
-		log.Fatal("Failed to connect to database: ", err)
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+
+	// Tracing kapalıyken span'ler no-op olduğu için eklenti her zaman kurulur
+	if err := db.Use(tracingPlugin{}); err != nil {
+		return nil, fmt.Errorf("register tracing plugin: %w", err)
 	}
 
-	sqlDB, err := DB.DB()
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatal("Failed to get database instance: ", err)
+		return nil, fmt.Errorf("get database instance: %w", err)
 	}
 
 	// Bağlantıyı test et
-	err = sqlDB.Ping()
-	if err != nil {
-		log.Fatal("Failed to ping database: ", err)
+	if err := sqlDB.Ping(); err != nil {
+		return nil, fmt.Errorf("ping database: %w", err)
 	}
 
 	// Connection pool ayarları
 	sqlDB.SetMaxIdleConns(10)
 	sqlDB.SetMaxOpenConns(100)
 
-	log.Println("Database connection established successfully")
-
-	// Migrationları çalıştır
-	if err := DB.AutoMigrate(
-		&models.User{},
-		&models.Post{},
-		&models.Product{},
-		&models.Category{},
-		&models.Like{},
-		&models.Comment{},
-		&models.Hashtag{},
-		&models.Notification{},
-		&models.NotificationPreference{},
-		&models.PostView{},
-		&models.AffiliateLink{},
-		&models.ClickLog{},
-	); err != nil {
-		log.Printf("Warning: Migration issues: %v", err)
-	} else {
-		log.Println("Migrations completed successfully")
-	}
+	slog.Info("Database connection established")
+	return db, nil
 }
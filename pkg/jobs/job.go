@@ -0,0 +1,115 @@
+// pkg/jobs/job.go
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	// Deneme hakkı biten veya kalıcı hatayla sonlanan işler
+	StatusDead Status = "dead"
+)
+
+const defaultMaxAttempts = 10
+
+type Job struct {
+	ID          int64
+	Kind        string
+	Payload     string `gorm:"type:jsonb"` // JSON; simple protocol []byte'ı bytea olarak gönderdiği için string
+	Status      Status
+	Attempts    int
+	MaxAttempts int
+	RunAt       time.Time
+	LockedAt    *time.Time
+	LockedBy    string
+	LastError   string
+	// Kuyruğa ekleyen isteğin traceparent'ı; tracing kapalıysa boş
+	TraceParent string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Tekrarlayan işlere verilen payload; At işin planlandığı zamandır
+type Tick struct {
+	At time.Time `json:"at"`
+}
+
+// Payload tipiyle eşleştirilmiş iş türü. Kuyruğa ekleyen ve işleyen taraf
+// aynı Kind değerini kullanır, böylece payload tipi derleme zamanında denetlenir.
+type Kind[T any] struct {
+	name string
+	opts []Option
+}
+
+// opts bu türdeki her işe uygulanır; Enqueue'ya verilenler bunları ezer
+func NewKind[T any](name string, opts ...Option) Kind[T] {
+	return Kind[T]{name: name, opts: opts}
+}
+
+func (k Kind[T]) Name() string {
+	return k.name
+}
+
+type options struct {
+	runAt       time.Time
+	maxAttempts int
+}
+
+type Option func(*options)
+
+// İşi belirtilen zamandan önce çalıştırmaz
+func RunAt(t time.Time) Option {
+	return func(o *options) { o.runAt = t }
+}
+
+// İşi d kadar sonra çalıştırır
+func Delay(d time.Duration) Option {
+	return func(o *options) { o.runAt = time.Now().Add(d) }
+}
+
+// İş bu kadar denemeden sonra dead durumuna geçer
+func MaxAttempts(n int) Option {
+	return func(o *options) { o.maxAttempts = n }
+}
+
+// Tekrar denenmesi anlamsız hata (örn. bozuk payload, silinmiş kayıt);
+// iş beklemeden dead durumuna geçer
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+func isPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
+type attemptKey struct{}
+
+func withAttempt(ctx context.Context, job *Job) context.Context {
+	return context.WithValue(ctx, attemptKey{}, [2]int{job.Attempts, job.MaxAttempts})
+}
+
+// Handler'a verilen ctx'teki işin son deneme hakkında olup olmadığı; bu
+// denemede dönen hata işi dead durumuna geçirir. İş dışında false döner.
+func IsLastAttempt(ctx context.Context) bool {
+	attempt, ok := ctx.Value(attemptKey{}).([2]int)
+	return ok && attempt[0] >= attempt[1]
+}
@@ -0,0 +1,107 @@
+// pkg/jobs/queue.go
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"gorm.io/gorm"
+)
+
+// Postgres tablosu üzerinde kalıcı iş kuyruğu
+type Queue struct {
+	db *gorm.DB
+}
+
+func NewQueue(db *gorm.DB) *Queue {
+	return &Queue{db: db}
+}
+
+// Kuyruğu verilen transaction'a bağlar; işler ancak transaction commit
+// edilirse görünür hale gelir
+func (q *Queue) WithTx(tx *gorm.DB) *Queue {
+	return &Queue{db: tx}
+}
+
+// payload'ı k türünde iş olarak kuyruğa ekler
+func (k Kind[T]) Enqueue(ctx context.Context, q *Queue, payload T, opts ...Option) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode %s payload: %w", k.name, err)
+	}
+
+	o := options{maxAttempts: defaultMaxAttempts}
+	for _, opt := range append(k.opts, opts...) {
+		opt(&o)
+	}
+	if o.runAt.IsZero() {
+		o.runAt = time.Now()
+	}
+
+	// İşin span'i sonradan bu isteğin trace'ine bağlanabilsin
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	return q.db.WithContext(ctx).Create(&Job{
+		Kind:        k.name,
+		Payload:     string(data),
+		Status:      StatusPending,
+		MaxAttempts: o.maxAttempts,
+		RunAt:       o.runAt,
+		TraceParent: carrier.Get("traceparent"),
+	}).Error
+}
+
+// Durumlara göre iş sayıları
+func (q *Queue) Stats(ctx context.Context) (map[Status]int64, error) {
+	var rows []struct {
+		Status Status
+		Count  int64
+	}
+	if err := q.db.WithContext(ctx).Model(&Job{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	stats := make(map[Status]int64, len(rows))
+	for _, row := range rows {
+		stats[row.Status] = row.Count
+	}
+	return stats, nil
+}
+
+// En son ölen işler
+func (q *Queue) DeadJobs(ctx context.Context, limit int) ([]Job, error) {
+	var jobs []Job
+	err := q.db.WithContext(ctx).
+		Where("status = ?", StatusDead).
+		Order("updated_at DESC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// Ölü işi deneme sayısını sıfırlayarak yeniden kuyruğa alır; id 0 ise tüm
+// ölü işleri alır. Yeniden kuyruğa alınan iş sayısını döner.
+func (q *Queue) RetryDead(ctx context.Context, id int64) (int64, error) {
+	query := q.db.WithContext(ctx).Model(&Job{}).Where("status = ?", StatusDead)
+	if id != 0 {
+		query = query.Where("id = ?", id)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"status":     StatusPending,
+		"attempts":   0,
+		"run_at":     gorm.Expr("now()"),
+		"locked_at":  nil,
+		"locked_by":  "",
+		"updated_at": gorm.Expr("now()"),
+	})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,345 @@
+// pkg/jobs/worker.go
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sefazor/comfyn/pkg/metrics"
+	"github.com/sefazor/comfyn/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	initialBackoff = 10 * time.Second
+	maxBackoff     = time.Hour
+)
+
+type WorkerConfig struct {
+	// Aynı anda işlenen en fazla iş sayısı
+	Concurrency int
+	// Kuyruk boşken yeni iş için bekleme aralığı
+	PollInterval time.Duration
+	// Tek bir işin en uzun çalışma süresi. Bundan iki kat uzun süredir
+	// çalışıyor görünen iş, worker'ı çökmüş sayılarak tekrar kuyruğa alınır.
+	JobTimeout time.Duration
+}
+
+type handler func(ctx context.Context, job *Job) error
+
+type schedule struct {
+	kind     Kind[Tick]
+	interval time.Duration
+}
+
+// Kuyruktaki işleri FOR UPDATE SKIP LOCKED ile alıp kayıtlı handler'larla
+// işler; birden fazla süreçte aynı anda çalışabilir
+type Worker struct {
+	db        *gorm.DB
+	queue     *Queue
+	cfg       WorkerConfig
+	id        string
+	handlers  map[string]handler
+	schedules []schedule
+}
+
+func NewWorker(db *gorm.DB, cfg WorkerConfig) *Worker {
+	hostname, _ := os.Hostname()
+	return &Worker{
+		db:       db,
+		queue:    NewQueue(db),
+		cfg:      cfg,
+		id:       fmt.Sprintf("%s:%d", hostname, os.Getpid()),
+		handlers: make(map[string]handler),
+	}
+}
+
+// k türündeki işleri fn ile işler. Payload çözülemezse iş tekrar denenmez.
+func Handle[T any](w *Worker, k Kind[T], fn func(ctx context.Context, payload T) error) {
+	if _, exists := w.handlers[k.name]; exists {
+		panic("jobs: duplicate handler for " + k.name)
+	}
+	w.handlers[k.name] = func(ctx context.Context, job *Job) error {
+		var payload T
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return Permanent(fmt.Errorf("decode payload: %w", err))
+		}
+		return fn(ctx, payload)
+	}
+}
+
+// fn'i her interval'de bir, interval'e hizalı zamanlarda (örn. saat başı)
+// çalıştırır. Kaç worker olursa olsun her zaman dilimi için tek iş oluşur;
+// worker'lar kapalıyken kaçırılan dilimler telafi edilmez.
+func (w *Worker) Every(name string, interval time.Duration, fn func(ctx context.Context, at time.Time) error) {
+	kind := NewKind[Tick](name, MaxAttempts(3))
+	Handle(w, kind, func(ctx context.Context, tick Tick) error {
+		return fn(ctx, tick.At)
+	})
+	w.schedules = append(w.schedules, schedule{kind: kind, interval: interval})
+}
+
+// ctx iptal edilene kadar işleri işler; iptalden sonra süren işlerin bitmesini bekler
+func (w *Worker) Run(ctx context.Context) error {
+	if err := w.initSchedules(ctx); err != nil {
+		return err
+	}
+	log.Printf("Job worker %s started with %d handlers", w.id, len(w.handlers))
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.maintain(ctx)
+	}()
+
+	wg.Wait()
+	log.Printf("Job worker %s stopped", w.id)
+	return nil
+}
+
+// Boşta kalmadıkça işleri art arda alır, kuyruk boşsa PollInterval bekler
+func (w *Worker) loop(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := w.claim(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to claim job: %v", err)
+		}
+		if job != nil {
+			w.process(ctx, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.cfg.PollInterval):
+		}
+	}
+}
+
+// Zamanı gelen tekrarlayan işleri kuyruğa ekler ve çökmüş worker'ların işlerini geri alır
+func (w *Worker) maintain(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.enqueueDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to enqueue scheduled jobs: %v", err)
+		}
+		if err := w.rescue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to rescue stale jobs: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) claim(ctx context.Context) (*Job, error) {
+	var jobs []Job
+	err := w.db.WithContext(ctx).Raw(`
+		UPDATE jobs SET status = ?, attempts = attempts + 1, locked_at = now(), locked_by = ?, updated_at = now()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE status = ? AND run_at <= now()
+			ORDER BY run_at, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING id, kind, payload, attempts, max_attempts, run_at, trace_parent, created_at`,
+		StatusRunning, w.id, StatusPending).Scan(&jobs).Error
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return &jobs[0], nil
+}
+
+func (w *Worker) process(ctx context.Context, job *Job) {
+	// Kapanış sinyali süren işi yarıda kesmez; iş JobTimeout kadar sürebilir
+	jobCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), w.cfg.JobTimeout)
+	defer cancel()
+
+	jobCtx, span := w.startSpan(withAttempt(jobCtx, job), job)
+	defer span.End()
+
+	err := w.run(jobCtx, job)
+	if err == nil {
+		metrics.JobsProcessed.WithLabelValues(job.Kind, "success").Inc()
+		w.finish(job, w.db.Delete(&Job{}, job.ID).Error)
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	if isPermanent(err) || job.Attempts >= job.MaxAttempts {
+		metrics.JobsProcessed.WithLabelValues(job.Kind, "dead").Inc()
+		log.Printf("Job %d (%s) failed permanently after %d attempts: %v", job.ID, job.Kind, job.Attempts, err)
+		w.finish(job, w.db.Model(&Job{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+			"status":     StatusDead,
+			"last_error": err.Error(),
+			"locked_at":  nil,
+			"updated_at": gorm.Expr("now()"),
+		}).Error)
+		return
+	}
+
+	metrics.JobsProcessed.WithLabelValues(job.Kind, "retry").Inc()
+	delay := backoff(job.Attempts)
+	log.Printf("Job %d (%s) attempt %d failed, retrying in %s: %v", job.ID, job.Kind, job.Attempts, delay.Round(time.Second), err)
+	w.finish(job, w.db.Model(&Job{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+		"status":     StatusPending,
+		"run_at":     time.Now().Add(delay),
+		"last_error": err.Error(),
+		"locked_at":  nil,
+		"updated_at": gorm.Expr("now()"),
+	}).Error)
+}
+
+// İş kuyruğa eklendikten çok sonra çalışabildiği için span ekleyen isteğin
+// altına değil, ona bağlantı (link) verilerek yeni bir trace'te açılır
+func (w *Worker) startSpan(ctx context.Context, job *Job) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("job.kind", job.Kind),
+			attribute.Int64("job.id", job.ID),
+			attribute.Int("job.attempt", job.Attempts),
+		),
+	}
+	if job.TraceParent != "" {
+		carrier := propagation.MapCarrier{"traceparent": job.TraceParent}
+		parent := trace.SpanContextFromContext(otel.GetTextMapPropagator().Extract(context.Background(), carrier))
+		if parent.IsValid() {
+			opts = append(opts, trace.WithLinks(trace.Link{SpanContext: parent}))
+		}
+	}
+	return tracing.Tracer().Start(ctx, "job "+job.Kind, opts...)
+}
+
+// Handler panic'lerini hataya çevirir ki worker goroutine'i ölmesin
+func (w *Worker) run(ctx context.Context, job *Job) (err error) {
+	h, ok := w.handlers[job.Kind]
+	if !ok {
+		return fmt.Errorf("no handler registered for job kind %q", job.Kind)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h(ctx, job)
+}
+
+func (w *Worker) finish(job *Job, err error) {
+	if err != nil {
+		log.Printf("Failed to record result of job %d (%s): %v", job.ID, job.Kind, err)
+	}
+}
+
+// Deneme sayısına göre üstel artan, rastgele sapmalı bekleme süresi
+func backoff(attempt int) time.Duration {
+	delay := maxBackoff
+	if attempt < 20 {
+		delay = min(initialBackoff<<(attempt-1), maxBackoff)
+	}
+	// Aynı anda başarısız olan işler aynı anda tekrar denenmesin
+	jitter := time.Duration(rand.Int63n(int64(delay)/5 + 1))
+	return delay - delay/10 + jitter
+}
+
+// Çalışırken worker'ı ölen işleri tekrar kuyruğa alır, hakkı bittiyse öldürür
+func (w *Worker) rescue(ctx context.Context) error {
+	result := w.db.WithContext(ctx).Exec(`
+		UPDATE jobs SET
+			status = CASE WHEN attempts >= max_attempts THEN ? ELSE ? END,
+			last_error = 'worker ' || coalesce(locked_by, '') || ' stopped while running the job',
+			locked_at = NULL,
+			updated_at = now()
+		WHERE status = ? AND locked_at < ?`,
+		StatusDead, StatusPending, StatusRunning, time.Now().Add(-2*w.cfg.JobTimeout))
+	if result.RowsAffected > 0 {
+		log.Printf("Rescued %d stale jobs", result.RowsAffected)
+	}
+	return result.Error
+}
+
+type jobSchedule struct {
+	Name      string `gorm:"primaryKey"`
+	NextRunAt time.Time
+}
+
+func (jobSchedule) TableName() string {
+	return "job_schedules"
+}
+
+// Kayıtlı tekrarlayan işlerin ilk çalışma zamanını bir sonraki dilim olarak yazar
+func (w *Worker) initSchedules(ctx context.Context) error {
+	now := time.Now()
+	for _, s := range w.schedules {
+		row := jobSchedule{Name: s.kind.name, NextRunAt: now.Truncate(s.interval).Add(s.interval)}
+		if err := w.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+			return fmt.Errorf("init schedule %s: %w", s.kind.name, err)
+		}
+	}
+	return nil
+}
+
+func (w *Worker) enqueueDue(ctx context.Context) error {
+	var errs []error
+	for _, s := range w.schedules {
+		if err := w.enqueueSchedule(ctx, s); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.kind.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *Worker) enqueueSchedule(ctx context.Context, s schedule) error {
+	return w.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rows []jobSchedule
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("name = ? AND next_run_at <= now()", s.kind.name).
+			Find(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		at := rows[0].NextRunAt
+		next := at.Add(s.interval)
+		if now := time.Now(); !next.After(now) {
+			next = now.Truncate(s.interval).Add(s.interval)
+		}
+		if err := tx.Model(&jobSchedule{}).Where("name = ?", s.kind.name).Update("next_run_at", next).Error; err != nil {
+			return err
+		}
+
+		return s.kind.Enqueue(ctx, w.queue.WithTx(tx), Tick{At: at})
+	})
+}
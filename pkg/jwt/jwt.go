@@ -1,12 +1,18 @@
 package jwt
 
 import (
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+var secret []byte
+
+// Token imzalama anahtarını ayarlar, token üretmeden önce çağrılmalı
+func Init(signingSecret string) {
+	secret = []byte(signingSecret)
+}
+
 func GenerateToken(userID uint) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
@@ -15,22 +21,72 @@ func GenerateToken(userID uint) (string, error) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	return token.SignedString(secret)
 }
 
 func ValidateToken(tokenString string) (uint, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET")), nil
-	})
+	claims, err := parse(tokenString)
+	if err != nil {
+		return 0, err
+	}
+
+	// Kapsamlı token'lar (abonelik iptali vb.) oturum açmak için kullanılamaz
+	if _, scoped := claims["scope"]; scoped {
+		return 0, jwt.ErrTokenInvalidClaims
+	}
+
+	return userIDFromClaims(claims)
+}
+
+// Tek bir amaç için üretilen token (örn. e-posta abonelik iptali linki).
+// ttl sıfır ise token süresiz geçerlidir.
+func GenerateScopedToken(userID uint, scope string, ttl time.Duration) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"scope":   scope,
+		"iat":     time.Now().Unix(),
+	}
+	if ttl > 0 {
+		claims["exp"] = time.Now().Add(ttl).Unix()
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(secret)
+}
 
+func ValidateScopedToken(tokenString string, scope string) (uint, error) {
+	claims, err := parse(tokenString)
 	if err != nil {
 		return 0, err
 	}
 
+	if tokenScope, _ := claims["scope"].(string); tokenScope != scope {
+		return 0, jwt.ErrTokenInvalidClaims
+	}
+
+	return userIDFromClaims(claims)
+}
+
+func parse(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil {
+		return nil, err
+	}
+
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID := uint(claims["user_id"].(float64))
-		return userID, nil
+		return claims, nil
 	}
 
-	return 0, jwt.ErrSignatureInvalid
+	return nil, jwt.ErrSignatureInvalid
+}
+
+func userIDFromClaims(claims jwt.MapClaims) (uint, error) {
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, jwt.ErrTokenInvalidClaims
+	}
+	return uint(userID), nil
 }
@@ -0,0 +1,133 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/sefazor/comfyn/configs"
+)
+
+type Email struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+	Headers  map[string]string // Örn. List-Unsubscribe
+}
+
+type Sender interface {
+	Send(ctx context.Context, email Email) error
+}
+
+var Default Sender = LogSender{}
+
+// SMTP ayarları varsa SMTP göndericisini, yoksa log göndericisini kullanır
+func Init(cfg configs.MailConfig) {
+	if cfg.SMTPHost == "" {
+		log.Println("SMTP_HOST not set, emails will only be logged")
+		return
+	}
+
+	Default = &SMTPSender{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUser,
+		Password: cfg.SMTPPassword,
+		From:     cfg.From,
+	}
+}
+
+func Send(ctx context.Context, email Email) error {
+	return Default.Send(ctx, email)
+}
+
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTPSender) Send(ctx context.Context, email Email) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	msg, err := buildMessage(s.From, email)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	return smtp.SendMail(s.Host+":"+s.Port, auth, s.From, []string{email.To}, msg)
+}
+
+// Geliştirme ortamı için e-postaları sadece loglar
+type LogSender struct{}
+
+func (LogSender) Send(ctx context.Context, email Email) error {
+	log.Printf("Email to %s: %s\n%s", email.To, email.Subject, email.TextBody)
+	return nil
+}
+
+// Düz metin ve HTML içeren multipart/alternative mesaj oluşturur
+func buildMessage(from string, email Email) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	headers := map[string]string{
+		"From":         from,
+		"To":           email.To,
+		"Subject":      mime.QEncoding.Encode("utf-8", email.Subject),
+		"Date":         time.Now().Format(time.RFC1123Z),
+		"MIME-Version": "1.0",
+		"Content-Type": fmt.Sprintf("multipart/alternative; boundary=%s", writer.Boundary()),
+	}
+	for k, v := range email.Headers {
+		headers[k] = v
+	}
+
+	var header strings.Builder
+	for k, v := range headers {
+		fmt.Fprintf(&header, "%s: %s\r\n", k, v)
+	}
+	header.WriteString("\r\n")
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=UTF-8", email.TextBody},
+		{"text/html; charset=UTF-8", email.HTMLBody},
+	}
+	for _, p := range parts {
+		if p.body == "" {
+			continue
+		}
+		part, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {p.contentType}})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write([]byte(p.body)); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return append([]byte(header.String()), buf.Bytes()...), nil
+}
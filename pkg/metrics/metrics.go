@@ -0,0 +1,71 @@
+// pkg/metrics/metrics.go
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "comfyn"
+
+// Sonuç etiketi değerleri
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+var (
+	// Route etiketi şablon yoludur (/api/posts/:id), böylece etiket sayısı sınırlı kalır
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	LinkRedirects = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "link_redirects_total",
+		Help:      "Affiliate link redirects served.",
+	})
+
+	NotificationsCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_created_total",
+		Help:      "Notifications created by type.",
+	}, []string{"type"})
+
+	PushDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "push_deliveries_total",
+		Help:      "Push notification delivery attempts by result.",
+	}, []string{"result"})
+
+	JobsProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_processed_total",
+		Help:      "Background jobs processed by kind and result (success, retry, dead).",
+	}, []string{"kind", "result"})
+)
+
+// Bağlantı havuzu istatistiklerini (açık/boşta bağlantı, bekleme süresi) yayınlar
+func RegisterDB(db *sql.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+}
+
+// /metrics endpoint'i
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
+
+func Result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
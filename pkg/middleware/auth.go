@@ -3,48 +3,82 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sefazor/comfyn/internal/models"
-	"github.com/sefazor/comfyn/pkg/database"
+	"github.com/sefazor/comfyn/pkg/apierror"
 	"github.com/sefazor/comfyn/pkg/jwt"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// Token'daki kullanıcıyı yüklemek için gereken işlem (user.Store karşılar)
+type UserFinder interface {
+	FindByID(id uint) (*models.User, error)
+}
+
+func AuthMiddleware(users UserFinder) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
-			c.Abort()
+			apierror.Unauthorized(c, "Authorization header is required")
 			return
 		}
 
 		// Bearer token'ı ayıkla
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token format"})
-			c.Abort()
+			apierror.Unauthorized(c, "Invalid token format")
 			return
 		}
 
 		// Token'ı doğrula ve user ID'yi al
 		userID, err := jwt.ValidateToken(parts[1])
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
+			apierror.Unauthorized(c, "Invalid token")
 			return
 		}
 
 		// Kullanıcıyı veritabanından al
-		var user models.User
-		if err := database.DB.First(&user, userID).Error; err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
-			c.Abort()
+		user, err := users.FindByID(userID)
+		if err != nil {
+			apierror.Unauthorized(c, "User not found")
+			return
+		}
+
+		// Askıya alınmış hesaplar API'yi kullanamaz
+		if user.IsSuspended(time.Now()) {
+			apierror.AccountSuspended(c, user.SuspensionReason, user.SuspendedUntil)
+			return
+		}
+
+		// Devre dışı bırakılan hesap tekrar giriş yapana kadar kullanılamaz
+		if user.DeactivatedAt != nil {
+			apierror.Abort(c, apierror.New(http.StatusUnauthorized, apierror.CodeAccountDeactivated,
+				"Account is deactivated, log in again to reactivate it"))
 			return
 		}
 
 		// User'ı context'e ekle
-		c.Set("user", user)
+		c.Set("user", *user)
+		c.Next()
+	}
+}
+
+// AuthMiddleware'den sonra kullanılmalı; kullanıcının rollerden birine sahip olmasını ister
+func RequireRole(roles ...models.UserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := c.Get("user")
+		if !exists {
+			apierror.Unauthorized(c, "Authentication required")
+			return
+		}
+
+		currentUser := user.(models.User)
+		if !currentUser.HasRole(roles...) {
+			apierror.Forbidden(c, "Insufficient permissions")
+			return
+		}
+
 		c.Next()
 	}
 }
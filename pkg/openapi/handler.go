@@ -0,0 +1,94 @@
+// pkg/openapi/handler.go
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Belgeyi bir kez JSON'a çevirip sunar
+func Handler(doc *Document) (gin.HandlerFunc, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encode openapi document: %w", err)
+	}
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", data)
+	}, nil
+}
+
+var swaggerUI = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: {{.SpecURL}}, dom_id: "#swagger-ui", persistAuthorization: true });
+    };
+  </script>
+</body>
+</html>
+`))
+
+// Belgeyi specURL'den okuyan Swagger UI sayfası
+func SwaggerUI(title, specURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusOK)
+		if err := swaggerUI.Execute(c.Writer, map[string]string{"Title": title, "SpecURL": specURL}); err != nil {
+			c.Error(err)
+		}
+	}
+}
+
+// Kayıtlı route'larla belgedeki route'ları karşılaştırır. Belgelenmemiş
+// ya da artık kayıtlı olmayan route varsa hepsini listeleyen hata döner.
+func CheckRoutes(routes []Route, registered gin.RoutesInfo) error {
+	documented := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		documented[route.Method+" "+route.Path] = true
+	}
+
+	var undocumented []string
+	for _, route := range registered {
+		key := route.Method + " " + route.Path
+		if !documented[key] {
+			undocumented = append(undocumented, key)
+		}
+		delete(documented, key)
+	}
+
+	stale := make([]string, 0, len(documented))
+	for key := range documented {
+		stale = append(stale, key)
+	}
+
+	if len(undocumented) == 0 && len(stale) == 0 {
+		return nil
+	}
+	sort.Strings(undocumented)
+	sort.Strings(stale)
+
+	var b strings.Builder
+	b.WriteString("openapi document is out of date")
+	for _, key := range undocumented {
+		b.WriteString("\n  missing from spec:  " + key)
+	}
+	for _, key := range stale {
+		b.WriteString("\n  not registered:     " + key)
+	}
+	return errors.New(b.String())
+}
@@ -0,0 +1,219 @@
+// pkg/openapi/route.go
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sefazor/comfyn/pkg/apierror"
+	"github.com/sefazor/comfyn/pkg/pagination"
+)
+
+// Yanıt zarfı: alan adı -> örnek değer (yalnızca tipi kullanılır).
+// Adı "?" ile biten alanlar her yanıtta bulunmaz.
+type Fields map[string]interface{}
+
+// Belgelenen bir route. Path gin biçimindedir (/posts/:id); Query form
+// etiketli bir struct, Body ve Response ise JSON'a yazılan tiplerdir.
+type Route struct {
+	Method  string
+	Path    string
+	Tag     string
+	Summary string
+
+	// Public route'lar Authorization başlığı istemez
+	Public bool
+	Query  interface{}
+	// ?limit= ve ?cursor= ile keyset sayfalama
+	Cursor bool
+	Body   interface{}
+
+	// Başarılı yanıt kodu; boşsa 200
+	Status   int
+	Response interface{}
+	// JSON dışı yanıtın içerik tipi (örn. application/zip)
+	Produces string
+}
+
+// Kimlik doğrulaması Authorization: Bearer <jwt> ile yapılır
+const bearerAuth = "bearerAuth"
+
+var pathParam = regexp.MustCompile(`[:*]([A-Za-z_]+)`)
+
+// Gin yolunu OpenAPI biçimine çevirir: /posts/:id -> /posts/{id}
+func Path(ginPath string) string {
+	return pathParam.ReplaceAllString(ginPath, "{$1}")
+}
+
+// Route listesinden belgeyi üretir
+func Build(info Info, routes []Route) *Document {
+	s := newSchemas()
+	problem := s.of(apierror.Problem{})
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   make(map[string]map[string]*Operation),
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+		Security: []SecurityRequirement{{bearerAuth: {}}},
+	}
+
+	seenTags := make(map[string]bool)
+	for _, route := range routes {
+		path := Path(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*Operation)
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = operation(s, route, problem)
+
+		if route.Tag != "" && !seenTags[route.Tag] {
+			seenTags[route.Tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: route.Tag})
+		}
+	}
+
+	doc.Components.Schemas = s.components
+	return doc
+}
+
+func operation(s *schemas, route Route, problem *Schema) *Operation {
+	op := &Operation{
+		Summary:     route.Summary,
+		OperationID: operationID(route),
+		Responses:   make(map[string]Response),
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+	if route.Public {
+		op.Security = &[]SecurityRequirement{}
+	}
+
+	for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+		schema := &Schema{Type: "string"}
+		if match[1] == "id" {
+			schema = &Schema{Type: "integer", Format: "int32"}
+		}
+		op.Parameters = append(op.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: schema})
+	}
+	if route.Query != nil {
+		op.Parameters = append(op.Parameters, queryParams(s, reflect.TypeOf(route.Query))...)
+	}
+	if route.Cursor {
+		op.Parameters = append(op.Parameters, cursorParams()...)
+	}
+
+	if route.Body != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: s.inputOf(route.Body)}},
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	response := Response{Description: http.StatusText(status)}
+	switch {
+	case route.Produces != "":
+		schema := &Schema{Type: "string"}
+		if !strings.HasPrefix(route.Produces, "text/") {
+			schema.Format = "binary"
+		}
+		response.Content = map[string]MediaType{route.Produces: {Schema: schema}}
+	case route.Response != nil:
+		response.Content = map[string]MediaType{"application/json": {Schema: s.of(route.Response)}}
+	}
+	if status >= 300 && status < 400 {
+		response.Headers = map[string]Header{"Location": {Schema: &Schema{Type: "string", Format: "uri"}}}
+	}
+	op.Responses[strconv.Itoa(status)] = response
+
+	// Tüm hatalar problem+json olarak döner
+	op.Responses["default"] = Response{
+		Description: "Error",
+		Content:     map[string]MediaType{apierror.ContentType: {Schema: problem}},
+	}
+	return op
+}
+
+// Query struct'ının form etiketli alanları; gömülü struct'lar açılır
+func queryParams(s *schemas, t reflect.Type) []Parameter {
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			params = append(params, queryParams(s, field.Type)...)
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		schema := s.typeOf(fieldType)
+		required := applyRules(schema, field.Tag.Get("binding"))
+		if value, ok := strings.CutPrefix(opts, "default="); ok {
+			schema.Default = defaultValue(schema.Type, value)
+		}
+
+		params = append(params, Parameter{Name: name, In: "query", Required: required, Schema: schema})
+	}
+	return params
+}
+
+func defaultValue(schemaType, value string) interface{} {
+	switch schemaType {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+func cursorParams() []Parameter {
+	minLimit, maxLimit := float64(1), float64(pagination.MaxLimit)
+	return []Parameter{
+		{Name: "limit", In: "query", Schema: &Schema{
+			Type: "integer", Format: "int32", Minimum: &minLimit, Maximum: &maxLimit, Default: pagination.DefaultLimit,
+		}},
+		{Name: "cursor", In: "query", Schema: &Schema{Type: "string"}},
+	}
+}
+
+// GET /api/posts/:id/comments -> getPostsIdComments
+func operationID(route Route) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(route.Method))
+	for _, part := range strings.FieldsFunc(route.Path, func(r rune) bool {
+		return r == '/' || r == ':' || r == '*' || r == '-' || r == '_'
+	}) {
+		if part == "api" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
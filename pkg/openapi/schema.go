@@ -0,0 +1,236 @@
+// pkg/openapi/schema.go
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// Go tiplerinden şema üretir. İsimli struct'lar components altına bir kez
+// yazılır ve $ref ile kullanılır; farklı paketlerde aynı adı taşıyan
+// tiplerin adına paket adı eklenir.
+type schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+
+	// İstek gövdesi ve query struct'larında zorunlu alanlar binding
+	// etiketinden, yanıtlarda omitempty olmamasından anlaşılır
+	input bool
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// Değerin şeması; Fields zarf olarak, diğerleri tipine göre yazılır
+func (s *schemas) of(v interface{}) *Schema {
+	if fields, ok := v.(Fields); ok {
+		return s.fields(fields)
+	}
+	return s.typeOf(reflect.TypeOf(v))
+}
+
+func (s *schemas) fields(fields Fields) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema, len(fields))}
+	for name, v := range fields {
+		name, optional := strings.CutSuffix(name, "?")
+		schema.Properties[name] = s.of(v)
+		if !optional {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// İstek tarafındaki tipin şeması
+func (s *schemas) inputOf(v interface{}) *Schema {
+	s.input = true
+	defer func() { s.input = false }()
+	return s.of(v)
+}
+
+func (s *schemas) typeOf(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(s.typeOf(t.Elem()))
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.typeOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.typeOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + s.component(t)}
+	}
+	// interface{} ve diğerleri: herhangi bir değer
+	return &Schema{}
+}
+
+func (s *schemas) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := s.components[name]; taken {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+	}
+	s.names[t] = name
+	// Kendine referans veren tiplerde sonsuz döngü olmasın diye önce yer ayrılır
+	s.components[name] = &Schema{}
+	*s.components[name] = *s.object(t)
+	return name
+}
+
+// Struct alanları; gömülü struct'ların alanları üst seviyeye alınır
+func (s *schemas) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	s.addFields(schema, t)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+func (s *schemas) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				s.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := s.typeOf(field.Type)
+		required := applyRules(property, field.Tag.Get("binding"))
+		schema.Properties[name] = property
+
+		if !s.input {
+			required = !strings.Contains(opts, "omitempty")
+		}
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// Gin'in binding etiketindeki doğrulama kurallarını şemaya yansıtır ve
+// alanın zorunlu olup olmadığını döner. dive sonrasındaki kurallar
+// elemanlara ait olduğu için okunmaz.
+func applyRules(schema *Schema, tag string) (required bool) {
+	for _, rule := range strings.Split(tag, ",") {
+		key, param, _ := strings.Cut(rule, "=")
+		switch key {
+		case "dive":
+			return required
+		case "required":
+			required = true
+		case "email":
+			schema.Format = "email"
+		case "url", "http_url":
+			schema.Format = "uri"
+		case "oneof":
+			schema.Enum = strings.Fields(param)
+		case "min", "gte":
+			setBound(schema, param, true)
+		case "max", "lte":
+			setBound(schema, param, false)
+		}
+	}
+	return required
+}
+
+// min/max metinde uzunluk, listede eleman sayısı, sayıda değer sınırıdır
+func setBound(schema *Schema, param string, lower bool) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	count := int(n)
+
+	switch schema.Type {
+	case "string":
+		if lower {
+			schema.MinLength = &count
+		} else {
+			schema.MaxLength = &count
+		}
+	case "array":
+		if lower {
+			schema.MinItems = &count
+		} else {
+			schema.MaxItems = &count
+		}
+	case "integer", "number":
+		if lower {
+			schema.Minimum = &n
+		} else {
+			schema.Maximum = &n
+		}
+	}
+}
+
+// OpenAPI 3.0'da $ref yanında başka anahtar yazılamadığı için allOf ile sarılır
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+	}
+	schema.Nullable = true
+	return schema
+}
@@ -0,0 +1,153 @@
+// pkg/pagination/pagination.go
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 50
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Son gösterilen kaydın sıralama anahtarı. Sıralama sütunu zaman ya da
+// tam sayı olabilir; ID eşit değerlerde sırayı belirler.
+type Cursor struct {
+	Time  *time.Time `json:"t,omitempty"`
+	Value *int64     `json:"v,omitempty"`
+	ID    uint       `json:"id"`
+}
+
+func TimeKey(t time.Time, id uint) Cursor {
+	return Cursor{Time: &t, ID: id}
+}
+
+func ValueKey(v int64, id uint) Cursor {
+	return Cursor{Value: &v, ID: id}
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func Decode(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || (cursor.Time == nil) == (cursor.Value == nil) {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+func (c *Cursor) key() interface{} {
+	if c.Time != nil {
+		return *c.Time
+	}
+	return *c.Value
+}
+
+// Keyset sıralaması: Column ve IDColumn birlikte benzersiz bir sıra oluşturur
+type Order struct {
+	Column    string
+	IDColumn  string
+	Ascending bool
+}
+
+// İstekten okunan sayfalama parametreleri
+type Params struct {
+	Limit int
+	After *Cursor
+}
+
+// limit değerini sunucu sınırları içine çeker
+func ClampLimit(limit int) int {
+	if limit < 1 {
+		return DefaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
+
+// ?limit= ve ?cursor= parametrelerini okur
+func Parse(c *gin.Context) (Params, error) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	params := Params{Limit: ClampLimit(limit)}
+
+	if value := c.Query("cursor"); value != "" {
+		cursor, err := Decode(value)
+		if err != nil {
+			return params, err
+		}
+		params.After = cursor
+	}
+	return params, nil
+}
+
+// Sorguya keyset koşulu, sıralama ve bir fazla kayıt limiti ekler.
+// Fazladan gelen kayıt sonraki sayfanın varlığını gösterir.
+func (p Params) Apply(db *gorm.DB, order Order) *gorm.DB {
+	direction, op := "DESC", "<"
+	if order.Ascending {
+		direction, op = "ASC", ">"
+	}
+
+	if p.After != nil {
+		db = db.Where("("+order.Column+", "+order.IDColumn+") "+op+" (?, ?)", p.After.key(), p.After.ID)
+	}
+
+	return db.Order(order.Column + " " + direction + ", " + order.IDColumn + " " + direction).
+		Limit(p.Limit + 1)
+}
+
+// İstemciye dönen sayfa bilgisi
+type Page struct {
+	Limit      int     `json:"limit"`
+	NextCursor *string `json:"nextCursor"`
+}
+
+// Apply ile çekilen kayıtları sayfa boyutuna indirir ve sonraki cursor'ı üretir
+func Paginate[T any](items []T, p Params, key func(T) Cursor) ([]T, Page) {
+	page := Page{Limit: p.Limit}
+	if len(items) <= p.Limit {
+		return items, page
+	}
+
+	items = items[:p.Limit]
+	next := key(items[len(items)-1]).Encode()
+	page.NextCursor = &next
+	return items, page
+}
+
+// Sayfa numarasıyla gezilen listelerde (arama, ürünler, moderasyon)
+// istemciye dönen sayfa bilgisi
+type Numbered struct {
+	Current int   `json:"current"`
+	Limit   int   `json:"limit"`
+	Total   int64 `json:"total"`
+	Pages   int64 `json:"pages"`
+}
+
+func NumberedPage(current, limit int, total int64) Numbered {
+	return Numbered{
+		Current: current,
+		Limit:   limit,
+		Total:   total,
+		Pages:   (total + int64(limit) - 1) / int64(limit),
+	}
+}
@@ -0,0 +1,73 @@
+// pkg/tracing/tracing.go
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/sefazor/comfyn/configs"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Uygulamanın span'lerini oluşturan tracer'ın adı
+const instrumentationName = "github.com/sefazor/comfyn"
+
+// Tracing kapalıyken de çağrılabilir; global provider no-op kaldığı için
+// span'ler kaydedilmez
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Tracing açıksa span'leri OTLP/HTTP ile toplayıcıya gönderen provider'ı kurar.
+// Dönen fonksiyon kapanışta bekleyen span'leri gönderir.
+func Setup(ctx context.Context, cfg configs.TracingConfig) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create OTLP exporter: %w", err)
+	}
+
+	provider := Install(cfg, sdktrace.NewBatchSpanProcessor(exporter))
+	return provider.Shutdown, nil
+}
+
+// Verilen işlemciyle global provider'ı ve W3C trace context yayılımını kurar.
+// Testler sdktrace.NewSimpleSpanProcessor(tracetest.NewInMemoryExporter())
+// vererek span'leri bellekte toplayabilir.
+func Install(cfg configs.TracingConfig, processor sdktrace.SpanProcessor) *sdktrace.TracerProvider {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+	return provider
+}
+
+// Dışarıya yapılan HTTP isteklerini span'le sarar ve trace context'i
+// isteğe ekler; base nil ise http.DefaultTransport kullanılır
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
@@ -0,0 +1,102 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sefazor/comfyn/configs"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span'leri bellekte toplayan global provider kurar
+func installTestProvider(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := Install(configs.TracingConfig{ServiceName: "comfyn-test", SampleRatio: 1}, sdktrace.NewSimpleSpanProcessor(exporter))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	return exporter
+}
+
+func TestTransportPropagatesSpan(t *testing.T) {
+	exporter := installTestProvider(t)
+
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	ctx, span := Tracer().Start(context.Background(), "request")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := (&http.Client{Transport: Transport(nil)}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	span.End()
+
+	// Karşı taraf aynı trace'e bağlanabilmeli
+	remote := trace.SpanContextFromContext(otel.GetTextMapPropagator().Extract(context.Background(),
+		propagation.MapCarrier{"traceparent": received}))
+	if remote.TraceID() != span.SpanContext().TraceID() {
+		t.Fatalf("traceparent = %q, want trace %s", received, span.SpanContext().TraceID())
+	}
+
+	// Dış çağrının span'i isteğin span'inin altında kaydedilir
+	var client *tracetest.SpanStub
+	spans := exporter.GetSpans()
+	for i := range spans {
+		if spans[i].SpanKind == trace.SpanKindClient {
+			client = &spans[i]
+		}
+	}
+	if client == nil {
+		t.Fatalf("no client span recorded, got %d spans", len(spans))
+	}
+	if client.Parent.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("client span parent = %s, want %s", client.Parent.SpanID(), span.SpanContext().SpanID())
+	}
+}
+
+// Kuyruk işlerinin kaydettiği traceparent ile iş span'i isteğe bağlanır
+func TestTraceParentRoundTrip(t *testing.T) {
+	exporter := installTestProvider(t)
+
+	ctx, request := Tracer().Start(context.Background(), "request")
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	request.End()
+
+	if carrier.Get("traceparent") == "" {
+		t.Fatal("traceparent not injected from a context with a span")
+	}
+
+	parent := trace.SpanContextFromContext(otel.GetTextMapPropagator().Extract(context.Background(), carrier))
+	_, job := Tracer().Start(context.Background(), "job", trace.WithLinks(trace.Link{SpanContext: parent}))
+	job.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	links := spans[1].Links
+	if len(links) != 1 || links[0].SpanContext.SpanID() != request.SpanContext().SpanID() {
+		t.Errorf("job span links = %+v, want a link to the request span", links)
+	}
+
+	// Span'i olmayan context (ör. context.Background) trace taşımaz
+	empty := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(context.Background(), empty)
+	if value := empty.Get("traceparent"); value != "" {
+		t.Errorf("traceparent = %q from a context without a span, want empty", value)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sefazor/comfyn/internal/account"
+	"github.com/sefazor/comfyn/internal/affiliate/link"
+	"github.com/sefazor/comfyn/internal/auth"
+	"github.com/sefazor/comfyn/internal/digest"
+	"github.com/sefazor/comfyn/internal/export"
+	"github.com/sefazor/comfyn/internal/health"
+	"github.com/sefazor/comfyn/internal/models"
+	"github.com/sefazor/comfyn/internal/moderation"
+	"github.com/sefazor/comfyn/internal/notification"
+	"github.com/sefazor/comfyn/internal/post"
+	"github.com/sefazor/comfyn/internal/product"
+	"github.com/sefazor/comfyn/internal/push"
+	"github.com/sefazor/comfyn/internal/search"
+	usersvc "github.com/sefazor/comfyn/internal/user"
+	"github.com/sefazor/comfyn/pkg/apierror"
+	"github.com/sefazor/comfyn/pkg/metrics"
+	"github.com/sefazor/comfyn/pkg/middleware"
+	"github.com/sefazor/comfyn/pkg/openapi"
+)
+
+// Route'ların bağlandığı handler'lar. "openapi check" komutu router'ı
+// boş değerle kurar; handler'lar yalnızca istek gelince çağrıldığı için
+// bu güvenlidir.
+type handlers struct {
+	users        usersvc.Store
+	auth         *auth.Handler
+	user         *usersvc.Handler
+	post         *post.Handler
+	notification *notification.Handler
+	link         *link.Handler
+	product      *product.Handler
+	search       *search.Handler
+	push         *push.Handler
+	account      *account.Handler
+	export       *export.Handler
+	digest       *digest.Handler
+	moderation   *moderation.Handler
+	health       *health.Handler
+}
+
+func newRouter(h handlers) (*gin.Engine, error) {
+	spec, err := openapi.Handler(openapi.Build(apiInfo, apiRoutes))
+	if err != nil {
+		return nil, err
+	}
+
+	// Recovery en içte: panikler 500'e çevrildikten sonra log, metrik ve
+	// span'e de yansır
+	r := gin.New()
+	r.Use(
+		middleware.RequestID(), middleware.Tracing(), middleware.AccessLog(), middleware.Metrics(),
+		gin.CustomRecovery(func(c *gin.Context, recovered any) {
+			c.Error(fmt.Errorf("panic: %v", recovered))
+			apierror.Internal(c, "Internal server error")
+		}),
+	)
+	r.NoRoute(func(c *gin.Context) { apierror.NotFound(c, "Route not found") })
+
+	// Health ve metrik routes
+	r.GET("/healthz", h.health.LivenessHandler)
+	r.GET("/readyz", h.health.ReadinessHandler)
+	r.GET("/metrics", metrics.Handler())
+
+	// API belgesi
+	r.GET("/openapi.json", spec)
+	r.GET("/docs", openapi.SwaggerUI(apiInfo.Title, "/openapi.json"))
+
+	// Public routes
+	r.POST("/api/auth/register", h.auth.RegisterHandler)
+	r.POST("/api/auth/login", h.auth.LoginHandler)
+	r.GET("/go/:tracking_id", h.link.RedirectHandler)
+	r.GET("/api/email/unsubscribe", h.digest.UnsubscribeHandler)
+	r.POST("/api/email/unsubscribe", h.digest.UnsubscribeHandler)
+	r.GET("/api/exports/:id/download", h.export.DownloadExportHandler)
+
+	// Protected routes
+	protected := r.Group("/api")
+	protected.Use(middleware.AuthMiddleware(h.users))
+	{
+		// User routes
+		protected.GET("/users/me", h.user.GetProfileHandler)
+		protected.POST("/users/me/deactivate", h.account.DeactivateAccountHandler)
+		protected.DELETE("/users/me", h.account.DeleteAccountHandler)
+		protected.GET("/users/me/saved", h.post.GetSavedPostsHandler)
+		protected.POST("/users/me/export", h.export.RequestExportHandler)
+		protected.GET("/users/me/exports", h.export.GetExportsHandler)
+		protected.GET("/users/:id", h.user.GetUserProfileHandler)
+		protected.PUT("/users/profile", h.user.UpdateProfileHandler)
+		protected.PUT("/users/security", h.user.UpdateSecurityHandler)
+		protected.POST("/users/:id/follow", h.user.FollowUserHandler)
+		protected.GET("/users/:id/followers", h.user.GetFollowersHandler)
+		protected.GET("/users/:id/following", h.user.GetFollowingHandler)
+		protected.GET("/users/search", h.user.SearchUsersHandler)
+		protected.POST("/users/:id/block", h.user.BlockUserHandler)
+		protected.POST("/users/:id/mute", h.user.MuteUserHandler)
+		protected.GET("/users/blocked", h.user.GetBlockedUsersHandler)
+		protected.GET("/users/muted", h.user.GetMutedUsersHandler)
+
+		// Follow request routes
+		protected.GET("/follow-requests", h.user.GetFollowRequestsHandler)
+		protected.POST("/follow-requests/:id/approve", h.user.ApproveFollowRequestHandler)
+		protected.POST("/follow-requests/:id/reject", h.user.RejectFollowRequestHandler)
+
+		// Post routes
+		protected.POST("/posts", h.post.CreatePostHandler)
+		protected.GET("/posts", h.post.ListPostsHandler)
+		protected.GET("/posts/:id", h.post.GetPostHandler)
+		protected.PUT("/posts/:id", h.post.UpdatePostHandler)
+		protected.DELETE("/posts/:id", h.post.DeletePostHandler)
+		protected.POST("/posts/:id/like", h.post.LikePostHandler)
+		protected.POST("/posts/:id/save", h.post.SavePostHandler)
+		protected.GET("/posts/:id/comments", h.post.GetCommentsHandler)
+		protected.POST("/posts/:id/comment", h.post.CreateCommentHandler)
+		protected.POST("/posts/:id/view", h.post.IncrementViewHandler)
+
+		// Feed routes
+		protected.GET("/feed", h.post.GetPersonalFeedHandler)
+		protected.GET("/feed/suggested", h.post.GetSuggestedPostsHandler)
+
+		// Hashtag routes
+		protected.GET("/posts/hashtag/:tag", h.post.SearchPostsByHashtagHandler)
+		protected.GET("/hashtags/trending", h.post.GetTrendingHashtagsHandler)
+
+		// Product routes
+		protected.GET("/products", h.product.ListProductsHandler)
+
+		// Search routes
+		protected.GET("/search/posts", h.search.SearchPostsHandler)
+
+		// Notification routes
+		protected.GET("/notifications", h.notification.GetNotificationsHandler)
+		protected.PUT("/notifications/:id/read", h.notification.MarkNotificationReadHandler)
+		protected.PUT("/notifications/preferences", h.notification.UpdateNotificationPreferencesHandler)
+
+		// Push cihaz routes
+		protected.POST("/devices", h.push.RegisterDeviceHandler)
+		protected.DELETE("/devices/:token", h.push.UnregisterDeviceHandler)
+
+		protected.GET("/analytics/links", h.link.GetLinkAnalyticsHandler)
+
+		protected.GET("/analytics/clicks", h.link.GetClickStatsHandler)
+
+		// Report routes
+		protected.POST("/posts/:id/report", h.moderation.ReportPostHandler)
+		protected.POST("/comments/:id/report", h.moderation.ReportCommentHandler)
+		protected.POST("/users/:id/report", h.moderation.ReportUserHandler)
+	}
+
+	// Admin routes
+	admin := r.Group("/api/admin")
+	admin.Use(middleware.AuthMiddleware(h.users), middleware.RequireRole(models.RoleModerator, models.RoleAdmin))
+	{
+		admin.GET("/reports", h.moderation.GetModerationQueueHandler)
+		admin.GET("/reports/:type/:id", h.moderation.GetTargetReportsHandler)
+		admin.POST("/reports/:type/:id/action", h.moderation.TakeActionHandler)
+		admin.GET("/moderation-actions", h.moderation.GetModerationActionsHandler)
+		admin.POST("/users/:id/suspend", h.account.SuspendUserHandler)
+		admin.POST("/users/:id/unsuspend", h.account.UnsuspendUserHandler)
+	}
+
+	return r, nil
+}